@@ -1,37 +1,109 @@
 package api
 
 import (
+	"context"
 	"time"
 
 	"github.com/franciscosbf/spotify-tui/internals/auth"
 	"github.com/franciscosbf/spotify-tui/internals/browser"
 )
 
-const verificationTimeout = time.Second * 15
+const DefaultVerificationTimeout = time.Minute * 2
 
-func GenerateToken(clientId string) (auth.Token, error) {
+type Authorization struct {
+	clientId     string
+	codeVerifier string
+	redirectUri  string
+	codeAuth     auth.CodeAuth
+	callback     *auth.CallbackServer
+}
+
+func newAuthorization(clientId, redirectUri string, scopes []string, callback *auth.CallbackServer) Authorization {
 	codeVerifier := auth.GenCodeVerifier()
 	codeChallenge := auth.GenCodeChallenge(codeVerifier)
 
-	codeAuth := auth.BuildCodeAuth(clientId, codeChallenge)
+	codeAuth := auth.BuildCodeAuth(clientId, codeChallenge, redirectUri, scopes)
+
+	return Authorization{clientId, codeVerifier, redirectUri, codeAuth, callback}
+}
+
+func NewAuthorization(clientId string, redirect auth.Redirect, scopes []string) (Authorization, error) {
+	callback, err := auth.ListenCallback(redirect)
+	if err != nil {
+		return Authorization{}, err
+	}
 
-	if err := browser.OpenAuthLink(codeAuth.Url); err != nil {
+	return newAuthorization(clientId, callback.RedirectUri(), scopes, callback), nil
+}
+
+func NewManualAuthorization(clientId string, redirect auth.Redirect, scopes []string) Authorization {
+	return newAuthorization(clientId, redirect.Uri(redirect.Ports[0]), scopes, nil)
+}
+
+func (a Authorization) Url() string {
+	return a.codeAuth.Url
+}
+
+func (a Authorization) RedirectUri() string {
+	return a.redirectUri
+}
+
+func (a Authorization) OpenBrowser() error {
+	return browser.OpenAuthLink(a.codeAuth.Url)
+}
+
+func (a Authorization) WaitForToken(ctx context.Context) (auth.Token, error) {
+	defer a.Close()
+
+	code, err := a.callback.WaitForCode(ctx, a.codeAuth.State)
+	if err != nil {
 		return auth.Token{}, err
 	}
 
-	code, err := auth.WaitForCode(codeAuth.State, verificationTimeout)
+	return auth.FetchToken(a.clientId, a.codeVerifier, code, a.redirectUri)
+}
+
+func (a Authorization) TokenFromRedirect(redirectUrl string) (auth.Token, error) {
+	code, err := auth.ParseRedirectUrl(redirectUrl, a.codeAuth.State)
 	if err != nil {
 		return auth.Token{}, err
 	}
 
-	token, err := auth.FetchToken(clientId, codeVerifier, code)
+	return auth.FetchToken(a.clientId, a.codeVerifier, code, a.redirectUri)
+}
+
+func (a Authorization) Close() {
+	if a.callback != nil {
+		a.callback.Close()
+	}
+}
+
+func GenerateToken(clientId string, redirect auth.Redirect) (auth.Token, error) {
+	authorization, err := NewAuthorization(clientId, redirect, nil)
 	if err != nil {
 		return auth.Token{}, err
 	}
 
-	return token, nil
+	if err := authorization.OpenBrowser(); err != nil {
+		authorization.Close()
+		return auth.Token{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultVerificationTimeout)
+	defer cancel()
+
+	return authorization.WaitForToken(ctx)
 }
 
-func RegenerateToken(clientId, refreshToken string) (auth.Token, error) {
-	return auth.RefreshToken(clientId, refreshToken)
+func RegenerateToken(clientId string, previous auth.Token) (auth.Token, error) {
+	token, err := auth.RefreshToken(clientId, previous.Refresh)
+	if err != nil {
+		return auth.Token{}, err
+	}
+
+	if len(token.Scopes) == 0 {
+		token.Scopes = previous.Scopes
+	}
+
+	return token, nil
 }
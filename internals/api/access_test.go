@@ -3,18 +3,19 @@ package api
 import (
 	"testing"
 
+	"github.com/franciscosbf/spotify-tui/internals/auth"
 	"github.com/franciscosbf/spotify-tui/internals/internalstest"
 )
 
 func TestGenAndRegenToken(t *testing.T) {
 	clientId := internalstest.GetClientId(t)
 
-	token, err := GenerateToken(clientId)
+	token, err := GenerateToken(clientId, auth.DefaultRedirect)
 	if err != nil {
 		t.Fatalf("failed to generate token: %s", err)
 	}
 
-	_, err = RegenerateToken(clientId, token.Refresh)
+	_, err = RegenerateToken(clientId, token)
 	if err != nil {
 		t.Fatalf("failed to regenerate token: %s", err)
 	}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/imroc/req/v3"
 
@@ -23,19 +26,47 @@ var (
 	shuffleEndpoint  string
 	repeatEndpoint   string
 	volumeEndpoint   string
+	seekEndpoint     string
+	queueEndpoint    string
+	searchEndpoint   string
+	tracksEndpoint   string
+	recentEndpoint   string
+	topEndpoint      string
+	usersEndpoint    string
+	artistsEndpoint  string
+	playlistEndpoint string
+	albumsEndpoint   string
+
+	myPlaylistsEndpoint string
+	savedTracksEndpoint string
+	savedAlbumsEndpoint string
+	followingEndpoint   string
 )
 
-func endpoint(base, path string) string {
-	e, _ := url.JoinPath(base, path)
+func endpoint(base string, path ...string) string {
+	e, _ := url.JoinPath(base, path...)
 
 	return e
 }
 
 func init() {
-	profileEndpoint = endpoint(uri.API, "v1/me")
-
-	playerEndpoint = endpoint(uri.API, "v1/me/player")
-
+	profileEndpoint = "/v1/me"
+	searchEndpoint = "/v1/search"
+	tracksEndpoint = "/v1/tracks"
+	usersEndpoint = "/v1/users"
+	artistsEndpoint = "/v1/artists"
+	playlistEndpoint = "/v1/playlists"
+	albumsEndpoint = "/v1/albums"
+
+	topEndpoint = endpoint(profileEndpoint, "top")
+	myPlaylistsEndpoint = endpoint(profileEndpoint, "playlists")
+	savedTracksEndpoint = endpoint(profileEndpoint, "tracks")
+	savedAlbumsEndpoint = endpoint(profileEndpoint, "albums")
+	followingEndpoint = endpoint(profileEndpoint, "following")
+
+	playerEndpoint = endpoint(profileEndpoint, "player")
+
+	devicesEndpoint = endpoint(playerEndpoint, "devices")
 	playEndpoint = endpoint(playerEndpoint, "play")
 	pauseEndpoint = endpoint(playerEndpoint, "pause")
 	previousEndpoint = endpoint(playerEndpoint, "previous")
@@ -43,26 +74,111 @@ func init() {
 	shuffleEndpoint = endpoint(playerEndpoint, "shuffle")
 	repeatEndpoint = endpoint(playerEndpoint, "repeat")
 	volumeEndpoint = endpoint(playerEndpoint, "volume")
+	seekEndpoint = endpoint(playerEndpoint, "seek")
+	queueEndpoint = endpoint(playerEndpoint, "queue")
+	recentEndpoint = endpoint(playerEndpoint, "recently-played")
 }
 
-var ErrRequestFailed = errors.New("failed to send request")
+var (
+	ErrRequestFailed  = errors.New("failed to send request")
+	ErrNoActiveDevice = errors.New("no active device")
+	ErrInvalidCursor  = errors.New("only one of the before and after cursors can be set")
+	ErrInvalidRange   = errors.New("invalid time range")
+)
+
+const (
+	playlistBatch = 100
+	playlistsPage = 50
+	libraryPage   = 50
+	albumsBatch   = 20
+
+	rateLimitRetries = 5
+	maxRetryAfter    = time.Minute
+)
+
+type Player interface {
+	GetUserProfile() (UserProfile, error)
+	GetPlaybackState() (PlaybackState, error)
+	GetDevices() ([]Device, error)
+	TransferPlayback(deviceId string, play bool) error
+	Resume() error
+	StartPlayback(options PlayOptions) error
+	Pause() error
+	SkipToPrevious() error
+	SkipToNext() error
+	EnableShuffle() error
+	DisableShuffle() error
+	SetRepeatTrack() error
+	SetRepeatContext() error
+	DisableRepeat() error
+	SetVolume(percent int) error
+	Seek(positionMs int) error
+	GetQueue() (Queue, error)
+	AddToQueue(uri string) error
+}
 
 type Client struct {
-	cli   *req.Client
-	token string
+	cli         *req.Client
+	mu          sync.RWMutex
+	token       string
+	onRateLimit func(wait time.Duration)
 }
 
 func NewClient() *Client {
-	client := req.C()
+	return NewClientWithBase(uri.API)
+}
+
+func retryAfter(resp *req.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.GetHeader("Retry-After"))
+	if err != nil || seconds < 0 {
+		return time.Second
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+func NewClientWithBase(base string) *Client {
+	c := &Client{}
+
+	c.cli = req.C().
+		SetBaseURL(base).
+		SetCommonRetryCount(rateLimitRetries).
+		SetCommonRetryCondition(func(resp *req.Response, err error) bool {
+			return resp.Response != nil &&
+				resp.StatusCode == http.StatusTooManyRequests &&
+				retryAfter(resp) <= maxRetryAfter
+		}).
+		SetCommonRetryInterval(func(resp *req.Response, _ int) time.Duration {
+			return retryAfter(resp)
+		}).
+		SetCommonRetryHook(func(resp *req.Response, _ error) {
+			c.mu.RLock()
+			hook := c.onRateLimit
+			c.mu.RUnlock()
+
+			if hook != nil {
+				hook(retryAfter(resp))
+			}
+		})
+
+	return c
+}
+
+func (c *Client) OnRateLimit(hook func(wait time.Duration)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	return &Client{client, ""}
+	c.onRateLimit = hook
 }
 
 func (c *Client) tokenBearer() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	return fmt.Sprintf("Bearer %s", c.token)
 }
 
-func (c *Client) request(method string, endpoint string, request *req.Request) error {
+func (c *Client) request(method string, endpoint string, request *req.Request) (*req.Response, error) {
 	bearer := c.tokenBearer()
 
 	var er struct {
@@ -74,32 +190,40 @@ func (c *Client) request(method string, endpoint string, request *req.Request) e
 		SetErrorResult(&er)
 
 	resp, err := request.Send(method, endpoint)
-	if err != nil {
-		return ErrRequestFailed
+	if resp.Response == nil {
+		return nil, ErrRequestFailed
 	}
 
 	if resp.IsErrorState() {
-		return er.Error
+		if er.Error.Status == 0 {
+			er.Error = ErrResponse{Message: http.StatusText(resp.StatusCode), Status: resp.StatusCode}
+		}
+
+		return nil, er.Error
 	}
 
-	return nil
+	if err != nil {
+		return nil, ErrRequestFailed
+	}
+
+	return resp, nil
 }
 
 func (c *Client) simpleRequest(method, endpoint string) error {
 	request := c.cli.R()
 
-	if err := c.request(method, endpoint, request); err != nil {
+	if _, err := c.request(method, endpoint, request); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Client) stateRequest(endpoint, state string) error {
+func (c *Client) queryRequest(endpoint, param, value string) error {
 	request := c.cli.R().
-		SetQueryParam("state", state)
+		SetQueryParam(param, value)
 
-	if err := c.request(http.MethodPut, endpoint, request); err != nil {
+	if _, err := c.request(http.MethodPut, endpoint, request); err != nil {
 		return err
 	}
 
@@ -107,11 +231,11 @@ func (c *Client) stateRequest(endpoint, state string) error {
 }
 
 func (c *Client) shuffleRequest(shuffle bool) error {
-	return c.stateRequest(shuffleEndpoint, strconv.FormatBool(shuffle))
+	return c.queryRequest(shuffleEndpoint, "state", strconv.FormatBool(shuffle))
 }
 
 func (c *Client) repeatRequest(mode string) error {
-	return c.stateRequest(repeatEndpoint, mode)
+	return c.queryRequest(repeatEndpoint, "state", mode)
 }
 
 func (c *Client) GetUserProfile() (UserProfile, error) {
@@ -120,17 +244,88 @@ func (c *Client) GetUserProfile() (UserProfile, error) {
 	request := c.cli.R().
 		SetSuccessResult(&profile)
 
-	if err := c.request(http.MethodGet, profileEndpoint, request); err != nil {
+	if _, err := c.request(http.MethodGet, profileEndpoint, request); err != nil {
 		return UserProfile{}, err
 	}
 
 	return profile, nil
 }
 
+func (c *Client) GetPlaybackState() (PlaybackState, error) {
+	var state PlaybackState
+
+	request := c.cli.R().
+		SetSuccessResult(&state)
+
+	resp, err := c.request(http.MethodGet, playerEndpoint, request)
+	if err != nil {
+		return PlaybackState{}, err
+	}
+
+	if resp.StatusCode == http.StatusNoContent {
+		return PlaybackState{}, ErrNoActiveDevice
+	}
+
+	return state, nil
+}
+
+func (c *Client) GetDevices() ([]Device, error) {
+	var devices struct {
+		Devices []Device `json:"devices"`
+	}
+
+	request := c.cli.R().
+		SetSuccessResult(&devices)
+
+	if _, err := c.request(http.MethodGet, devicesEndpoint, request); err != nil {
+		return nil, err
+	}
+
+	return devices.Devices, nil
+}
+
+func (c *Client) TransferPlayback(deviceId string, play bool) error {
+	body := map[string]any{
+		"device_ids": []string{deviceId},
+		"play":       play,
+	}
+
+	request := c.cli.R().
+		SetBodyJsonMarshal(body)
+
+	if _, err := c.request(http.MethodPut, playerEndpoint, request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c *Client) Resume() error {
 	return c.simpleRequest(http.MethodPut, playEndpoint)
 }
 
+func (c *Client) StartPlayback(options PlayOptions) error {
+	body := map[string]any{}
+	if options.ContextUri != "" {
+		body["context_uri"] = options.ContextUri
+	}
+	if len(options.Uris) > 0 {
+		body["uris"] = options.Uris
+	}
+	if options.OffsetUri != "" {
+		body["offset"] = map[string]string{"uri": options.OffsetUri}
+	}
+
+	request := c.cli.R().
+		SetBodyJsonMarshal(body)
+
+	if _, err := c.request(http.MethodPut, playEndpoint, request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c *Client) Pause() error {
 	return c.simpleRequest(http.MethodPut, pauseEndpoint)
 }
@@ -163,6 +358,414 @@ func (c *Client) DisableRepeat() error {
 	return c.repeatRequest("off")
 }
 
+func (c *Client) SetVolume(percent int) error {
+	percent = min(max(percent, 0), 100)
+
+	return c.queryRequest(volumeEndpoint, "volume_percent", strconv.Itoa(percent))
+}
+
+func (c *Client) Seek(positionMs int) error {
+	return c.queryRequest(seekEndpoint, "position_ms", strconv.Itoa(max(positionMs, 0)))
+}
+
+func (c *Client) GetQueue() (Queue, error) {
+	var queue Queue
+
+	request := c.cli.R().
+		SetSuccessResult(&queue)
+
+	if _, err := c.request(http.MethodGet, queueEndpoint, request); err != nil {
+		return Queue{}, err
+	}
+
+	return queue, nil
+}
+
+func (c *Client) AddToQueue(uri string) error {
+	request := c.cli.R().
+		SetQueryParam("uri", uri)
+
+	if _, err := c.request(http.MethodPost, queueEndpoint, request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) SearchTracks(query string, limit int) ([]Track, error) {
+	var result struct {
+		Tracks struct {
+			Items []Track `json:"items"`
+		} `json:"tracks"`
+	}
+
+	request := c.cli.R().
+		SetQueryParam("q", query).
+		SetQueryParam("type", "track").
+		SetQueryParam("limit", strconv.Itoa(limit)).
+		SetSuccessResult(&result)
+
+	if _, err := c.request(http.MethodGet, searchEndpoint, request); err != nil {
+		return nil, err
+	}
+
+	return result.Tracks.Items, nil
+}
+
+func (c *Client) GetTrack(id string) (Track, error) {
+	var track Track
+
+	request := c.cli.R().
+		SetSuccessResult(&track)
+
+	if _, err := c.request(http.MethodGet, endpoint(tracksEndpoint, id), request); err != nil {
+		return Track{}, err
+	}
+
+	return track, nil
+}
+
+func (c *Client) GetRecentlyPlayed(cursors Cursors, limit int) (RecentlyPlayed, error) {
+	if cursors.Before != "" && cursors.After != "" {
+		return RecentlyPlayed{}, ErrInvalidCursor
+	}
+
+	var recent RecentlyPlayed
+
+	request := c.cli.R().
+		SetQueryParam("limit", strconv.Itoa(limit)).
+		SetSuccessResult(&recent)
+
+	if cursors.Before != "" {
+		request.SetQueryParam("before", cursors.Before)
+	}
+	if cursors.After != "" {
+		request.SetQueryParam("after", cursors.After)
+	}
+
+	if _, err := c.request(http.MethodGet, recentEndpoint, request); err != nil {
+		return RecentlyPlayed{}, err
+	}
+
+	return recent, nil
+}
+
+func (c *Client) getTop(kind string, timeRange TimeRange, limit int, result any) error {
+	if !slices.Contains(TimeRanges, timeRange) {
+		return fmt.Errorf("%w: %s", ErrInvalidRange, timeRange)
+	}
+
+	request := c.cli.R().
+		SetQueryParam("time_range", string(timeRange)).
+		SetQueryParam("limit", strconv.Itoa(limit)).
+		SetSuccessResult(result)
+
+	if _, err := c.request(http.MethodGet, endpoint(topEndpoint, kind), request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) GetTopTracks(timeRange TimeRange, limit int) ([]Track, error) {
+	var top struct {
+		Items []Track `json:"items"`
+	}
+
+	if err := c.getTop("tracks", timeRange, limit, &top); err != nil {
+		return nil, err
+	}
+
+	return top.Items, nil
+}
+
+func (c *Client) GetTopArtists(timeRange TimeRange, limit int) ([]Artist, error) {
+	var top struct {
+		Items []Artist `json:"items"`
+	}
+
+	if err := c.getTop("artists", timeRange, limit, &top); err != nil {
+		return nil, err
+	}
+
+	return top.Items, nil
+}
+
+func (c *Client) GetArtistTopTracks(id string) ([]Track, error) {
+	var top struct {
+		Tracks []Track `json:"tracks"`
+	}
+
+	request := c.cli.R().
+		SetQueryParam("market", "from_token").
+		SetSuccessResult(&top)
+
+	if _, err := c.request(http.MethodGet, endpoint(artistsEndpoint, id, "top-tracks"), request); err != nil {
+		return nil, err
+	}
+
+	return top.Tracks, nil
+}
+
+func (c *Client) CreatePlaylist(userId string, details PlaylistDetails) (Playlist, error) {
+	var playlist Playlist
+
+	request := c.cli.R().
+		SetBodyJsonMarshal(details).
+		SetSuccessResult(&playlist)
+
+	if _, err := c.request(http.MethodPost, endpoint(usersEndpoint, userId, "playlists"), request); err != nil {
+		return Playlist{}, err
+	}
+
+	return playlist, nil
+}
+
+func (c *Client) AddPlaylistTracks(playlistId string, uris []string) (string, error) {
+	return c.InsertPlaylistTracks(playlistId, uris, -1)
+}
+
+func (c *Client) ReplacePlaylistTracks(playlistId string, uris []string) (string, error) {
+	var snapshot struct {
+		SnapshotId string `json:"snapshot_id"`
+	}
+
+	first := append([]string{}, uris[:min(len(uris), playlistBatch)]...)
+
+	request := c.cli.R().
+		SetBodyJsonMarshal(map[string]any{"uris": first}).
+		SetSuccessResult(&snapshot)
+
+	if _, err := c.request(http.MethodPut, endpoint(playlistEndpoint, playlistId, "tracks"), request); err != nil {
+		return "", err
+	}
+
+	if len(uris) == len(first) {
+		return snapshot.SnapshotId, nil
+	}
+
+	return c.InsertPlaylistTracks(playlistId, uris[len(first):], -1)
+}
+
+func (c *Client) InsertPlaylistTracks(playlistId string, uris []string, position int) (string, error) {
+	var snapshot struct {
+		SnapshotId string `json:"snapshot_id"`
+	}
+
+	for batch := range slices.Chunk(uris, playlistBatch) {
+		body := map[string]any{"uris": batch}
+		if position >= 0 {
+			body["position"] = position
+			position += len(batch)
+		}
+
+		request := c.cli.R().
+			SetBodyJsonMarshal(body).
+			SetSuccessResult(&snapshot)
+
+		if _, err := c.request(http.MethodPost, endpoint(playlistEndpoint, playlistId, "tracks"), request); err != nil {
+			return "", err
+		}
+	}
+
+	return snapshot.SnapshotId, nil
+}
+
+func (c *Client) RemovePlaylistTracks(playlistId, snapshotId string, uris []string) (string, error) {
+	var snapshot struct {
+		SnapshotId string `json:"snapshot_id"`
+	}
+
+	for batch := range slices.Chunk(uris, playlistBatch) {
+		tracks := make([]map[string]string, 0, len(batch))
+		for _, uri := range batch {
+			tracks = append(tracks, map[string]string{"uri": uri})
+		}
+
+		body := map[string]any{"tracks": tracks}
+		if snapshotId != "" {
+			body["snapshot_id"] = snapshotId
+		}
+
+		request := c.cli.R().
+			SetBodyJsonMarshal(body).
+			SetSuccessResult(&snapshot)
+
+		if _, err := c.request(http.MethodDelete, endpoint(playlistEndpoint, playlistId, "tracks"), request); err != nil {
+			return "", err
+		}
+
+		snapshotId = snapshot.SnapshotId
+	}
+
+	return snapshot.SnapshotId, nil
+}
+
+func (c *Client) ReorderPlaylistTracks(playlistId string, reorder Reorder) (string, error) {
+	var snapshot struct {
+		SnapshotId string `json:"snapshot_id"`
+	}
+
+	request := c.cli.R().
+		SetBodyJsonMarshal(reorder).
+		SetSuccessResult(&snapshot)
+
+	if _, err := c.request(http.MethodPut, endpoint(playlistEndpoint, playlistId, "tracks"), request); err != nil {
+		return "", err
+	}
+
+	return snapshot.SnapshotId, nil
+}
+
+func (c *Client) ChangePlaylistDetails(playlistId string, details PlaylistDetails) error {
+	request := c.cli.R().
+		SetBodyJsonMarshal(details)
+
+	if _, err := c.request(http.MethodPut, endpoint(playlistEndpoint, playlistId), request); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) GetPlaylist(playlistId string) (Playlist, error) {
+	var playlist Playlist
+
+	request := c.cli.R().
+		SetQueryParam("fields", "id,name,description,uri,public,collaborative,snapshot_id,owner(id,display_name),tracks(total)").
+		SetSuccessResult(&playlist)
+
+	if _, err := c.request(http.MethodGet, endpoint(playlistEndpoint, playlistId), request); err != nil {
+		return Playlist{}, err
+	}
+
+	return playlist, nil
+}
+
+func getPages[T any](c *Client, endpoint string, limit int, params map[string]string) ([]T, error) {
+	items := []T{}
+
+	for offset := 0; ; offset += limit {
+		var page struct {
+			Items []T    `json:"items"`
+			Next  string `json:"next"`
+		}
+
+		request := c.cli.R().
+			SetQueryParams(params).
+			SetQueryParam("limit", strconv.Itoa(limit)).
+			SetQueryParam("offset", strconv.Itoa(offset)).
+			SetSuccessResult(&page)
+
+		if _, err := c.request(http.MethodGet, endpoint, request); err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.Items...)
+		if page.Next == "" || len(page.Items) == 0 {
+			return items, nil
+		}
+	}
+}
+
+func (c *Client) putIds(endpoint string, ids []string, batch int, params map[string]string) error {
+	for chunk := range slices.Chunk(ids, batch) {
+		request := c.cli.R().
+			SetQueryParams(params).
+			SetBodyJsonMarshal(map[string]any{"ids": chunk})
+
+		if _, err := c.request(http.MethodPut, endpoint, request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) GetMyPlaylists() ([]Playlist, error) {
+	return getPages[Playlist](c, myPlaylistsEndpoint, playlistsPage, nil)
+}
+
+func (c *Client) GetPlaylistItems(playlistId string) ([]PlaylistItem, error) {
+	return getPages[PlaylistItem](c, endpoint(playlistEndpoint, playlistId, "tracks"), playlistBatch,
+		map[string]string{"additional_types": "track"})
+}
+
+func (c *Client) GetSavedTracks() ([]SavedTrack, error) {
+	return getPages[SavedTrack](c, savedTracksEndpoint, libraryPage, nil)
+}
+
+func (c *Client) SaveTracks(tracks []TimestampedId) error {
+	for chunk := range slices.Chunk(tracks, libraryPage) {
+		request := c.cli.R().SetBodyJsonMarshal(map[string]any{"timestamped_ids": chunk})
+
+		if _, err := c.request(http.MethodPut, savedTracksEndpoint, request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) GetSavedAlbums() ([]SavedAlbum, error) {
+	return getPages[SavedAlbum](c, savedAlbumsEndpoint, libraryPage, nil)
+}
+
+func (c *Client) SaveAlbums(ids []string) error {
+	return c.putIds(savedAlbumsEndpoint, ids, albumsBatch, nil)
+}
+
+func (c *Client) GetArtistAlbums(artistId string) ([]Album, error) {
+	return getPages[Album](c, endpoint(artistsEndpoint, artistId, "albums"), libraryPage,
+		map[string]string{"include_groups": "album,single"})
+}
+
+func (c *Client) GetAlbumTracks(albumId string) ([]Track, error) {
+	return getPages[Track](c, endpoint(albumsEndpoint, albumId, "tracks"), libraryPage, nil)
+}
+
+func (c *Client) GetFollowedArtists() ([]Artist, error) {
+	artists := []Artist{}
+	after := ""
+
+	for {
+		var page struct {
+			Artists struct {
+				Items   []Artist `json:"items"`
+				Cursors struct {
+					After string `json:"after"`
+				} `json:"cursors"`
+			} `json:"artists"`
+		}
+
+		request := c.cli.R().
+			SetQueryParam("type", "artist").
+			SetQueryParam("limit", strconv.Itoa(libraryPage)).
+			SetSuccessResult(&page)
+		if after != "" {
+			request.SetQueryParam("after", after)
+		}
+
+		if _, err := c.request(http.MethodGet, followingEndpoint, request); err != nil {
+			return nil, err
+		}
+
+		artists = append(artists, page.Artists.Items...)
+
+		after = page.Artists.Cursors.After
+		if after == "" || len(page.Artists.Items) == 0 {
+			return artists, nil
+		}
+	}
+}
+
+func (c *Client) FollowArtists(ids []string) error {
+	return c.putIds(followingEndpoint, ids, libraryPage, map[string]string{"type": "artist"})
+}
+
 func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	c.token = token
 }
@@ -0,0 +1,599 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func fakeApi(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClientWithBase(server.URL)
+	client.SetToken("token")
+
+	return client
+}
+
+func TestGetPlaybackState(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/me/player" {
+			t.Errorf("invalid path. got=%s, expected=/v1/me/player", r.URL.Path)
+		}
+
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("invalid authorization header. got=%s, expected=Bearer token", auth)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"is_playing": true,
+			"progress_ms": 1200,
+			"repeat_state": "context",
+			"device": {"id": "d1", "name": "Desk", "volume_percent": 40},
+			"item": {"name": "Song", "duration_ms": 3000, "artists": [{"name": "Band"}]}
+		}`))
+	})
+
+	state, err := client.GetPlaybackState()
+	if err != nil {
+		t.Fatalf("failed to get playback state: %s", err)
+	}
+
+	if !state.IsPlaying || state.ProgressMs != 1200 || state.RepeatState != "context" {
+		t.Fatalf("invalid state. got=%+v, expected=playing at 1200ms with context repeat", state)
+	}
+
+	if state.Device.Name != "Desk" || state.Device.VolumePercent != 40 {
+		t.Fatalf("invalid device. got=%+v, expected=Desk at 40%%", state.Device)
+	}
+
+	if state.Item.Name != "Song" || state.Item.Artists[0].Name != "Band" {
+		t.Fatalf("invalid item. got=%+v, expected=Song by Band", state.Item)
+	}
+}
+
+func TestGetPlaybackStateNoDevice(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := client.GetPlaybackState(); !errors.Is(err, ErrNoActiveDevice) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrNoActiveDevice)
+	}
+}
+
+func TestErrResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		target error
+	}{
+		{"no active device", http.StatusNotFound,
+			`{"error": {"status": 404, "message": "Player command failed: No active device found", "reason": "NO_ACTIVE_DEVICE"}}`,
+			ErrNoActiveDevice},
+		{"unauthorized", http.StatusUnauthorized,
+			`{"error": {"status": 401, "message": "The access token expired"}}`,
+			ErrUnauthorized},
+		{"unauthorized without body", http.StatusUnauthorized, ``, ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			if err := client.Resume(); !errors.Is(err, tt.target) {
+				t.Fatalf("invalid error. got=%v, expected=%v", err, tt.target)
+			}
+		})
+	}
+}
+
+func TestSetVolume(t *testing.T) {
+	tests := []struct {
+		percent  int
+		expected string
+	}{
+		{35, "35"},
+		{-5, "0"},
+		{120, "100"},
+	}
+
+	for _, tt := range tests {
+		var got string
+
+		client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut || r.URL.Path != "/v1/me/player/volume" {
+				t.Errorf("invalid request. got=%s %s, expected=PUT /v1/me/player/volume", r.Method, r.URL.Path)
+			}
+
+			got = r.URL.Query().Get("volume_percent")
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		if err := client.SetVolume(tt.percent); err != nil {
+			t.Fatalf("failed to set volume: %s", err)
+		}
+
+		if got != tt.expected {
+			t.Fatalf("invalid volume. got=%s, expected=%s", got, tt.expected)
+		}
+	}
+}
+
+func TestTransferPlayback(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/me/player" {
+			t.Errorf("invalid request. got=%s %s, expected=PUT /v1/me/player", r.Method, r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := client.TransferPlayback("d1", false); err != nil {
+		t.Fatalf("failed to transfer playback: %s", err)
+	}
+}
+
+func TestStartPlayback(t *testing.T) {
+	var body map[string]any
+
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/me/player/play" {
+			t.Errorf("invalid request. got=%s %s, expected=PUT /v1/me/player/play", r.Method, r.URL.Path)
+		}
+
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	err := client.StartPlayback(PlayOptions{ContextUri: "spotify:album:a", OffsetUri: "spotify:track:t"})
+	if err != nil {
+		t.Fatalf("failed to start context: %s", err)
+	}
+
+	offset, _ := body["offset"].(map[string]any)
+	if body["context_uri"] != "spotify:album:a" || offset["uri"] != "spotify:track:t" || body["uris"] != nil {
+		t.Fatalf("invalid body. got=%v, expected=the album context with the track offset", body)
+	}
+
+	if err := client.StartPlayback(PlayOptions{Uris: []string{"spotify:track:t"}}); err != nil {
+		t.Fatalf("failed to start tracks: %s", err)
+	}
+
+	if uris, _ := body["uris"].([]any); len(uris) != 1 || body["context_uri"] != nil {
+		t.Fatalf("invalid body. got=%v, expected=one uri without context", body)
+	}
+}
+
+func TestGetRecentlyPlayed(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/me/player/recently-played" {
+			t.Errorf("invalid path. got=%s, expected=/v1/me/player/recently-played", r.URL.Path)
+		}
+
+		query := r.URL.Query()
+		if query.Get("limit") != "2" || query.Get("after") != "" {
+			t.Errorf("invalid query. got=%s, expected=limit=2 without after", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if query.Get("before") == "1714500000000" {
+			w.Write([]byte(`{"items": [], "next": null, "cursors": null, "limit": 2}`))
+			return
+		}
+
+		w.Write([]byte(`{
+			"items": [
+				{"track": {"name": "B", "uri": "spotify:track:b"}, "played_at": "2024-05-01T20:10:00.000Z",
+				 "context": {"type": "playlist", "uri": "spotify:playlist:p"}},
+				{"track": {"name": "A", "uri": "spotify:track:a"}, "played_at": "2024-04-30T18:00:00.000Z", "context": null}
+			],
+			"next": "https://api.spotify.com/v1/me/player/recently-played?before=1714500000000&limit=2",
+			"cursors": {"after": "1714594200000", "before": "1714500000000"},
+			"limit": 2
+		}`))
+	})
+
+	recent, err := client.GetRecentlyPlayed(Cursors{}, 2)
+	if err != nil {
+		t.Fatalf("failed to get recently played: %s", err)
+	}
+
+	if len(recent.Items) != 2 || !recent.HasMore() || recent.Cursors.Before != "1714500000000" {
+		t.Fatalf("invalid page. got=%+v, expected=2 items and more before 1714500000000", recent)
+	}
+
+	first := recent.Items[0]
+	if first.Track.Name != "B" || first.Context.Uri != "spotify:playlist:p" ||
+		!first.PlayedAt.Equal(time.Date(2024, 5, 1, 20, 10, 0, 0, time.UTC)) {
+		t.Fatalf("invalid item. got=%+v, expected=B from the playlist", first)
+	}
+
+	if recent.Items[1].Context.Uri != "" {
+		t.Fatalf("invalid context. got=%+v, expected=none", recent.Items[1].Context)
+	}
+
+	older, err := client.GetRecentlyPlayed(Cursors{Before: recent.Cursors.Before}, 2)
+	if err != nil {
+		t.Fatalf("failed to get older page: %s", err)
+	}
+
+	if len(older.Items) != 0 || older.HasMore() {
+		t.Fatalf("invalid page. got=%+v, expected=the last one", older)
+	}
+
+	if _, err := client.GetRecentlyPlayed(Cursors{Before: "1", After: "2"}, 2); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidCursor)
+	}
+}
+
+func TestGetTopTracks(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/me/top/tracks" {
+			t.Errorf("invalid path. got=%s, expected=/v1/me/top/tracks", r.URL.Path)
+		}
+
+		query := r.URL.Query()
+		if query.Get("time_range") != "short_term" || query.Get("limit") != "20" {
+			t.Errorf("invalid query. got=%s, expected=time_range=short_term&limit=20", r.URL.RawQuery)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [{"id": "t1", "name": "First"}, {"id": "t2", "name": "Second"}]}`))
+	})
+
+	tracks, err := client.GetTopTracks(ShortTerm, 20)
+	if err != nil {
+		t.Fatalf("failed to get top tracks: %s", err)
+	}
+
+	if len(tracks) != 2 || tracks[0].Id != "t1" || tracks[1].Name != "Second" {
+		t.Fatalf("invalid tracks. got=%+v, expected=[First Second]", tracks)
+	}
+}
+
+func TestGetTopArtistsInvalidRange(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("invalid request. got=%s %s, expected=none", r.Method, r.URL.Path)
+	})
+
+	if _, err := client.GetTopArtists("forever", 20); !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidRange)
+	}
+}
+
+func TestCreatePlaylistAndAddTracks(t *testing.T) {
+	batches := []int{}
+
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/v1/users/me1/playlists":
+			var details PlaylistDetails
+			json.NewDecoder(r.Body).Decode(&details)
+
+			if r.Method != http.MethodPost || details.Name != "Mix" || details.Public {
+				t.Errorf("invalid request. got=%s %+v, expected=POST of a private Mix", r.Method, details)
+			}
+
+			w.Write([]byte(`{"id": "p1", "name": "Mix", "snapshot_id": "s0"}`))
+		case "/v1/playlists/p1/tracks":
+			var body struct {
+				Uris []string `json:"uris"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+
+			batches = append(batches, len(body.Uris))
+
+			w.Write([]byte(`{"snapshot_id": "s` + string(rune('0'+len(batches))) + `"}`))
+		default:
+			t.Errorf("invalid path. got=%s, expected=the playlist or its tracks", r.URL.Path)
+		}
+	})
+
+	playlist, err := client.CreatePlaylist("me1", PlaylistDetails{Name: "Mix"})
+	if err != nil {
+		t.Fatalf("failed to create playlist: %s", err)
+	}
+
+	if playlist.Id != "p1" {
+		t.Fatalf("invalid playlist. got=%+v, expected=p1", playlist)
+	}
+
+	uris := make([]string, 150)
+	for i := range uris {
+		uris[i] = "spotify:track:t"
+	}
+
+	snapshot, err := client.AddPlaylistTracks(playlist.Id, uris)
+	if err != nil {
+		t.Fatalf("failed to add tracks: %s", err)
+	}
+
+	if len(batches) != 2 || batches[0] != 100 || batches[1] != 50 {
+		t.Fatalf("invalid batches. got=%v, expected=[100 50]", batches)
+	}
+
+	if snapshot != "s2" {
+		t.Fatalf("invalid snapshot. got=%s, expected=s2", snapshot)
+	}
+}
+
+func TestReorderPlaylistTracks(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/playlists/p1/tracks" {
+			t.Errorf("invalid request. got=%s %s, expected=PUT /v1/playlists/p1/tracks", r.Method, r.URL.Path)
+		}
+
+		if body["range_start"] != 3.0 || body["range_length"] != 2.0 ||
+			body["insert_before"] != 0.0 || body["snapshot_id"] != "s1" {
+			t.Errorf("invalid body. got=%v, expected=range 3+2 before 0 at s1", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"snapshot_id": "s2"}`))
+	})
+
+	snapshot, err := client.ReorderPlaylistTracks("p1",
+		Reorder{RangeStart: 3, RangeLength: 2, InsertBefore: 0, SnapshotId: "s1"})
+	if err != nil {
+		t.Fatalf("failed to reorder tracks: %s", err)
+	}
+
+	if snapshot != "s2" {
+		t.Fatalf("invalid snapshot. got=%s, expected=s2", snapshot)
+	}
+}
+
+func TestRemovePlaylistTracks(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Tracks     []map[string]string `json:"tracks"`
+			SnapshotId string              `json:"snapshot_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if r.Method != http.MethodDelete {
+			t.Errorf("invalid method. got=%s, expected=DELETE", r.Method)
+		}
+
+		if len(body.Tracks) != 1 || body.Tracks[0]["uri"] != "spotify:track:t1" || body.SnapshotId != "s1" {
+			t.Errorf("invalid body. got=%+v, expected=spotify:track:t1 at s1", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"snapshot_id": "s2"}`))
+	})
+
+	snapshot, err := client.RemovePlaylistTracks("p1", "s1", []string{"spotify:track:t1"})
+	if err != nil {
+		t.Fatalf("failed to remove tracks: %s", err)
+	}
+
+	if snapshot != "s2" {
+		t.Fatalf("invalid snapshot. got=%s, expected=s2", snapshot)
+	}
+}
+
+func TestGetPlaylistItems(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch offset := r.URL.Query().Get("offset"); offset {
+		case "0":
+			w.Write([]byte(`{"items": [{"added_by": {"id": "u1"}, "track": {"id": "t1"}}], "next": "more"}`))
+		case "100":
+			w.Write([]byte(`{"items": [{"added_by": {"id": "u2"}, "track": {"id": "t2"}}], "next": null}`))
+		default:
+			t.Errorf("invalid offset. got=%s, expected=0 or 100", offset)
+		}
+	})
+
+	items, err := client.GetPlaylistItems("p1")
+	if err != nil {
+		t.Fatalf("failed to get playlist items: %s", err)
+	}
+
+	if len(items) != 2 || items[0].AddedBy.Id != "u1" || items[1].Track.Id != "t2" {
+		t.Fatalf("invalid items. got=%+v, expected=t1 by u1 and t2", items)
+	}
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	attempts := 0
+
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "me1"}`))
+	})
+
+	waits := 0
+	client.OnRateLimit(func(wait time.Duration) {
+		waits++
+	})
+
+	profile, err := client.GetUserProfile()
+	if err != nil {
+		t.Fatalf("failed to get user profile: %s", err)
+	}
+
+	if profile.Id != "me1" || attempts != 3 || waits != 2 {
+		t.Fatalf("invalid result. got=%+v after %d attempts and %d waits, expected=me1 after 3 and 2", profile, attempts, waits)
+	}
+}
+
+func TestRateLimitTooLong(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	if _, err := client.GetUserProfile(); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrRateLimited)
+	}
+}
+
+func TestGetFollowedArtists(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("type") != "artist" {
+			t.Errorf("invalid query. got=%s, expected=type=artist", r.URL.RawQuery)
+		}
+
+		switch r.URL.Query().Get("after") {
+		case "":
+			w.Write([]byte(`{"artists": {"items": [{"id": "a1"}, {"id": "a2"}], "cursors": {"after": "a2"}}}`))
+		case "a2":
+			w.Write([]byte(`{"artists": {"items": [{"id": "a3"}], "cursors": {"after": null}}}`))
+		default:
+			t.Errorf("invalid cursor. got=%s, expected=none or a2", r.URL.Query().Get("after"))
+		}
+	})
+
+	artists, err := client.GetFollowedArtists()
+	if err != nil {
+		t.Fatalf("failed to get followed artists: %s", err)
+	}
+
+	if len(artists) != 3 || artists[2].Id != "a3" {
+		t.Fatalf("invalid artists. got=%+v, expected=[a1 a2 a3]", artists)
+	}
+}
+
+func TestSaveAlbums(t *testing.T) {
+	batches := []int{}
+
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Ids []string `json:"ids"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/me/albums" {
+			t.Errorf("invalid request. got=%s %s, expected=PUT /v1/me/albums", r.Method, r.URL.Path)
+		}
+
+		batches = append(batches, len(body.Ids))
+	})
+
+	if err := client.SaveAlbums(make([]string, 45)); err != nil {
+		t.Fatalf("failed to save albums: %s", err)
+	}
+
+	if len(batches) != 3 || batches[0] != 20 || batches[2] != 5 {
+		t.Fatalf("invalid batches. got=%v, expected=[20 20 5]", batches)
+	}
+}
+
+func TestReplacePlaylistTracks(t *testing.T) {
+	requests := []string{}
+
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Uris []string `json:"uris"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if r.URL.Path != "/v1/playlists/p1/tracks" || body.Uris == nil {
+			t.Errorf("invalid request. got=%s %s, expected=/v1/playlists/p1/tracks with uris", r.Method, r.URL.Path)
+		}
+
+		requests = append(requests, fmt.Sprintf("%s %d", r.Method, len(body.Uris)))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"snapshot_id": "s1"}`))
+	})
+
+	for _, size := range []int{250, 0} {
+		if _, err := client.ReplacePlaylistTracks("p1", make([]string, size)); err != nil {
+			t.Fatalf("failed to replace tracks: %s", err)
+		}
+	}
+
+	expected := []string{"PUT 100", "POST 100", "POST 50", "PUT 0"}
+	if !slices.Equal(requests, expected) {
+		t.Fatalf("invalid requests. got=%v, expected=%v", requests, expected)
+	}
+}
+
+func TestSaveTracks(t *testing.T) {
+	addedAt := time.Date(2022, 3, 1, 18, 30, 0, 0, time.UTC)
+	batches := []int{}
+
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			TimestampedIds []TimestampedId `json:"timestamped_ids"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/me/tracks" {
+			t.Errorf("invalid request. got=%s %s, expected=PUT /v1/me/tracks", r.Method, r.URL.Path)
+		}
+
+		if first := body.TimestampedIds[0]; first.Id == "" || !first.AddedAt.Equal(addedAt) {
+			t.Errorf("invalid timestamped id. got=%+v, expected added_at=%s", first, addedAt)
+		}
+
+		batches = append(batches, len(body.TimestampedIds))
+	})
+
+	tracks := make([]TimestampedId, 60)
+	for i := range tracks {
+		tracks[i] = TimestampedId{Id: fmt.Sprintf("t%d", i), AddedAt: addedAt}
+	}
+
+	if err := client.SaveTracks(tracks); err != nil {
+		t.Fatalf("failed to save tracks: %s", err)
+	}
+
+	if !slices.Equal(batches, []int{50, 10}) {
+		t.Fatalf("invalid batches. got=%v, expected=[50 10]", batches)
+	}
+}
+
+func TestGetArtistAlbums(t *testing.T) {
+	client := fakeApi(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/artists/a1/albums" || r.URL.Query().Get("include_groups") != "album,single" {
+			t.Errorf("invalid request. got=%s, expected=/v1/artists/a1/albums?include_groups=album,single", r.URL)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [{"id": "al1", "release_date": "2024-05-01"}], "next": null}`))
+	})
+
+	albums, err := client.GetArtistAlbums("a1")
+	if err != nil {
+		t.Fatalf("failed to get artist albums: %s", err)
+	}
+
+	if len(albums) != 1 || albums[0].ReleaseDate != "2024-05-01" {
+		t.Fatalf("invalid albums. got=%+v, expected=al1 released 2024-05-01", albums)
+	}
+}
@@ -1,17 +1,201 @@
 package api
 
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
 type ErrResponse struct {
 	Message string `json:"message"`
 	Status  int    `json:"status"`
+	Reason  string `json:"reason"`
 }
 
 func (e ErrResponse) Error() string {
 	return e.Message
 }
 
+func (e ErrResponse) Is(target error) bool {
+	switch target {
+	case ErrNoActiveDevice:
+		return e.Reason == "NO_ACTIVE_DEVICE"
+	case ErrUnauthorized:
+		return e.Status == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	}
+
+	return false
+}
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+)
+
 type UserProfile struct {
+	Id        string `json:"id"`
 	Name      string `json:"display_name"`
 	Followers struct {
 		Total int `json:"total"`
 	} `json:"followers"`
 }
+
+type Artist struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Uri        string   `json:"uri"`
+	Genres     []string `json:"genres,omitempty"`
+	Popularity int      `json:"popularity,omitempty"`
+}
+
+type Image struct {
+	Url    string `json:"url"`
+	Height int    `json:"height"`
+	Width  int    `json:"width"`
+}
+
+type Album struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Uri         string   `json:"uri"`
+	ReleaseDate string   `json:"release_date"`
+	Artists     []Artist `json:"artists"`
+	Images      []Image  `json:"images"`
+}
+
+type Track struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`
+	Uri         string   `json:"uri"`
+	DurationMs  int      `json:"duration_ms"`
+	Explicit    bool     `json:"explicit"`
+	Popularity  int      `json:"popularity"`
+	Artists     []Artist `json:"artists"`
+	Album       Album    `json:"album"`
+	ExternalIds struct {
+		Isrc string `json:"isrc"`
+	} `json:"external_ids"`
+	ExternalUrls struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+}
+
+type Device struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	IsActive       bool   `json:"is_active"`
+	IsRestricted   bool   `json:"is_restricted"`
+	VolumePercent  int    `json:"volume_percent"`
+	SupportsVolume bool   `json:"supports_volume"`
+}
+
+type Context struct {
+	Type string `json:"type"`
+	Uri  string `json:"uri"`
+}
+
+type PlaybackState struct {
+	Device       Device  `json:"device"`
+	RepeatState  string  `json:"repeat_state"`
+	ShuffleState bool    `json:"shuffle_state"`
+	Context      Context `json:"context"`
+	Timestamp    int64   `json:"timestamp"`
+	ProgressMs   int     `json:"progress_ms"`
+	IsPlaying    bool    `json:"is_playing"`
+	Item         Track   `json:"item"`
+}
+
+type Queue struct {
+	CurrentlyPlaying Track   `json:"currently_playing"`
+	Queue            []Track `json:"queue"`
+}
+
+type PlayOptions struct {
+	ContextUri string   `json:"context_uri,omitempty"`
+	Uris       []string `json:"uris,omitempty"`
+	OffsetUri  string   `json:"offset_uri,omitempty"`
+}
+
+type Cursors struct {
+	After  string `json:"after"`
+	Before string `json:"before"`
+}
+
+type PlayHistory struct {
+	Track    Track     `json:"track"`
+	PlayedAt time.Time `json:"played_at"`
+	Context  Context   `json:"context"`
+}
+
+type RecentlyPlayed struct {
+	Items   []PlayHistory `json:"items"`
+	Next    string        `json:"next"`
+	Cursors Cursors       `json:"cursors"`
+	Limit   int           `json:"limit"`
+}
+
+func (r RecentlyPlayed) HasMore() bool {
+	return r.Next != "" && r.Cursors.Before != ""
+}
+
+type TimeRange string
+
+const (
+	ShortTerm  TimeRange = "short_term"
+	MediumTerm TimeRange = "medium_term"
+	LongTerm   TimeRange = "long_term"
+)
+
+var TimeRanges = []TimeRange{ShortTerm, MediumTerm, LongTerm}
+
+type PlaylistDetails struct {
+	Name          string `json:"name"`
+	Description   string `json:"description"`
+	Public        bool   `json:"public"`
+	Collaborative bool   `json:"collaborative"`
+}
+
+type Playlist struct {
+	Id            string      `json:"id"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	Uri           string      `json:"uri"`
+	Public        bool        `json:"public"`
+	Collaborative bool        `json:"collaborative"`
+	SnapshotId    string      `json:"snapshot_id"`
+	Owner         UserProfile `json:"owner"`
+	Tracks        struct {
+		Total int `json:"total"`
+	} `json:"tracks"`
+}
+
+type PlaylistItem struct {
+	AddedAt time.Time   `json:"added_at"`
+	AddedBy UserProfile `json:"added_by"`
+	Track   Track       `json:"track"`
+}
+
+type SavedTrack struct {
+	AddedAt time.Time `json:"added_at"`
+	Track   Track     `json:"track"`
+}
+
+type TimestampedId struct {
+	Id      string    `json:"id"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type SavedAlbum struct {
+	AddedAt time.Time `json:"added_at"`
+	Album   Album     `json:"album"`
+}
+
+type Reorder struct {
+	RangeStart   int    `json:"range_start"`
+	RangeLength  int    `json:"range_length"`
+	InsertBefore int    `json:"insert_before"`
+	SnapshotId   string `json:"snapshot_id,omitempty"`
+}
@@ -0,0 +1,375 @@
+package apitest
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+type FakeLibrary struct {
+	mu        sync.Mutex
+	calls     []string
+	snapshots int
+	Profile   api.UserProfile
+	Tracks    []api.SavedTrack
+	Albums    []api.SavedAlbum
+	Artists   []api.Artist
+	Playlists []api.Playlist
+	Items     map[string][]api.PlaylistItem
+	Top       map[api.TimeRange][]api.Track
+	Releases  map[string][]api.Album
+	Album     map[string][]api.Track
+	Recent    []api.PlayHistory
+	Fail      func(call string) error
+}
+
+func (f *FakeLibrary) record(call string) error {
+	f.calls = append(f.calls, call)
+
+	if f.Fail != nil {
+		return f.Fail(call)
+	}
+
+	return nil
+}
+
+func (f *FakeLibrary) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string{}, f.calls...)
+}
+
+func (f *FakeLibrary) Count(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := 0
+	for _, call := range f.calls {
+		if strings.HasPrefix(call, prefix) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (f *FakeLibrary) Uris(playlistId string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	uris := []string{}
+	for _, item := range f.Items[playlistId] {
+		uris = append(uris, item.Track.Uri)
+	}
+
+	return uris
+}
+
+func (f *FakeLibrary) snapshot(playlistId string) string {
+	f.snapshots++
+
+	snapshotId := fmt.Sprintf("s%d", f.snapshots)
+	for i := range f.Playlists {
+		if f.Playlists[i].Id == playlistId {
+			f.Playlists[i].SnapshotId = snapshotId
+			f.Playlists[i].Tracks.Total = len(f.Items[playlistId])
+		}
+	}
+
+	return snapshotId
+}
+
+func (f *FakeLibrary) GetUserProfile() (api.UserProfile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.Profile, f.record("GetUserProfile")
+}
+
+func (f *FakeLibrary) GetSavedTracks() ([]api.SavedTrack, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.Tracks), f.record("GetSavedTracks")
+}
+
+func (f *FakeLibrary) SaveTracks(tracks []api.TimestampedId) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ids := make([]string, 0, len(tracks))
+	for _, track := range tracks {
+		ids = append(ids, track.Id)
+	}
+
+	if err := f.record(fmt.Sprintf("SaveTracks %s", strings.Join(ids, ","))); err != nil {
+		return err
+	}
+
+	for _, saved := range tracks {
+		track := api.Track{Id: saved.Id, Uri: "spotify:track:" + saved.Id}
+		f.Tracks = slices.Insert(f.Tracks, 0, api.SavedTrack{AddedAt: saved.AddedAt, Track: track})
+	}
+
+	return nil
+}
+
+func (f *FakeLibrary) GetSavedAlbums() ([]api.SavedAlbum, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.Albums), f.record("GetSavedAlbums")
+}
+
+func (f *FakeLibrary) SaveAlbums(ids []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(fmt.Sprintf("SaveAlbums %s", strings.Join(ids, ","))); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		album := api.Album{Id: id, Uri: "spotify:album:" + id}
+		f.Albums = slices.Insert(f.Albums, 0, api.SavedAlbum{AddedAt: time.Now(), Album: album})
+	}
+
+	return nil
+}
+
+func (f *FakeLibrary) GetFollowedArtists() ([]api.Artist, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.Artists), f.record("GetFollowedArtists")
+}
+
+func (f *FakeLibrary) FollowArtists(ids []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(fmt.Sprintf("FollowArtists %s", strings.Join(ids, ","))); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		f.Artists = append(f.Artists, api.Artist{Id: id, Uri: "spotify:artist:" + id})
+	}
+
+	return nil
+}
+
+func (f *FakeLibrary) GetTopTracks(timeRange api.TimeRange, limit int) ([]api.Track, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	top := f.Top[timeRange]
+
+	return slices.Clone(top[:min(limit, len(top))]), f.record(fmt.Sprintf("GetTopTracks %s", timeRange))
+}
+
+func (f *FakeLibrary) GetArtistAlbums(artistId string) ([]api.Album, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.Releases[artistId]), f.record(fmt.Sprintf("GetArtistAlbums %s", artistId))
+}
+
+func (f *FakeLibrary) GetAlbumTracks(albumId string) ([]api.Track, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.Album[albumId]), f.record(fmt.Sprintf("GetAlbumTracks %s", albumId))
+}
+
+func (f *FakeLibrary) GetRecentlyPlayed(cursors api.Cursors, limit int) (api.RecentlyPlayed, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("GetRecentlyPlayed"); err != nil {
+		return api.RecentlyPlayed{}, err
+	}
+
+	after, _ := strconv.ParseInt(cursors.After, 10, 64)
+
+	recent := api.RecentlyPlayed{Limit: limit}
+	for _, play := range f.Recent {
+		if play.PlayedAt.UnixMilli() > after && len(recent.Items) < limit {
+			recent.Items = append(recent.Items, play)
+		}
+	}
+
+	return recent, nil
+}
+
+func (f *FakeLibrary) GetMyPlaylists() ([]api.Playlist, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.Playlists), f.record("GetMyPlaylists")
+}
+
+func (f *FakeLibrary) GetPlaylist(playlistId string) (api.Playlist, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(fmt.Sprintf("GetPlaylist %s", playlistId)); err != nil {
+		return api.Playlist{}, err
+	}
+
+	for _, playlist := range f.Playlists {
+		if playlist.Id == playlistId {
+			return playlist, nil
+		}
+	}
+
+	return api.Playlist{}, api.ErrResponse{Message: "Not found", Status: 404}
+}
+
+func (f *FakeLibrary) GetPlaylistItems(playlistId string) ([]api.PlaylistItem, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return slices.Clone(f.Items[playlistId]), f.record(fmt.Sprintf("GetPlaylistItems %s", playlistId))
+}
+
+func (f *FakeLibrary) CreatePlaylist(userId string, details api.PlaylistDetails) (api.Playlist, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(fmt.Sprintf("CreatePlaylist %s", details.Name)); err != nil {
+		return api.Playlist{}, err
+	}
+
+	playlist := api.Playlist{
+		Id:            fmt.Sprintf("p%d", len(f.Playlists)+1),
+		Name:          details.Name,
+		Description:   details.Description,
+		Public:        details.Public,
+		Collaborative: details.Collaborative,
+		Owner:         f.Profile,
+	}
+	playlist.Uri = "spotify:playlist:" + playlist.Id
+
+	f.Playlists = slices.Insert(f.Playlists, 0, playlist)
+	if f.Items == nil {
+		f.Items = map[string][]api.PlaylistItem{}
+	}
+	f.Items[playlist.Id] = []api.PlaylistItem{}
+
+	playlist.SnapshotId = f.snapshot(playlist.Id)
+
+	return playlist, nil
+}
+
+func (f *FakeLibrary) items(uris []string) []api.PlaylistItem {
+	items := make([]api.PlaylistItem, 0, len(uris))
+	for _, uri := range uris {
+		id := uri[strings.LastIndex(uri, ":")+1:]
+		items = append(items, api.PlaylistItem{
+			AddedAt: time.Now(),
+			AddedBy: f.Profile,
+			Track:   api.Track{Id: id, Uri: uri},
+		})
+	}
+
+	return items
+}
+
+func (f *FakeLibrary) AddPlaylistTracks(playlistId string, uris []string) (string, error) {
+	return f.InsertPlaylistTracks(playlistId, uris, -1)
+}
+
+func (f *FakeLibrary) InsertPlaylistTracks(playlistId string, uris []string, position int) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(fmt.Sprintf("AddPlaylistTracks %s %d", playlistId, len(uris))); err != nil {
+		return "", err
+	}
+
+	items := f.items(uris)
+
+	if position < 0 || position > len(f.Items[playlistId]) {
+		position = len(f.Items[playlistId])
+	}
+	f.Items[playlistId] = slices.Insert(f.Items[playlistId], position, items...)
+
+	return f.snapshot(playlistId), nil
+}
+
+func (f *FakeLibrary) ReplacePlaylistTracks(playlistId string, uris []string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(fmt.Sprintf("ReplacePlaylistTracks %s %d", playlistId, len(uris))); err != nil {
+		return "", err
+	}
+
+	f.Items[playlistId] = f.items(uris)
+
+	return f.snapshot(playlistId), nil
+}
+
+func (f *FakeLibrary) RemovePlaylistTracks(playlistId, snapshotId string, uris []string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(fmt.Sprintf("RemovePlaylistTracks %s %d", playlistId, len(uris))); err != nil {
+		return "", err
+	}
+
+	f.Items[playlistId] = slices.DeleteFunc(f.Items[playlistId], func(item api.PlaylistItem) bool {
+		return slices.Contains(uris, item.Track.Uri)
+	})
+
+	return f.snapshot(playlistId), nil
+}
+
+func (f *FakeLibrary) ReorderPlaylistTracks(playlistId string, reorder api.Reorder) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(fmt.Sprintf("ReorderPlaylistTracks %s %d %d %d",
+		playlistId, reorder.RangeStart, reorder.RangeLength, reorder.InsertBefore)); err != nil {
+		return "", err
+	}
+
+	items := f.Items[playlistId]
+	moved := slices.Clone(items[reorder.RangeStart : reorder.RangeStart+reorder.RangeLength])
+	rest := slices.Delete(slices.Clone(items), reorder.RangeStart, reorder.RangeStart+reorder.RangeLength)
+
+	insert := reorder.InsertBefore
+	if insert > reorder.RangeStart {
+		insert -= reorder.RangeLength
+	}
+	f.Items[playlistId] = slices.Insert(rest, insert, moved...)
+
+	return f.snapshot(playlistId), nil
+}
+
+func (f *FakeLibrary) ChangePlaylistDetails(playlistId string, details api.PlaylistDetails) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(fmt.Sprintf("ChangePlaylistDetails %s", playlistId)); err != nil {
+		return err
+	}
+
+	for i := range f.Playlists {
+		if f.Playlists[i].Id == playlistId {
+			f.Playlists[i].Name = details.Name
+			f.Playlists[i].Description = details.Description
+			f.Playlists[i].Public = details.Public
+			f.Playlists[i].Collaborative = details.Collaborative
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package apitest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+type FakePlayer struct {
+	mu      sync.Mutex
+	calls   []string
+	State   api.PlaybackState
+	Profile api.UserProfile
+	Devices []api.Device
+	Queue   api.Queue
+	Err     error
+}
+
+func (f *FakePlayer) record(call string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, call)
+
+	return f.Err
+}
+
+func (f *FakePlayer) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string{}, f.calls...)
+}
+
+func (f *FakePlayer) GetUserProfile() (api.UserProfile, error) {
+	return f.Profile, f.record("GetUserProfile")
+}
+
+func (f *FakePlayer) GetPlaybackState() (api.PlaybackState, error) {
+	return f.State, f.record("GetPlaybackState")
+}
+
+func (f *FakePlayer) GetDevices() ([]api.Device, error) {
+	return f.Devices, f.record("GetDevices")
+}
+
+func (f *FakePlayer) TransferPlayback(deviceId string, play bool) error {
+	return f.record(fmt.Sprintf("TransferPlayback %s %t", deviceId, play))
+}
+
+func (f *FakePlayer) Resume() error {
+	return f.record("Resume")
+}
+
+func (f *FakePlayer) Pause() error {
+	return f.record("Pause")
+}
+
+func (f *FakePlayer) SkipToPrevious() error {
+	return f.record("SkipToPrevious")
+}
+
+func (f *FakePlayer) SkipToNext() error {
+	return f.record("SkipToNext")
+}
+
+func (f *FakePlayer) EnableShuffle() error {
+	return f.record("EnableShuffle")
+}
+
+func (f *FakePlayer) DisableShuffle() error {
+	return f.record("DisableShuffle")
+}
+
+func (f *FakePlayer) SetRepeatTrack() error {
+	return f.record("SetRepeatTrack")
+}
+
+func (f *FakePlayer) SetRepeatContext() error {
+	return f.record("SetRepeatContext")
+}
+
+func (f *FakePlayer) DisableRepeat() error {
+	return f.record("DisableRepeat")
+}
+
+func (f *FakePlayer) SetVolume(percent int) error {
+	return f.record(fmt.Sprintf("SetVolume %d", percent))
+}
+
+func (f *FakePlayer) Seek(positionMs int) error {
+	return f.record(fmt.Sprintf("Seek %d", positionMs))
+}
+
+func (f *FakePlayer) GetQueue() (api.Queue, error) {
+	return f.Queue, f.record("GetQueue")
+}
+
+func (f *FakePlayer) StartPlayback(options api.PlayOptions) error {
+	return f.record(fmt.Sprintf("StartPlayback %s %s %s",
+		options.ContextUri, strings.Join(options.Uris, ","), options.OffsetUri))
+}
+
+func (f *FakePlayer) AddToQueue(uri string) error {
+	return f.record(fmt.Sprintf("AddToQueue %s", uri))
+}
@@ -1,88 +1,223 @@
 package auth
 
 import (
+	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
+	"sync/atomic"
 
 	"github.com/franciscosbf/spotify-tui/internals/uri"
-	"github.com/franciscosbf/spotify-tui/internals/util"
 )
 
 var (
-	ErrInvalidAuth = errors.New("invalid authentication")
-	ErrAuthTimeout = errors.New("authentication timed out")
+	ErrInvalidAuth        = errors.New("invalid authentication")
+	ErrInvalidState       = errors.New("invalid authentication state")
+	ErrCallbackUsed       = errors.New("authentication callback already used")
+	ErrAuthTimeout        = errors.New("authentication timed out")
+	ErrAuthCancelled      = errors.New("authentication cancelled")
+	ErrRedirectNoLoopback = errors.New("redirect host must be a loopback address")
+	ErrRedirectNoPorts    = errors.New("redirect has no ports to listen on")
+	ErrCallbackFailed     = errors.New("callback server stopped unexpectedly")
 )
 
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e OAuthError) Error() string {
+	return fmt.Sprintf("authorization failed: %s", e.Code)
+}
+
+type BindError struct {
+	Addresses []string
+}
+
+func (e BindError) Error() string {
+	return fmt.Sprintf("failed to listen for callback on %s",
+		strings.Join(e.Addresses, ", "))
+}
+
+type Redirect struct {
+	Host  string
+	Ports []int
+}
+
+var DefaultRedirect = Redirect{Host: "localhost", Ports: []int{6747}}
+
+func (r Redirect) loopback() bool {
+	if r.Host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(r.Host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+func (r Redirect) Uri(port int) string {
+	address := net.JoinHostPort(r.Host, strconv.Itoa(port))
+
+	return fmt.Sprintf("http://%s/callback", address)
+}
+
 type CodeAuth struct {
 	Url   string
 	State string
 }
 
 type callbackResponse struct {
-	code  string
-	state string
-	error string
+	code        string
+	state       string
+	error       string
+	description string
 }
 
-type callbackServer struct {
-	*http.Server
-	response <-chan callbackResponse
+func (r callbackResponse) verify(state string) (string, error) {
+	if subtle.ConstantTimeCompare([]byte(r.state), []byte(state)) != 1 {
+		return "", ErrInvalidState
+	}
+
+	if r.error != "" {
+		return "", OAuthError{r.error, r.description}
+	}
+
+	if r.code == "" {
+		return "", ErrInvalidAuth
+	}
+
+	return r.code, nil
 }
 
-func genState() string {
-	r := util.NewRand()
+func parseCallbackResponse(query url.Values) callbackResponse {
+	return callbackResponse{
+		code:        query.Get("code"),
+		state:       query.Get("state"),
+		error:       query.Get("error"),
+		description: query.Get("error_description"),
+	}
+}
 
-	return strconv.FormatInt(r.Int63(), 10)
+type CallbackServer struct {
+	listener    net.Listener
+	redirectUri string
 }
 
-func startCallbackServer() callbackServer {
-	url, _ := url.Parse(uri.REDIRECT)
-	port := url.Port()
+func ListenCallback(redirect Redirect) (*CallbackServer, error) {
+	if !redirect.loopback() {
+		return nil, ErrRedirectNoLoopback
+	}
+
+	if len(redirect.Ports) == 0 {
+		return nil, ErrRedirectNoPorts
+	}
+
+	addresses := []string{}
 
-	result := make(chan callbackResponse, 1)
+	for _, port := range redirect.Ports {
+		address := net.JoinHostPort(redirect.Host, strconv.Itoa(port))
+
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			addresses = append(addresses, address)
+			continue
+		}
+
+		port = listener.Addr().(*net.TCPAddr).Port
+
+		return &CallbackServer{listener, redirect.Uri(port)}, nil
+	}
+
+	return nil, BindError{addresses}
+}
+
+func (c *CallbackServer) RedirectUri() string {
+	return c.redirectUri
+}
+
+func (c *CallbackServer) Close() error {
+	return c.listener.Close()
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+func callbackHandler(state string, results chan<- callbackResult) http.Handler {
+	var used atomic.Bool
 
 	handler := http.NewServeMux()
 	handler.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		query := r.URL.Query()
+		if used.Load() {
+			renderCallbackPage(w, ErrCallbackUsed)
+			return
+		}
+
+		code, err := parseCallbackResponse(r.URL.Query()).verify(state)
+
+		var oauthErr OAuthError
+		if err != nil && !errors.As(err, &oauthErr) {
+			renderCallbackPage(w, err)
+			return
+		}
 
-		result <- callbackResponse{
-			code:  query.Get("code"),
-			state: query.Get("state"),
-			error: query.Get("error"),
+		if !used.CompareAndSwap(false, true) {
+			renderCallbackPage(w, ErrCallbackUsed)
+			return
 		}
 
-		fmt.Fprint(w, "Go check the app...")
+		renderCallbackPage(w, err)
+
+		results <- callbackResult{code, err}
 	})
 
-	server := &http.Server{
-		Addr:    fmt.Sprintf(":%s", port),
-		Handler: handler,
-	}
+	return handler
+}
+
+func (c *CallbackServer) WaitForCode(ctx context.Context, state string) (string, error) {
+	results := make(chan callbackResult, 1)
 
+	server := &http.Server{Handler: callbackHandler(state, results)}
+
+	serveErr := make(chan error, 1)
 	go func() {
-		server.ListenAndServe()
+		serveErr <- server.Serve(c.listener)
 	}()
 
-	return callbackServer{server, result}
+	defer server.Close()
+
+	select {
+	case result := <-results:
+		return result.code, result.err
+	case <-serveErr:
+		return "", ErrCallbackFailed
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", ErrAuthTimeout
+		}
+		return "", ErrAuthCancelled
+	}
 }
 
-func BuildCodeAuth(clientId, codeChallenge string) CodeAuth {
+func BuildCodeAuth(clientId, codeChallenge, redirectUri string, scopes []string) CodeAuth {
 	tokenUrl, _ := url.Parse(uri.ACCOUNTS)
 
 	tokenUrl = tokenUrl.JoinPath("authorize")
 
 	state := genState()
-	scope := strings.Join(requiredScopes, " ")
+	scope := strings.Join(MergeScopes(requiredScopes, scopes), " ")
 
 	query := url.Values{}
 	query.Set("client_id", clientId)
 	query.Set("response_type", "code")
-	query.Set("redirect_uri", uri.REDIRECT)
+	query.Set("redirect_uri", redirectUri)
 	query.Set("state", state)
 	query.Set("scope", scope)
 	query.Set("code_challenge_method", "S256")
@@ -92,21 +227,11 @@ func BuildCodeAuth(clientId, codeChallenge string) CodeAuth {
 	return CodeAuth{Url: tokenUrl.String(), State: state}
 }
 
-func WaitForCode(state string, timeout time.Duration) (string, error) {
-	callback := startCallbackServer()
-
-	defer func() {
-		callback.Close()
-	}()
-
-	select {
-	case response := <-callback.response:
-		if response.error != "" || response.state != state {
-			return "", ErrInvalidAuth
-		}
-
-		return response.code, nil
-	case <-time.After(timeout):
-		return "", ErrAuthTimeout
+func ParseRedirectUrl(redirectUrl, state string) (string, error) {
+	parsed, err := url.Parse(strings.Join(strings.Fields(redirectUrl), ""))
+	if err != nil {
+		return "", ErrInvalidAuth
 	}
+
+	return parseCallbackResponse(parsed.Query()).verify(state)
 }
@@ -1,8 +1,12 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
@@ -11,11 +15,17 @@ import (
 	"github.com/franciscosbf/spotify-tui/internals/uri"
 )
 
+const fakeRedirectUri = "http://localhost:6747/callback"
+
+var testRedirect = Redirect{Host: "127.0.0.1", Ports: []int{0}}
+
 func TestBuildAuth(t *testing.T) {
 	fakeClientId := "242b42obfevbeber"
 	fakeCodeChallenge := "vrwnvr3vnreov3vr3"
 
-	codeAuth := BuildCodeAuth(fakeClientId, fakeCodeChallenge)
+	fakeScopes := []string{ScopeReadPrivate, ScopePlaylistReadPrivate}
+
+	codeAuth := BuildCodeAuth(fakeClientId, fakeCodeChallenge, fakeRedirectUri, fakeScopes)
 
 	expectedUrl := uri.ACCOUNTS + "/authorize"
 	if !strings.HasPrefix(codeAuth.Url, expectedUrl) {
@@ -32,9 +42,9 @@ func TestBuildAuth(t *testing.T) {
 	expectedParameters := map[string]string{
 		"client_id":             fakeClientId,
 		"response_type":         "code",
-		"redirect_uri":          uri.REDIRECT,
+		"redirect_uri":          fakeRedirectUri,
 		"state":                 codeAuth.State,
-		"scope":                 strings.Join(requiredScopes, " "),
+		"scope":                 strings.Join(append(requiredScopes, ScopePlaylistReadPrivate), " "),
 		"code_challenge_method": "S256",
 		"code_challenge":        fakeCodeChallenge,
 	}
@@ -52,50 +62,169 @@ func TestBuildAuth(t *testing.T) {
 	}
 }
 
-func TestWaitForCode(t *testing.T) {
-	fakeCode := "br3rb3h5b34b3bnb"
-	fakeState := "dvsdab33b44t4btadfasf"
+func waitForCallback(t *testing.T, state string, query url.Values) (callbackResult, *http.Response) {
 	timeout := time.Second * 4
 
-	type codeResult struct {
-		err  error
-		code string
+	callback, err := ListenCallback(testRedirect)
+	if err != nil {
+		t.Fatalf("failed to listen for callback: %s", err)
 	}
+	defer callback.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	cre := make(chan codeResult, 1)
-	resp := make(chan *http.Response, 1)
-	stop := make(chan struct{}, 1)
+	cre := make(chan callbackResult, 1)
 
 	go func() {
-		code, err := WaitForCode(fakeState, timeout)
-		cre <- codeResult{err, code}
-		stop <- struct{}{}
+		code, err := callback.WaitForCode(ctx, state)
+		cre <- callbackResult{code, err}
 	}()
 
-	redirectUri, _ := url.Parse(uri.REDIRECT)
-	redirectUri = redirectUri.JoinPath("callback")
-	query := redirectUri.Query()
+	redirectUri, _ := url.Parse(callback.RedirectUri())
+	redirectUri.RawQuery = query.Encode()
+
+	response, err := http.Get(redirectUri.String())
+	if err != nil {
+		t.Fatalf("failed to send callback request: %s", err)
+	}
+
+	return <-cre, response
+}
+
+func readBody(t *testing.T, response *http.Response) string {
+	defer func() {
+		response.Body.Close()
+	}()
+
+	rawBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("failed to read body")
+	}
+
+	return string(rawBody)
+}
+
+func TestWaitForCode(t *testing.T) {
+	fakeCode := "br3rb3h5b34b3bnb"
+	fakeState := "dvsdab33b44t4btadfasf"
+
+	query := url.Values{}
 	query.Set("state", fakeState)
 	query.Set("code", fakeCode)
-	redirectUri.RawQuery = query.Encode()
 
-	go func(redirectUri string) {
-		for {
-			if response, err := http.Get(redirectUri); err == nil {
-				resp <- response
-				break
-			}
-
-			select {
-			case <-stop:
-			default:
-				continue
-			}
-			break
+	result, response := waitForCallback(t, fakeState, query)
+
+	if result.err != nil {
+		t.Fatalf("failed while waiting for code: %s", result.err)
+	}
+
+	if result.code != fakeCode {
+		t.Fatalf("invalid code. got=%s, expected=%s", result.code, fakeCode)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("invalid status. got=%d, expected=%d", response.StatusCode, http.StatusOK)
+	}
+
+	expectedBody := "You can close this tab"
+	if body := readBody(t, response); !strings.Contains(body, expectedBody) {
+		t.Fatalf("invalid body. got=%s, expected to contain %s", body, expectedBody)
+	}
+}
+
+func TestWaitForCodeDenied(t *testing.T) {
+	fakeState := "dvsdab33b44t4btadfasf"
+
+	query := url.Values{}
+	query.Set("state", fakeState)
+	query.Set("error", "access_denied")
+
+	result, response := waitForCallback(t, fakeState, query)
+
+	expectedErr := OAuthError{Code: "access_denied"}
+	if result.err != expectedErr {
+		t.Fatalf("invalid error. got=%v, expected=%v", result.err, expectedErr)
+	}
+
+	if response.StatusCode != http.StatusForbidden {
+		t.Fatalf("invalid status. got=%d, expected=%d", response.StatusCode, http.StatusForbidden)
+	}
+
+	body := readBody(t, response)
+	for _, expected := range []string{"access_denied", "You denied access"} {
+		if !strings.Contains(body, expected) {
+			t.Fatalf("invalid body. got=%s, expected to contain %s", body, expected)
+		}
+	}
+}
+
+func TestWaitForCodeInterrupted(t *testing.T) {
+	callback, err := ListenCallback(testRedirect)
+	if err != nil {
+		t.Fatalf("failed to listen for callback: %s", err)
+	}
+	defer callback.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	if _, err := callback.WaitForCode(ctx, "state"); err != ErrAuthTimeout {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrAuthTimeout)
+	}
+
+	callback, err = ListenCallback(testRedirect)
+	if err != nil {
+		t.Fatalf("failed to listen for callback: %s", err)
+	}
+	defer callback.Close()
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := callback.WaitForCode(ctx, "state"); err != ErrAuthCancelled {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrAuthCancelled)
+	}
+}
+
+func TestCallbackSingleUse(t *testing.T) {
+	fakeCode := "br3rb3h5b34b3bnb"
+	fakeState := "dvsdab33b44t4btadfasf"
+
+	results := make(chan callbackResult, 1)
+	handler := callbackHandler(fakeState, results)
+
+	requests := []struct {
+		name   string
+		state  string
+		code   string
+		status int
+	}{
+		{"forged state", "forged", "forgedcode", http.StatusBadRequest},
+		{"missing code", fakeState, "", http.StatusBadRequest},
+		{"valid", fakeState, fakeCode, http.StatusOK},
+		{"replayed", fakeState, "othercode", http.StatusGone},
+	}
+
+	for _, request := range requests {
+		query := url.Values{}
+		query.Set("state", request.state)
+		query.Set("code", request.code)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/callback?"+query.Encode(), nil))
+
+		if recorder.Code != request.status {
+			t.Fatalf("%s: invalid status. got=%d, expected=%d",
+				request.name, recorder.Code, request.status)
 		}
-	}(redirectUri.String())
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("invalid number of results. got=%d, expected=1", len(results))
+	}
 
-	result := <-cre
+	result := <-results
 
 	if result.err != nil {
 		t.Fatalf("failed while waiting for code: %s", result.err)
@@ -104,20 +233,106 @@ func TestWaitForCode(t *testing.T) {
 	if result.code != fakeCode {
 		t.Fatalf("invalid code. got=%s, expected=%s", result.code, fakeCode)
 	}
+}
 
-	response := <-resp
+func TestListenCallbackFallback(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to occupy port: %s", err)
+	}
+	defer busy.Close()
 
-	defer func() {
-		response.Body.Close()
+	busyPort := busy.Addr().(*net.TCPAddr).Port
+
+	callback, err := ListenCallback(Redirect{Host: "127.0.0.1", Ports: []int{busyPort, 0}})
+	if err != nil {
+		t.Fatalf("failed to fallback to next port: %s", err)
+	}
+	defer callback.Close()
+
+	if busyUri := testRedirect.Uri(busyPort); callback.RedirectUri() == busyUri {
+		t.Fatalf("listening on busy port. got=%s", callback.RedirectUri())
+	}
+
+	_, err = ListenCallback(Redirect{Host: "127.0.0.1", Ports: []int{busyPort}})
+
+	var bindErr BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("invalid error. got=%v, expected bind error", err)
+	}
+}
+
+func TestListenCallbackAfterClose(t *testing.T) {
+	callback, err := ListenCallback(Redirect{Host: "127.0.0.1", Ports: []int{0}})
+	if err != nil {
+		t.Fatalf("failed to listen for callback: %s", err)
+	}
+
+	port := callback.listener.Addr().(*net.TCPAddr).Port
+
+	waitErr := make(chan error, 1)
+	go func() {
+		_, err := callback.WaitForCode(context.Background(), "state")
+		waitErr <- err
 	}()
 
-	rawBody, err := io.ReadAll(response.Body)
+	callback.Close()
+
+	retry, err := ListenCallback(Redirect{Host: "127.0.0.1", Ports: []int{port}})
 	if err != nil {
-		t.Fatalf("failed to read body")
+		t.Fatalf("failed to listen again on the same port: %s", err)
+	}
+	defer retry.Close()
+
+	if err := <-waitErr; err != ErrCallbackFailed {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrCallbackFailed)
 	}
+}
+
+func TestListenCallbackInvalidRedirect(t *testing.T) {
+	tests := []struct {
+		name     string
+		redirect Redirect
+		err      error
+	}{
+		{"any address", Redirect{Host: "0.0.0.0", Ports: []int{0}}, ErrRedirectNoLoopback},
+		{"remote host", Redirect{Host: "example.com", Ports: []int{0}}, ErrRedirectNoLoopback},
+		{"no ports", Redirect{Host: "127.0.0.1"}, ErrRedirectNoPorts},
+	}
+
+	for _, test := range tests {
+		if _, err := ListenCallback(test.redirect); err != test.err {
+			t.Fatalf("%s: invalid error. got=%v, expected=%v", test.name, err, test.err)
+		}
+	}
+}
 
-	expectedBody := "Go check the app..."
-	if body := string(rawBody); body != expectedBody {
-		t.Fatalf("invalid body. got=%s, expected=%s", body, expectedBody)
+func TestParseRedirectUrl(t *testing.T) {
+	fakeCode := "br3rb3h5b34b3bnb"
+	fakeState := "dvsdab33b44t4btadfasf"
+
+	tests := []struct {
+		name        string
+		redirectUrl string
+		err         error
+	}{
+		{"valid", fakeRedirectUri + "?code=" + fakeCode + "&state=" + fakeState, nil},
+		{"surrounding spaces", " " + fakeRedirectUri + "?code=" + fakeCode + "&state=" + fakeState + "\n", nil},
+		{"broken lines", fakeRedirectUri + "?code=" + fakeCode + "\n&state=" + fakeState, nil},
+		{"invalid state", fakeRedirectUri + "?code=" + fakeCode + "&state=other", ErrInvalidState},
+		{"missing code", fakeRedirectUri + "?state=" + fakeState, ErrInvalidAuth},
+		{"denied", fakeRedirectUri + "?error=access_denied&state=" + fakeState, OAuthError{Code: "access_denied"}},
+		{"malformed", "%zz", ErrInvalidAuth},
+	}
+
+	for _, test := range tests {
+		code, err := ParseRedirectUrl(test.redirectUrl, fakeState)
+		if err != test.err {
+			t.Fatalf("%s: invalid error. got=%v, expected=%v", test.name, err, test.err)
+		}
+
+		if err == nil && code != fakeCode {
+			t.Fatalf("%s: invalid code. got=%s, expected=%s", test.name, code, fakeCode)
+		}
 	}
 }
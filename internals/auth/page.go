@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+)
+
+var callbackPage = template.Must(template.New("callback").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Spotify TUI - {{.Title}}</title>
+<style>
+body { font-family: sans-serif; background: #282828; color: #ebdbb2; display: flex; justify-content: center; padding-top: 15vh; }
+main { max-width: 32em; text-align: center; }
+h1 { color: {{if .Failed}}#fb4934{{else}}#b8bb26{{end}}; }
+code { color: #fabd2f; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+{{if .Code}}<p>Error: <code>{{.Code}}</code></p>{{end}}
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+</main>
+</body>
+</html>
+`))
+
+var oauthErrorMessages = map[string]string{
+	"access_denied":             "You denied access to the app. Go back to the terminal to try again.",
+	"invalid_scope":             "The app requested a permission Spotify doesn't recognize.",
+	"invalid_request":           "The authorization request was malformed.",
+	"unauthorized_client":       "This client id isn't allowed to request authorization.",
+	"server_error":              "Spotify failed to process the authorization.",
+	"temporarily_unavailable":   "Spotify is temporarily unavailable. Try again in a moment.",
+	"unsupported_response_type": "Spotify doesn't support the requested response type.",
+}
+
+type callbackPageData struct {
+	Title       string
+	Message     string
+	Code        string
+	Description string
+	Failed      bool
+}
+
+func renderCallbackPage(w http.ResponseWriter, err error) {
+	data := callbackPageData{
+		Title:   "Authorized",
+		Message: "You can close this tab and go back to the terminal.",
+	}
+
+	status := http.StatusOK
+
+	var oauthErr OAuthError
+
+	switch {
+	case errors.Is(err, ErrCallbackUsed):
+		status = http.StatusGone
+		data = callbackPageData{
+			Title:   "Already authorized",
+			Message: "This authorization was already completed. You can close this tab.",
+			Failed:  true,
+		}
+	case errors.As(err, &oauthErr):
+		status = http.StatusForbidden
+		data = callbackPageData{
+			Title:       "Authorization failed",
+			Message:     "Spotify didn't authorize the app.",
+			Code:        oauthErr.Code,
+			Description: oauthErr.Description,
+			Failed:      true,
+		}
+		if message, ok := oauthErrorMessages[oauthErr.Code]; ok {
+			data.Message = message
+		}
+	case err != nil:
+		status = http.StatusBadRequest
+		data = callbackPageData{
+			Title:   "Authorization failed",
+			Message: "The callback request doesn't match the authorization in progress.",
+			Failed:  true,
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	callbackPage.Execute(w, data)
+}
@@ -1,34 +1,49 @@
 package auth
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
-	"strings"
-
-	"github.com/franciscosbf/spotify-tui/internals/util"
 )
 
-const chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+const chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-._~"
 
 const codeVerifierLength = 128
 
-func GenCodeVerifier() string {
-	r := util.NewRand()
-	code := make([]byte, codeVerifierLength)
+const stateLength = 32
+
+func randomChars(length int) string {
+	limit := byte(256 - 256%len(chars))
+	code := make([]byte, 0, length)
+	buf := make([]byte, length)
 
-	for i := range codeVerifierLength {
-		code[i] = chars[r.Int63()%int64(len(chars))]
+	for len(code) < length {
+		rand.Read(buf)
+
+		for _, b := range buf {
+			if b >= limit || len(code) == length {
+				continue
+			}
+			code = append(code, chars[int(b)%len(chars)])
+		}
 	}
 
 	return string(code)
 }
 
+func GenCodeVerifier() string {
+	return randomChars(codeVerifierLength)
+}
+
 func GenCodeChallenge(codeVerifier string) string {
 	bts := sha256.Sum256([]byte(codeVerifier))
-	challenge := base64.StdEncoding.EncodeToString(bts[:])
 
-	r := strings.NewReplacer("=", "", "+", "-", "/", "_")
-	challenge = r.Replace(challenge)
+	return base64.RawURLEncoding.EncodeToString(bts[:])
+}
+
+func genState() string {
+	bts := make([]byte, stateLength)
+	rand.Read(bts)
 
-	return challenge
+	return base64.RawURLEncoding.EncodeToString(bts)
 }
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenCodeChallengeRfc7636(t *testing.T) {
+	codeVerifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	expectedChallenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	if challenge := GenCodeChallenge(codeVerifier); challenge != expectedChallenge {
+		t.Fatalf("invalid code challenge. got=%s, expected=%s",
+			challenge, expectedChallenge)
+	}
+}
+
+func TestGenCodeVerifier(t *testing.T) {
+	seen := map[string]bool{}
+
+	for range 64 {
+		codeVerifier := GenCodeVerifier()
+
+		if len(codeVerifier) != codeVerifierLength {
+			t.Fatalf("invalid code verifier length. got=%d, expected=%d",
+				len(codeVerifier), codeVerifierLength)
+		}
+
+		for _, c := range codeVerifier {
+			if !strings.ContainsRune(chars, c) {
+				t.Fatalf("code verifier has a reserved character %q", c)
+			}
+		}
+
+		if seen[codeVerifier] {
+			t.Fatalf("repeated code verifier %s", codeVerifier)
+		}
+		seen[codeVerifier] = true
+	}
+}
+
+func TestGenState(t *testing.T) {
+	first, second := genState(), genState()
+
+	if len(first) < 43 {
+		t.Fatalf("state is too short. got=%d", len(first))
+	}
+
+	if first == second {
+		t.Fatalf("repeated state %s", first)
+	}
+}
@@ -1,9 +1,53 @@
 package auth
 
+import "slices"
+
+const (
+	ScopeReadPlaybackState         = "user-read-playback-state"
+	ScopeModifyPlaybackState       = "user-modify-playback-state"
+	ScopeReadCurrentlyPlaying      = "user-read-currently-playing"
+	ScopeReadPrivate               = "user-read-private"
+	ScopePlaylistReadPrivate       = "playlist-read-private"
+	ScopePlaylistReadCollaborative = "playlist-read-collaborative"
+	ScopeReadRecentlyPlayed        = "user-read-recently-played"
+	ScopeTopRead                   = "user-top-read"
+	ScopePlaylistModifyPublic      = "playlist-modify-public"
+	ScopePlaylistModifyPrivate     = "playlist-modify-private"
+	ScopeLibraryRead               = "user-library-read"
+	ScopeLibraryModify             = "user-library-modify"
+	ScopeFollowRead                = "user-follow-read"
+	ScopeFollowModify              = "user-follow-modify"
+)
+
 var requiredScopes = []string{
-	"user-read-playback-state",
-	"user-modify-playback-state",
-	"user-read-currently-playing",
-	"playlist-read-private",
-	"user-read-private",
+	ScopeReadPlaybackState,
+	ScopeModifyPlaybackState,
+	ScopeReadCurrentlyPlaying,
+	ScopeReadPrivate,
+}
+
+func MergeScopes(scopes ...[]string) []string {
+	merged := []string{}
+
+	for _, set := range scopes {
+		for _, scope := range set {
+			if !slices.Contains(merged, scope) {
+				merged = append(merged, scope)
+			}
+		}
+	}
+
+	return merged
+}
+
+func MissingScopes(granted, needed []string) []string {
+	missing := []string{}
+
+	for _, scope := range needed {
+		if !slices.Contains(granted, scope) && !slices.Contains(missing, scope) {
+			missing = append(missing, scope)
+		}
+	}
+
+	return missing
 }
@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergeScopes(t *testing.T) {
+	merged := MergeScopes(
+		[]string{ScopeReadPrivate, ScopeReadPlaybackState},
+		[]string{ScopeReadPlaybackState, ScopePlaylistReadPrivate},
+		nil)
+
+	expected := []string{ScopeReadPrivate, ScopeReadPlaybackState, ScopePlaylistReadPrivate}
+	if !slices.Equal(merged, expected) {
+		t.Fatalf("invalid merged scopes. got=%v, expected=%v", merged, expected)
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	granted := []string{ScopeReadPrivate, ScopeReadPlaybackState}
+
+	missing := MissingScopes(granted, []string{ScopeReadPlaybackState, ScopePlaylistReadPrivate})
+
+	expected := []string{ScopePlaylistReadPrivate}
+	if !slices.Equal(missing, expected) {
+		t.Fatalf("invalid missing scopes. got=%v, expected=%v", missing, expected)
+	}
+
+	if missing := MissingScopes(granted, granted); len(missing) != 0 {
+		t.Fatalf("invalid missing scopes. got=%v, expected=[]", missing)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/franciscosbf/spotify-tui/internals/uri"
@@ -14,18 +15,31 @@ import (
 var (
 	ErrTokenRequestFailed   = errors.New("failed to request token")
 	ErrTokenInvalidResponse = errors.New("invalid token response")
+	ErrTokenRejected        = errors.New("token request rejected")
 )
 
+const expirationMargin = time.Minute * 10
+
 type Token struct {
 	Access    string
 	Refresh   string
-	ExpiresIn time.Duration
+	ExpiresAt time.Time
+	Scopes    []string
+}
+
+func (t Token) Expired() bool {
+	return t.Access == "" || !time.Now().Add(expirationMargin).Before(t.ExpiresAt)
+}
+
+func (t Token) RefreshIn() time.Duration {
+	return max(time.Until(t.ExpiresAt)-expirationMargin, 0)
 }
 
 type tokenResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
 }
 
 func requestToken(clientId string, parameters *url.Values) (Token, error) {
@@ -39,28 +53,39 @@ func requestToken(clientId string, parameters *url.Values) (Token, error) {
 	request, _ := http.NewRequest("POST", tokenUrl.String(), body)
 	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
+	requestedAt := time.Now()
+
 	client := &http.Client{}
 	response, err := client.Do(request)
-	if err != nil || response.StatusCode != 200 {
+	if err != nil {
 		return Token{}, ErrTokenRequestFailed
 	}
+	defer response.Body.Close()
+
+	if response.StatusCode != 200 {
+		return Token{}, ErrTokenRejected
+	}
 
 	var tokenMeta tokenResponse
-	json.NewDecoder(response.Body).Decode(&tokenMeta)
+	if err := json.NewDecoder(response.Body).Decode(&tokenMeta); err != nil ||
+		tokenMeta.AccessToken == "" {
+		return Token{}, ErrTokenInvalidResponse
+	}
 	token := Token{
 		Access:    tokenMeta.AccessToken,
 		Refresh:   tokenMeta.RefreshToken,
-		ExpiresIn: time.Second * time.Duration(tokenMeta.ExpiresIn),
+		ExpiresAt: requestedAt.Add(time.Second * time.Duration(tokenMeta.ExpiresIn)),
+		Scopes:    strings.Fields(tokenMeta.Scope),
 	}
 
 	return token, nil
 }
 
-func FetchToken(clientId, codeVerifier, code string) (Token, error) {
+func FetchToken(clientId, codeVerifier, code, redirectUri string) (Token, error) {
 	parameters := &url.Values{}
 	parameters.Set("grant_type", "authorization_code")
 	parameters.Set("code", code)
-	parameters.Set("redirect_uri", uri.REDIRECT)
+	parameters.Set("redirect_uri", redirectUri)
 	parameters.Set("code_verifier", codeVerifier)
 
 	return requestToken(clientId, parameters)
@@ -71,5 +96,14 @@ func RefreshToken(clientId, refreshToken string) (Token, error) {
 	parameters.Set("grant_type", "refresh_token")
 	parameters.Set("refresh_token", refreshToken)
 
-	return requestToken(clientId, parameters)
+	token, err := requestToken(clientId, parameters)
+	if err != nil {
+		return Token{}, err
+	}
+
+	if token.Refresh == "" {
+		token.Refresh = refreshToken
+	}
+
+	return token, nil
 }
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -15,13 +16,22 @@ func TestFetchTokenAndRefreshToken(t *testing.T) {
 
 	clientId := internalstest.GetClientId(t)
 
-	codeAuth := BuildCodeAuth(clientId, codeChallenge)
+	callback, err := ListenCallback(DefaultRedirect)
+	if err != nil {
+		t.Fatalf("failed to listen for callback: %s", err)
+	}
+	defer callback.Close()
+
+	codeAuth := BuildCodeAuth(clientId, codeChallenge, callback.RedirectUri(), nil)
 
 	if err := browser.OpenAuthLink(codeAuth.Url); err != nil {
 		t.Fatalf("failed to open browser with auth url: %s", err)
 	}
 
-	code, err := WaitForCode(codeAuth.State, timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	code, err := callback.WaitForCode(ctx, codeAuth.State)
 	if err != nil {
 		t.Fatalf("failed to wait for code: %s", err)
 	}
@@ -35,14 +45,16 @@ func TestFetchTokenAndRefreshToken(t *testing.T) {
 			t.Fatal("refresh token is empty")
 		}
 
-		expectedExpiresIn := time.Second * 3600
-		if token.ExpiresIn == expectedExpiresIn {
-			t.Fatalf("invalid expire time. got=%s, expected=%s",
-				token.ExpiresIn, expectedExpiresIn)
+		if token.Expired() {
+			t.Fatalf("token already expired. expires_at=%s", token.ExpiresAt)
+		}
+
+		if len(token.Scopes) == 0 {
+			t.Fatal("granted scopes are empty")
 		}
 	}
 
-	token, err := FetchToken(clientId, codeVerifier, code)
+	token, err := FetchToken(clientId, codeVerifier, code, callback.RedirectUri())
 	if err != nil {
 		t.Fatalf("failed to fetch token: %s", err)
 	}
@@ -54,3 +66,33 @@ func TestFetchTokenAndRefreshToken(t *testing.T) {
 	}
 	validateToken(token)
 }
+
+func TestTokenExpiration(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   Token
+		expired bool
+	}{
+		{"empty access", Token{ExpiresAt: time.Now().Add(time.Hour)}, true},
+		{"valid", Token{Access: "a", ExpiresAt: time.Now().Add(time.Hour)}, false},
+		{"close to expiry", Token{Access: "a", ExpiresAt: time.Now().Add(time.Minute)}, true},
+		{"expired", Token{Access: "a", ExpiresAt: time.Now().Add(-time.Minute)}, true},
+	}
+
+	for _, test := range tests {
+		if expired := test.token.Expired(); expired != test.expired {
+			t.Fatalf("%s: invalid expiration. got=%t, expected=%t",
+				test.name, expired, test.expired)
+		}
+	}
+
+	token := Token{Access: "a", ExpiresAt: time.Now().Add(time.Hour)}
+	if refreshIn := token.RefreshIn(); refreshIn <= 0 || refreshIn > time.Hour-expirationMargin {
+		t.Fatalf("invalid refresh time. got=%s", refreshIn)
+	}
+
+	token.ExpiresAt = time.Now()
+	if refreshIn := token.RefreshIn(); refreshIn != 0 {
+		t.Fatalf("invalid refresh time. got=%s, expected=0s", refreshIn)
+	}
+}
@@ -0,0 +1,211 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const Version = 1
+
+var (
+	ErrInvalidArchive     = errors.New("invalid backup archive")
+	ErrUnsupportedVersion = errors.New("backup made by a newer version")
+)
+
+const (
+	StageTracks    = "liked songs"
+	StageAlbums    = "albums"
+	StageArtists   = "artists"
+	StagePlaylists = "playlists"
+)
+
+type Progress func(stage string, done, total int)
+
+type Library interface {
+	GetUserProfile() (api.UserProfile, error)
+	GetSavedTracks() ([]api.SavedTrack, error)
+	SaveTracks(tracks []api.TimestampedId) error
+	GetSavedAlbums() ([]api.SavedAlbum, error)
+	SaveAlbums(ids []string) error
+	GetFollowedArtists() ([]api.Artist, error)
+	FollowArtists(ids []string) error
+	GetMyPlaylists() ([]api.Playlist, error)
+	GetPlaylistItems(playlistId string) ([]api.PlaylistItem, error)
+	CreatePlaylist(userId string, details api.PlaylistDetails) (api.Playlist, error)
+	AddPlaylistTracks(playlistId string, uris []string) (string, error)
+}
+
+type Account struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Item struct {
+	Uri     string    `json:"uri"`
+	Name    string    `json:"name"`
+	Artist  string    `json:"artist,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+	AddedBy string    `json:"added_by,omitempty"`
+}
+
+type Artist struct {
+	Uri  string `json:"uri"`
+	Name string `json:"name"`
+}
+
+type Playlist struct {
+	Id            string `json:"id"`
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	Public        bool   `json:"public"`
+	Collaborative bool   `json:"collaborative"`
+	Tracks        []Item `json:"tracks"`
+}
+
+type Archive struct {
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	Account   Account    `json:"account"`
+	Tracks    []Item     `json:"liked_songs"`
+	Albums    []Item     `json:"albums"`
+	Artists   []Artist   `json:"artists"`
+	Playlists []Playlist `json:"playlists"`
+}
+
+func (a Archive) key() string {
+	return fmt.Sprintf("%s@%s", a.Account.Id, a.CreatedAt.Format(time.RFC3339Nano))
+}
+
+func artistNames(artists []api.Artist) string {
+	names := make([]string, 0, len(artists))
+	for _, artist := range artists {
+		names = append(names, artist.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func Create(library Library, now time.Time, progress Progress) (Archive, error) {
+	profile, err := library.GetUserProfile()
+	if err != nil {
+		return Archive{}, err
+	}
+
+	archive := Archive{
+		Version:   Version,
+		CreatedAt: now.UTC(),
+		Account:   Account{Id: profile.Id, Name: profile.Name},
+		Tracks:    []Item{},
+		Albums:    []Item{},
+		Artists:   []Artist{},
+		Playlists: []Playlist{},
+	}
+
+	tracks, err := library.GetSavedTracks()
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, saved := range tracks {
+		archive.Tracks = append(archive.Tracks, Item{
+			Uri:     saved.Track.Uri,
+			Name:    saved.Track.Name,
+			Artist:  artistNames(saved.Track.Artists),
+			AddedAt: saved.AddedAt,
+		})
+	}
+	progress(StageTracks, len(tracks), len(tracks))
+
+	albums, err := library.GetSavedAlbums()
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, saved := range albums {
+		archive.Albums = append(archive.Albums, Item{
+			Uri:     saved.Album.Uri,
+			Name:    saved.Album.Name,
+			Artist:  artistNames(saved.Album.Artists),
+			AddedAt: saved.AddedAt,
+		})
+	}
+	progress(StageAlbums, len(albums), len(albums))
+
+	artists, err := library.GetFollowedArtists()
+	if err != nil {
+		return Archive{}, err
+	}
+	for _, artist := range artists {
+		archive.Artists = append(archive.Artists, Artist{Uri: artist.Uri, Name: artist.Name})
+	}
+	progress(StageArtists, len(artists), len(artists))
+
+	playlists, err := library.GetMyPlaylists()
+	if err != nil {
+		return Archive{}, err
+	}
+
+	owned := []api.Playlist{}
+	for _, playlist := range playlists {
+		if playlist.Owner.Id == profile.Id {
+			owned = append(owned, playlist)
+		}
+	}
+
+	for i, playlist := range owned {
+		items, err := library.GetPlaylistItems(playlist.Id)
+		if err != nil {
+			return Archive{}, err
+		}
+
+		archived := Playlist{
+			Id:            playlist.Id,
+			Name:          playlist.Name,
+			Description:   playlist.Description,
+			Public:        playlist.Public,
+			Collaborative: playlist.Collaborative,
+			Tracks:        make([]Item, 0, len(items)),
+		}
+		for _, item := range items {
+			archived.Tracks = append(archived.Tracks, Item{
+				Uri:     item.Track.Uri,
+				Name:    item.Track.Name,
+				Artist:  artistNames(item.Track.Artists),
+				AddedAt: item.AddedAt,
+				AddedBy: item.AddedBy.Id,
+			})
+		}
+		archive.Playlists = append(archive.Playlists, archived)
+
+		progress(StagePlaylists, i+1, len(owned))
+	}
+
+	return archive, nil
+}
+
+func Write(w io.Writer, archive Archive) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(archive)
+}
+
+func Read(r io.Reader) (Archive, error) {
+	var archive Archive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return Archive{}, fmt.Errorf("%w: %s", ErrInvalidArchive, err)
+	}
+
+	switch {
+	case archive.Version <= 0 || archive.Account.Id == "":
+		return Archive{}, fmt.Errorf("%w: missing version or account", ErrInvalidArchive)
+	case archive.Version > Version:
+		return Archive{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, archive.Version)
+	}
+
+	return archive, nil
+}
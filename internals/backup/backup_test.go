@@ -0,0 +1,239 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/apitest"
+)
+
+func noProgress(string, int, int) {}
+
+func track(id string) api.Track {
+	return api.Track{Id: id, Name: "Track " + id, Uri: "spotify:track:" + id}
+}
+
+func likedAt(id string) time.Time {
+	day := int(id[len(id)-1] - '0')
+
+	return time.Date(2022, 3, day, 18, 30, 0, 0, time.UTC)
+}
+
+func source() *apitest.FakeLibrary {
+	owner := api.UserProfile{Id: "alice", Name: "Alice"}
+	friend := api.UserProfile{Id: "bob"}
+
+	return &apitest.FakeLibrary{
+		Profile: owner,
+		Tracks: []api.SavedTrack{
+			{AddedAt: likedAt("t3"), Track: track("t3")},
+			{AddedAt: likedAt("t2"), Track: track("t2")},
+			{AddedAt: likedAt("t1"), Track: track("t1")},
+		},
+		Albums:  []api.SavedAlbum{{Album: api.Album{Id: "al1", Uri: "spotify:album:al1"}}},
+		Artists: []api.Artist{{Id: "ar1", Uri: "spotify:artist:ar1"}},
+		Playlists: []api.Playlist{
+			{Id: "mix", Name: "Mix", Owner: owner, Public: true},
+			{Id: "theirs", Name: "Theirs", Owner: friend},
+			{Id: "chill", Name: "Chill", Owner: owner, Collaborative: true},
+		},
+		Items: map[string][]api.PlaylistItem{
+			"mix": {
+				{Track: track("t1"), AddedBy: owner},
+				{Track: api.Track{Name: "Demo", Uri: "spotify:local:::Demo:120"}, AddedBy: owner},
+				{Track: track("t4"), AddedBy: friend},
+			},
+			"chill": {{Track: track("t5"), AddedBy: owner}},
+		},
+	}
+}
+
+func TestCreateArchive(t *testing.T) {
+	archive, err := Create(source(), time.Now(), noProgress)
+	if err != nil {
+		t.Fatalf("failed to create archive: %s", err)
+	}
+
+	if archive.Version != Version || archive.Account.Id != "alice" {
+		t.Fatalf("invalid header. got=version %d of %s, expected=version %d of alice", archive.Version, archive.Account.Id, Version)
+	}
+
+	if len(archive.Tracks) != 3 || len(archive.Albums) != 1 || len(archive.Artists) != 1 {
+		t.Fatalf("invalid library. got=%d tracks, %d albums and %d artists, expected=3, 1 and 1", len(archive.Tracks), len(archive.Albums), len(archive.Artists))
+	}
+
+	if len(archive.Playlists) != 2 || archive.Playlists[0].Id != "mix" || archive.Playlists[1].Id != "chill" {
+		t.Fatalf("invalid playlists. got=%+v, expected=the owned mix and chill", archive.Playlists)
+	}
+
+	if tracks := archive.Playlists[0].Tracks; len(tracks) != 3 || tracks[2].AddedBy != "bob" {
+		t.Fatalf("invalid tracks. got=%+v, expected=3 with the last added by bob", tracks)
+	}
+}
+
+func TestReadArchive(t *testing.T) {
+	archive, _ := Create(source(), time.Now(), noProgress)
+
+	var buffer bytes.Buffer
+	if err := Write(&buffer, archive); err != nil {
+		t.Fatalf("failed to write archive: %s", err)
+	}
+
+	read, err := Read(&buffer)
+	if err != nil {
+		t.Fatalf("failed to read archive: %s", err)
+	}
+
+	if read.key() != archive.key() || len(read.Playlists) != 2 {
+		t.Fatalf("invalid archive. got=%+v, expected=the written one", read)
+	}
+
+	newer := strings.NewReader(`{"version": 99, "account": {"id": "alice"}}`)
+	if _, err := Read(newer); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrUnsupportedVersion)
+	}
+
+	if _, err := Read(strings.NewReader(`{}`)); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidArchive)
+	}
+}
+
+func TestRestoreToAnotherAccount(t *testing.T) {
+	archive, _ := Create(source(), time.Now(), noProgress)
+
+	target := &apitest.FakeLibrary{Profile: api.UserProfile{Id: "carol"}}
+	journal := NewJournal(filepath.Join(t.TempDir(), "restore.journal"))
+
+	report, err := Restore(target, archive, journal, noProgress)
+	if err != nil {
+		t.Fatalf("failed to restore: %s", err)
+	}
+
+	if report.Tracks != 3 || report.Albums != 1 || report.Artists != 1 || report.Playlists != 2 {
+		t.Fatalf("invalid report. got=%+v, expected=3 tracks, 1 album, 1 artist and 2 playlists", report)
+	}
+
+	if calls := target.Calls(); !slices.Contains(calls, "SaveTracks t1,t2,t3") {
+		t.Fatalf("invalid calls. got=%v, expected=the oldest liked songs first", calls)
+	}
+
+	if len(target.Playlists) != 2 || target.Playlists[0].Name != "Mix" || !target.Playlists[1].Collaborative {
+		t.Fatalf("invalid playlists. got=%+v, expected=Mix and a collaborative one", target.Playlists)
+	}
+
+	if uris := target.Uris(target.Playlists[0].Id); !slices.Equal(uris, []string{"spotify:track:t1", "spotify:track:t4"}) {
+		t.Fatalf("invalid tracks. got=%v, expected=[spotify:track:t1 spotify:track:t4]", uris)
+	}
+
+	if len(report.Skipped) != 1 {
+		t.Fatalf("invalid skipped. got=%v, expected=the local track", report.Skipped)
+	}
+
+	if journal.Exists() {
+		t.Fatal("journal left behind")
+	}
+
+	again, err := Restore(target, archive, journal, noProgress)
+	if err != nil {
+		t.Fatalf("failed to restore again: %s", err)
+	}
+
+	if again.Tracks+again.Albums+again.Artists+again.Playlists+again.Added != 0 || len(target.Playlists) != 2 {
+		t.Fatalf("invalid second restore. got=%+v, expected=no changes", again)
+	}
+}
+
+func TestRestoreResumes(t *testing.T) {
+	archive, _ := Create(source(), time.Now(), noProgress)
+
+	target := &apitest.FakeLibrary{Profile: api.UserProfile{Id: "carol"}}
+	target.Fail = func(call string) error {
+		if strings.HasPrefix(call, "AddPlaylistTracks") {
+			return api.ErrResponse{Message: "Too many requests", Status: 429}
+		}
+		return nil
+	}
+
+	journal := NewJournal(filepath.Join(t.TempDir(), "restore.journal"))
+
+	if _, err := Restore(target, archive, journal, noProgress); !errors.Is(err, api.ErrRateLimited) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, api.ErrRateLimited)
+	}
+
+	if !journal.Exists() || len(target.Playlists) != 1 {
+		t.Fatalf("invalid playlists. got=%+v, expected=a journal and one created playlist", target.Playlists)
+	}
+
+	target.Fail = nil
+
+	report, err := Restore(target, archive, journal, noProgress)
+	if err != nil {
+		t.Fatalf("failed to resume restore: %s", err)
+	}
+
+	if report.Tracks != 0 || report.Playlists != 1 || len(target.Playlists) != 2 {
+		t.Fatalf("invalid report. got=%+v with playlists %+v, expected=1 more playlist", report, target.Playlists)
+	}
+
+	if target.Count("SaveTracks") != 1 || target.Count("GetSavedTracks") != 1 {
+		t.Fatalf("invalid calls. got=%v, expected=liked songs restored once", target.Calls())
+	}
+
+	for _, playlist := range target.Playlists {
+		if uris := target.Uris(playlist.Id); len(uris) == 0 {
+			t.Fatalf("invalid tracks of %s. got=[], expected=the archived ones", playlist.Name)
+		}
+	}
+}
+
+func TestRestoreSkipsChangedPlaylists(t *testing.T) {
+	library := source()
+	archive, _ := Create(library, time.Now(), noProgress)
+
+	library.Items["chill"] = append(library.Items["chill"], api.PlaylistItem{Track: track("t9")})
+	library.Items["mix"] = library.Items["mix"][:1]
+
+	report, err := Restore(library, archive, NewJournal(filepath.Join(t.TempDir(), "journal")), noProgress)
+	if err != nil {
+		t.Fatalf("failed to restore: %s", err)
+	}
+
+	if report.Playlists != 0 || report.Added != 1 {
+		t.Fatalf("invalid report. got=%+v, expected=no playlists and 1 added track", report)
+	}
+
+	if uris := library.Uris("mix"); len(uris) != 2 || uris[1] != "spotify:track:t4" {
+		t.Fatalf("invalid tracks. got=%v, expected=2 ending with spotify:track:t4", uris)
+	}
+
+	if len(report.Skipped) != 2 || !strings.Contains(report.Skipped[0], "Chill") {
+		t.Fatalf("invalid skipped. got=%v, expected=2 starting with Chill", report.Skipped)
+	}
+}
+
+func TestRestoreKeepsAddedAt(t *testing.T) {
+	archive, _ := Create(source(), time.Now(), noProgress)
+
+	target := &apitest.FakeLibrary{Profile: api.UserProfile{Id: "carol"}}
+	journal := NewJournal(filepath.Join(t.TempDir(), "restore.journal"))
+
+	if _, err := Restore(target, archive, journal, noProgress); err != nil {
+		t.Fatalf("failed to restore: %s", err)
+	}
+
+	if len(target.Tracks) != 3 {
+		t.Fatalf("invalid number of liked songs. got=%d, expected=3", len(target.Tracks))
+	}
+
+	for _, saved := range target.Tracks {
+		if expected := likedAt(saved.Track.Id); !saved.AddedAt.Equal(expected) {
+			t.Fatalf("invalid added_at of %s. got=%s, expected=%s", saved.Track.Id, saved.AddedAt, expected)
+		}
+	}
+}
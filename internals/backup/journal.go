@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+var ErrCorruptJournal = errors.New("corrupt restore journal")
+
+type progressState struct {
+	Archive   string            `json:"archive"`
+	Account   string            `json:"account"`
+	Tracks    bool              `json:"liked_songs"`
+	Albums    bool              `json:"albums"`
+	Artists   bool              `json:"artists"`
+	Playlists map[string]string `json:"playlists"`
+	Done      map[string]bool   `json:"done"`
+}
+
+func newProgressState(archive, account string) progressState {
+	return progressState{
+		Archive:   archive,
+		Account:   account,
+		Playlists: map[string]string{},
+		Done:      map[string]bool{},
+	}
+}
+
+type Journal struct {
+	path string
+}
+
+func NewJournal(path string) *Journal {
+	return &Journal{path: path}
+}
+
+func (j *Journal) Path() string {
+	return j.path
+}
+
+func (j *Journal) Exists() bool {
+	_, err := os.Stat(j.path)
+
+	return err == nil
+}
+
+func (j *Journal) load(archive, account string) (progressState, error) {
+	raw, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newProgressState(archive, account), nil
+	}
+	if err != nil {
+		return progressState{}, err
+	}
+
+	var state progressState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return progressState{}, ErrCorruptJournal
+	}
+
+	if state.Archive != archive || state.Account != account {
+		return newProgressState(archive, account), nil
+	}
+
+	if state.Playlists == nil {
+		state.Playlists = map[string]string{}
+	}
+	if state.Done == nil {
+		state.Done = map[string]bool{}
+	}
+
+	return state, nil
+}
+
+func (j *Journal) save(state progressState) error {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o700); err != nil {
+		return err
+	}
+
+	raw, _ := json.MarshalIndent(state, "", "  ")
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, j.path)
+}
+
+func (j *Journal) remove() error {
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
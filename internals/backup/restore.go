@@ -0,0 +1,292 @@
+package backup
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const (
+	libraryBatch  = 50
+	playlistBatch = 100
+)
+
+type Report struct {
+	Tracks    int
+	Albums    int
+	Artists   int
+	Playlists int
+	Added     int
+	Skipped   []string
+}
+
+func (r *Report) skip(format string, args ...any) {
+	r.Skipped = append(r.Skipped, fmt.Sprintf(format, args...))
+}
+
+func restorable(uri string) bool {
+	return strings.HasPrefix(uri, "spotify:track:") ||
+		strings.HasPrefix(uri, "spotify:episode:")
+}
+
+func missingIds(uris []string, present map[string]bool, kind string) []string {
+	ids := []string{}
+	seen := map[string]bool{}
+
+	for _, uri := range slices.Backward(uris) {
+		id, found := strings.CutPrefix(uri, "spotify:"+kind+":")
+		if !found || present[uri] || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func saveIds[T any](ids []T, save func([]T) error, stage string, progress Progress) error {
+	done := 0
+	progress(stage, done, len(ids))
+
+	for batch := range slices.Chunk(ids, libraryBatch) {
+		if err := save(batch); err != nil {
+			return err
+		}
+
+		done += len(batch)
+		progress(stage, done, len(ids))
+	}
+
+	return nil
+}
+
+func itemUris(items []Item) []string {
+	uris := make([]string, 0, len(items))
+	for _, item := range items {
+		uris = append(uris, item.Uri)
+	}
+
+	return uris
+}
+
+func restoreLibrary(library Library, archive Archive, state *progressState, report *Report, progress Progress) error {
+	if !state.Tracks {
+		saved, err := library.GetSavedTracks()
+		if err != nil {
+			return err
+		}
+
+		present := map[string]bool{}
+		for _, track := range saved {
+			present[track.Track.Uri] = true
+		}
+
+		addedAt := map[string]time.Time{}
+		for _, item := range archive.Tracks {
+			addedAt[item.Uri] = item.AddedAt
+		}
+
+		ids := missingIds(itemUris(archive.Tracks), present, "track")
+
+		tracks := make([]api.TimestampedId, 0, len(ids))
+		for _, id := range ids {
+			at := addedAt["spotify:track:"+id]
+			if at.IsZero() {
+				at = time.Now()
+			}
+
+			tracks = append(tracks, api.TimestampedId{Id: id, AddedAt: at})
+		}
+
+		if err := saveIds(tracks, library.SaveTracks, StageTracks, progress); err != nil {
+			return err
+		}
+
+		report.Tracks = len(ids)
+		state.Tracks = true
+	}
+
+	if !state.Albums {
+		saved, err := library.GetSavedAlbums()
+		if err != nil {
+			return err
+		}
+
+		present := map[string]bool{}
+		for _, album := range saved {
+			present[album.Album.Uri] = true
+		}
+
+		ids := missingIds(itemUris(archive.Albums), present, "album")
+		if err := saveIds(ids, library.SaveAlbums, StageAlbums, progress); err != nil {
+			return err
+		}
+
+		report.Albums = len(ids)
+		state.Albums = true
+	}
+
+	if !state.Artists {
+		followed, err := library.GetFollowedArtists()
+		if err != nil {
+			return err
+		}
+
+		present := map[string]bool{}
+		for _, artist := range followed {
+			present[artist.Uri] = true
+		}
+
+		uris := make([]string, 0, len(archive.Artists))
+		for _, artist := range archive.Artists {
+			uris = append(uris, artist.Uri)
+		}
+
+		ids := missingIds(uris, present, "artist")
+		if err := saveIds(ids, library.FollowArtists, StageArtists, progress); err != nil {
+			return err
+		}
+
+		report.Artists = len(ids)
+		state.Artists = true
+	}
+
+	return nil
+}
+
+func restorePlaylist(library Library, profile api.UserProfile, owned []api.Playlist,
+	archived Playlist, state *progressState, report *Report, journal *Journal,
+) error {
+	uris := []string{}
+	for _, uri := range itemUris(archived.Tracks) {
+		if restorable(uri) {
+			uris = append(uris, uri)
+		}
+	}
+	if skipped := len(archived.Tracks) - len(uris); skipped > 0 {
+		report.skip("%d local or unavailable tracks of %s", skipped, archived.Name)
+	}
+
+	find := func(match func(api.Playlist) bool) (string, bool) {
+		if i := slices.IndexFunc(owned, match); i >= 0 {
+			return owned[i].Id, true
+		}
+		return "", false
+	}
+
+	id, known := find(func(p api.Playlist) bool { return p.Id == state.Playlists[archived.Id] })
+	if !known {
+		id, known = find(func(p api.Playlist) bool { return p.Id == archived.Id })
+	}
+	if !known {
+		id, known = find(func(p api.Playlist) bool { return p.Name == archived.Name })
+	}
+
+	current := []string{}
+
+	if known {
+		items, err := library.GetPlaylistItems(id)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if restorable(item.Track.Uri) {
+				current = append(current, item.Track.Uri)
+			}
+		}
+	} else {
+		playlist, err := library.CreatePlaylist(profile.Id, api.PlaylistDetails{
+			Name:          archived.Name,
+			Description:   archived.Description,
+			Public:        archived.Public && !archived.Collaborative,
+			Collaborative: archived.Collaborative,
+		})
+		if err != nil {
+			return err
+		}
+
+		id = playlist.Id
+		report.Playlists++
+
+		state.Playlists[archived.Id] = id
+		if err := journal.save(*state); err != nil {
+			return err
+		}
+	}
+
+	if len(current) > len(uris) || !slices.Equal(current, uris[:len(current)]) {
+		report.skip("%s, it changed since the backup", archived.Name)
+		return nil
+	}
+
+	for batch := range slices.Chunk(uris[len(current):], playlistBatch) {
+		if _, err := library.AddPlaylistTracks(id, batch); err != nil {
+			return err
+		}
+
+		report.Added += len(batch)
+	}
+
+	return nil
+}
+
+func Restore(library Library, archive Archive, journal *Journal, progress Progress) (Report, error) {
+	report := Report{}
+
+	profile, err := library.GetUserProfile()
+	if err != nil {
+		return report, err
+	}
+
+	state, err := journal.load(archive.key(), profile.Id)
+	if err != nil {
+		return report, err
+	}
+
+	err = restoreLibrary(library, archive, &state, &report, progress)
+	if saveErr := journal.save(state); err == nil {
+		err = saveErr
+	}
+	if err != nil {
+		return report, err
+	}
+
+	playlists, err := library.GetMyPlaylists()
+	if err != nil {
+		return report, err
+	}
+
+	owned := []api.Playlist{}
+	for _, playlist := range playlists {
+		if playlist.Owner.Id == profile.Id {
+			owned = append(owned, playlist)
+		}
+	}
+
+	done := 0
+	progress(StagePlaylists, done, len(archive.Playlists))
+
+	for _, archived := range slices.Backward(archive.Playlists) {
+		if !state.Done[archived.Id] {
+			if err := restorePlaylist(library, profile, owned, archived, &state, &report, journal); err != nil {
+				return report, err
+			}
+
+			state.Done[archived.Id] = true
+			if err := journal.save(state); err != nil {
+				return report, err
+			}
+		}
+
+		done++
+		progress(StagePlaylists, done, len(archive.Playlists))
+	}
+
+	return report, journal.remove()
+}
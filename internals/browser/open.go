@@ -2,12 +2,21 @@ package browser
 
 import (
 	"errors"
+	"os"
 
 	"github.com/pkg/browser"
 )
 
 var ErrBrowser = errors.New("failed to open browser with authorization link")
 
+func Available() bool {
+	if os.Getenv("SSH_CONNECTION") == "" && os.Getenv("SSH_TTY") == "" {
+		return true
+	}
+
+	return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+}
+
 func OpenAuthLink(authUrl string) error {
 	if err := browser.OpenURL(authUrl); err != nil {
 		return ErrBrowser
@@ -4,16 +4,111 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"time"
 )
 
+const DefaultProfile = "default"
+
+type Profile struct {
+	Name         string    `json:"name"`
+	ClientId     string    `json:"client_id"`
+	RefreshToken string    `json:"refresh_token"`
+	AccessToken  string    `json:"access_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitzero"`
+	Scopes       []string  `json:"scopes,omitempty"`
+}
+
+type Redirect struct {
+	Host          string `json:"host,omitempty"`
+	Port          int    `json:"port,omitempty"`
+	FallbackPorts []int  `json:"fallback_ports,omitempty"`
+}
+
+type Remote struct {
+	Address string `json:"address,omitempty"`
+	Token   string `json:"token,omitempty"`
+}
+
+type Party struct {
+	Address       string `json:"address,omitempty"`
+	MaxRequests   int    `json:"max_requests,omitempty"`
+	Window        int    `json:"window,omitempty"`
+	AllowExplicit bool   `json:"allow_explicit,omitempty"`
+}
+
+type Webhook struct {
+	Url     string            `json:"url"`
+	Events  []string          `json:"events,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Retries int               `json:"retries,omitempty"`
+	Timeout int               `json:"timeout,omitempty"`
+}
+
+type ExecHook struct {
+	Command string   `json:"command"`
+	Events  []string `json:"events,omitempty"`
+	Timeout int      `json:"timeout,omitempty"`
+}
+
+type Hooks struct {
+	Webhooks      []Webhook  `json:"webhooks,omitempty"`
+	Exec          []ExecHook `json:"exec,omitempty"`
+	MaxConcurrent int        `json:"max_concurrent,omitempty"`
+}
+
+type LastFm struct {
+	ApiKey     string `json:"api_key,omitempty"`
+	Secret     string `json:"secret,omitempty"`
+	SessionKey string `json:"session_key,omitempty"`
+}
+
+type ListenBrainz struct {
+	Token string `json:"token,omitempty"`
+}
+
+type Scrobble struct {
+	LastFm       LastFm       `json:"lastfm,omitzero"`
+	ListenBrainz ListenBrainz `json:"listenbrainz,omitzero"`
+}
+
+type SmartRule struct {
+	Source    string `json:"source"`
+	Days      int    `json:"days,omitempty"`
+	TimeRange string `json:"time_range,omitempty"`
+	Playlist  string `json:"playlist,omitempty"`
+}
+
+type SmartPlaylist struct {
+	Name           string      `json:"name"`
+	Description    string      `json:"description,omitempty"`
+	Public         bool        `json:"public,omitempty"`
+	Include        []SmartRule `json:"include"`
+	Exclude        []SmartRule `json:"exclude,omitempty"`
+	Released       string      `json:"released,omitempty"`
+	NoExplicit     bool        `json:"no_explicit,omitempty"`
+	ExcludeArtists []string    `json:"exclude_artists,omitempty"`
+	Sort           string      `json:"sort,omitempty"`
+	Descending     bool        `json:"descending,omitempty"`
+	Limit          int         `json:"limit,omitempty"`
+	Refresh        int         `json:"refresh,omitempty"`
+}
+
 type Config struct {
-	ClientId     string `json:"client_id"`
-	RefreshToken string `json:"refresh_token"`
+	Profile     string    `json:"profile"`
+	Profiles    []Profile `json:"profiles"`
+	Redirect    Redirect  `json:"redirect,omitzero"`
+	AuthTimeout int       `json:"auth_timeout,omitempty"`
+	Remote      Remote    `json:"remote,omitzero"`
+	Party       Party     `json:"party,omitzero"`
+	Hooks       Hooks     `json:"hooks,omitzero"`
+	Scrobble    Scrobble  `json:"scrobble,omitzero"`
+
+	SmartPlaylists []SmartPlaylist `json:"smart_playlists,omitempty"`
 }
 
 var (
 	ErrFailedToReadConfig  = errors.New("failed to read config file")
-	ErrFailedToWriteConfig = errors.New("failed to  writeconfig file")
+	ErrFailedToWriteConfig = errors.New("failed to write config file")
 	ErrInvalidConfig       = errors.New("config file is invalid")
 )
 
@@ -33,7 +128,7 @@ func parse(path string, config any) error {
 func write(path string, config any) error {
 	raw, _ := json.MarshalIndent(config, "", "  ")
 
-	if err := os.WriteFile(path, raw, 0644); err != nil {
+	if err := os.WriteFile(path, raw, 0600); err != nil {
 		return ErrFailedToWriteConfig
 	}
 
@@ -41,13 +136,26 @@ func write(path string, config any) error {
 }
 
 func Parse(path string) (Config, error) {
-	var auth Config
+	var auth struct {
+		Config
+		Profile
+	}
 
 	if err := parse(path, &auth); err != nil {
 		return Config{}, err
 	}
 
-	return auth, nil
+	conf := auth.Config
+
+	if conf.Profiles == nil {
+		legacy := auth.Profile
+		legacy.Name = DefaultProfile
+
+		conf.Profile = DefaultProfile
+		conf.Profiles = []Profile{legacy}
+	}
+
+	return conf, nil
 }
 
 func Write(path string, auth Config) error {
@@ -2,7 +2,10 @@ package config
 
 import (
 	"os"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func prepareTempFile(pattern string, data string, t *testing.T) string {
@@ -35,27 +38,117 @@ func TestParseAuth(t *testing.T) {
 		t.Fatalf("failed to parse auth: %s", err)
 	}
 
-	if conf.ClientId == "" {
+	if conf.Profile != DefaultProfile {
+		t.Fatalf("invalid profile. got=%s, expected=%s",
+			conf.Profile, DefaultProfile)
+	}
+
+	if len(conf.Profiles) != 1 {
+		t.Fatalf("invalid number of profiles. got=%d, expected=1", len(conf.Profiles))
+	}
+
+	profile := conf.Profiles[0]
+
+	if profile.Name != DefaultProfile {
+		t.Fatalf("invalid profile name. got=%s, expected=%s",
+			profile.Name, DefaultProfile)
+	}
+
+	if profile.ClientId == "" {
 		t.Fatalf("missing client_id")
 	}
-	if conf.ClientId != expectedClientId {
+	if profile.ClientId != expectedClientId {
 		t.Fatalf("invalid client_id. got=%s, expected=%s",
-			conf.ClientId, expectedClientId)
+			profile.ClientId, expectedClientId)
 	}
 
-	if conf.RefreshToken == "" {
+	if profile.RefreshToken == "" {
 		t.Fatalf("missing refresh_token")
 	}
-	if conf.RefreshToken != expectedRefreshToken {
+	if profile.RefreshToken != expectedRefreshToken {
 		t.Fatalf("invalid refresh_token. got=%s, expected=%s",
-			conf.RefreshToken, expectedRefreshToken)
+			profile.RefreshToken, expectedRefreshToken)
+	}
+}
+
+func TestParseEmptyLegacyAuth(t *testing.T) {
+	filename := prepareTempFile("TestParseEmptyLegacyAuth", `{"client_id": "", "refresh_token": ""}`, t)
+
+	conf, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("failed to parse auth: %s", err)
+	}
+
+	if len(conf.Profiles) != 1 {
+		t.Fatalf("invalid number of profiles. got=%d, expected=1", len(conf.Profiles))
+	}
+
+	if profile := conf.Profiles[0]; profile.Name != DefaultProfile || profile.ClientId != "" {
+		t.Fatalf("invalid profile. got=%+v, expected=%s without client_id", profile, DefaultProfile)
+	}
+
+	filename = prepareTempFile("TestParseEmptyLegacyAuth", `{"profiles": []}`, t)
+
+	conf, err = Parse(filename)
+	if err != nil {
+		t.Fatalf("failed to parse profiles: %s", err)
+	}
+
+	if len(conf.Profiles) != 0 {
+		t.Fatalf("invalid number of profiles. got=%d, expected=0", len(conf.Profiles))
+	}
+}
+
+func TestParseProfiles(t *testing.T) {
+	data := `{
+		"profile": "work",
+		"profiles": [
+			{"name": "personal", "client_id": "b3j4b5j3", "refresh_token": "n4k3n5k3"},
+			{"name": "work", "client_id": "v4395hb49b4b", "refresh_token": ""}
+		]
+	}`
+	filename := prepareTempFile("TestParseProfiles", data, t)
+
+	conf, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("failed to parse profiles: %s", err)
+	}
+
+	if conf.Profile != "work" {
+		t.Fatalf("invalid profile. got=%s, expected=work", conf.Profile)
+	}
+
+	expectedProfiles := []Profile{
+		{Name: "personal", ClientId: "b3j4b5j3", RefreshToken: "n4k3n5k3"},
+		{Name: "work", ClientId: "v4395hb49b4b"},
+	}
+
+	if len(conf.Profiles) != len(expectedProfiles) {
+		t.Fatalf("invalid number of profiles. got=%d, expected=%d",
+			len(conf.Profiles), len(expectedProfiles))
+	}
+
+	for i, expected := range expectedProfiles {
+		profile := conf.Profiles[i]
+
+		if profile.Name != expected.Name ||
+			profile.ClientId != expected.ClientId ||
+			profile.RefreshToken != expected.RefreshToken {
+			t.Fatalf("invalid profile %d. got=%+v, expected=%+v", i, profile, expected)
+		}
 	}
 }
 
 func TestPersistAuth(t *testing.T) {
 	data := Config{
-		ClientId:     "v4395hb49b4b",
-		RefreshToken: "v3rb45jh549h84",
+		Profile: "personal",
+		Profiles: []Profile{
+			{
+				Name:         "personal",
+				ClientId:     "v4395hb49b4b",
+				RefreshToken: "v3rb45jh549h84",
+			},
+		},
 	}
 
 	filename := prepareTempFile("TestStoreAuth", "", t)
@@ -64,24 +157,90 @@ func TestPersistAuth(t *testing.T) {
 		t.Fatalf("failed to store file: %s", err)
 	}
 
+	raw, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file: %s", err)
+	}
+
+	for _, field := range []string{"expires_at", "redirect", "remote", "party", "hooks", "scrobble"} {
+		if strings.Contains(string(raw), `"`+field+`"`) {
+			t.Fatalf("invalid stored config. got=%s, expected=no %s field", raw, field)
+		}
+	}
+
 	conf, err := Parse(filename)
 	if err != nil {
 		t.Fatalf("failed to parse auth: %s", err)
 	}
 
-	if conf.ClientId == "" {
+	if conf.Profile != data.Profile {
+		t.Fatalf("invalid profile. got=%s, expected=%s",
+			conf.Profile, data.Profile)
+	}
+
+	if len(conf.Profiles) != 1 {
+		t.Fatalf("invalid number of profiles. got=%d, expected=1", len(conf.Profiles))
+	}
+
+	profile, expected := conf.Profiles[0], data.Profiles[0]
+
+	if profile.ClientId == "" {
 		t.Fatalf("missing client_id")
 	}
-	if conf.ClientId != data.ClientId {
+	if profile.ClientId != expected.ClientId {
 		t.Fatalf("invalid client_id. got=%s, expected=%s",
-			conf.ClientId, data.ClientId)
+			profile.ClientId, expected.ClientId)
 	}
 
-	if conf.RefreshToken == "" {
+	if profile.RefreshToken == "" {
 		t.Fatalf("missing refresh_token")
 	}
-	if conf.RefreshToken != data.RefreshToken {
+	if profile.RefreshToken != expected.RefreshToken {
 		t.Fatalf("invalid refresh_token. got=%s, expected=%s",
-			conf.RefreshToken, conf.RefreshToken)
+			profile.RefreshToken, expected.RefreshToken)
+	}
+}
+
+func TestPersistToken(t *testing.T) {
+	data := Config{
+		Profile: DefaultProfile,
+		Profiles: []Profile{
+			{
+				Name:         DefaultProfile,
+				ClientId:     "v4395hb49b4b",
+				RefreshToken: "v3rb45jh549h84",
+				AccessToken:  "b4j5b3j4b5j3b4",
+				ExpiresAt:    time.Date(2024, 10, 20, 12, 0, 0, 0, time.UTC),
+				Scopes:       []string{"user-read-private", "playlist-read-private"},
+			},
+		},
+	}
+
+	filename := prepareTempFile("TestPersistToken", "", t)
+
+	if err := Write(filename, data); err != nil {
+		t.Fatalf("failed to store file: %s", err)
+	}
+
+	conf, err := Parse(filename)
+	if err != nil {
+		t.Fatalf("failed to parse auth: %s", err)
+	}
+
+	profile, expected := conf.Profiles[0], data.Profiles[0]
+
+	if profile.AccessToken != expected.AccessToken {
+		t.Fatalf("invalid access_token. got=%s, expected=%s",
+			profile.AccessToken, expected.AccessToken)
+	}
+
+	if !profile.ExpiresAt.Equal(expected.ExpiresAt) {
+		t.Fatalf("invalid expires_at. got=%s, expected=%s",
+			profile.ExpiresAt, expected.ExpiresAt)
+	}
+
+	if !slices.Equal(profile.Scopes, expected.Scopes) {
+		t.Fatalf("invalid scopes. got=%v, expected=%v",
+			profile.Scopes, expected.Scopes)
 	}
 }
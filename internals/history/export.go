@@ -0,0 +1,59 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCsv  = "csv"
+	FormatJson = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+var csvHeader = []string{
+	"started_at", "ended_at", "uri", "title", "artists", "album", "context",
+	"device", "device_type", "duration_ms", "played_ms", "skipped",
+}
+
+func Export(w io.Writer, format string, plays []Play) error {
+	switch format {
+	case FormatJson:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(plays)
+	case FormatCsv:
+		writer := csv.NewWriter(w)
+		writer.Write(csvHeader)
+
+		for _, play := range plays {
+			writer.Write([]string{
+				play.StartedAt.Format(time.RFC3339),
+				play.EndedAt.Format(time.RFC3339),
+				play.Uri,
+				play.Title,
+				strings.Join(play.Artists, "; "),
+				play.Album,
+				play.Context,
+				play.Device,
+				play.DeviceType,
+				strconv.Itoa(play.DurationMs),
+				strconv.Itoa(play.PlayedMs),
+				strconv.FormatBool(play.Skipped),
+			})
+		}
+
+		writer.Flush()
+
+		return writer.Error()
+	}
+
+	return ErrUnknownFormat
+}
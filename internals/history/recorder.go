@@ -0,0 +1,116 @@
+package history
+
+import (
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const skipMargin = 10 * time.Second
+
+type Play struct {
+	Uri        string    `json:"uri"`
+	Title      string    `json:"title"`
+	Artists    []string  `json:"artists"`
+	Album      string    `json:"album"`
+	Context    string    `json:"context"`
+	Device     string    `json:"device"`
+	DeviceType string    `json:"device_type"`
+	StartedAt  time.Time `json:"started_at"`
+	EndedAt    time.Time `json:"ended_at"`
+	DurationMs int       `json:"duration_ms"`
+	PlayedMs   int       `json:"played_ms"`
+	Skipped    bool      `json:"skipped"`
+}
+
+func (p Play) Artist() string {
+	if len(p.Artists) == 0 {
+		return ""
+	}
+
+	return p.Artists[0]
+}
+
+type Recorder struct {
+	current      *Play
+	playing      bool
+	lastAt       time.Time
+	lastProgress int
+}
+
+func newPlay(snapshot playback.Snapshot, now time.Time) *Play {
+	state := snapshot.State
+
+	artists := make([]string, 0, len(state.Item.Artists))
+	for _, artist := range state.Item.Artists {
+		artists = append(artists, artist.Name)
+	}
+
+	return &Play{
+		Uri:        state.Item.Uri,
+		Title:      state.Item.Name,
+		Artists:    artists,
+		Album:      state.Item.Album.Name,
+		Context:    state.Context.Uri,
+		Device:     state.Device.Name,
+		DeviceType: state.Device.Type,
+		StartedAt:  now.Add(-time.Duration(state.ProgressMs) * time.Millisecond),
+		DurationMs: state.Item.DurationMs,
+	}
+}
+
+func (r *Recorder) finish(now time.Time, skipped bool) *Play {
+	play := r.current
+	r.current = nil
+
+	if play == nil || play.PlayedMs == 0 {
+		return nil
+	}
+
+	play.EndedAt = now
+	play.Skipped = skipped && r.lastProgress < play.DurationMs-int(skipMargin.Milliseconds())
+
+	return play
+}
+
+func (r *Recorder) Observe(snapshot playback.Snapshot, now time.Time) *Play {
+	uri := ""
+	if snapshot.Active {
+		uri = snapshot.State.Item.Uri
+	}
+	progress := snapshot.State.ProgressMs
+
+	var finished *Play
+
+	if r.current != nil && r.current.Uri == uri && r.playing {
+		if playback.Restarted(r.lastProgress, r.lastAt, progress, r.current.DurationMs, now) {
+			restartedAt := now.Add(-time.Duration(progress) * time.Millisecond)
+			rest := playback.Listened(r.lastProgress, r.lastAt, r.current.DurationMs, restartedAt)
+
+			r.current.PlayedMs += int(rest.Milliseconds())
+			finished = r.finish(restartedAt, false)
+
+			r.current = newPlay(snapshot, now)
+			r.current.PlayedMs = progress
+		} else {
+			r.current.PlayedMs += int(playback.Listened(r.lastProgress, r.lastAt, progress, now).Milliseconds())
+		}
+	}
+
+	if r.current != nil && r.current.Uri != uri {
+		finished = r.finish(now, uri != "")
+	}
+
+	if r.current == nil && uri != "" {
+		r.current = newPlay(snapshot, now)
+	}
+
+	r.playing = snapshot.Active && snapshot.State.IsPlaying
+	r.lastAt, r.lastProgress = now, progress
+
+	return finished
+}
+
+func (r *Recorder) Close(now time.Time) *Play {
+	return r.finish(now, false)
+}
@@ -0,0 +1,122 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+func snapshot(uri string, progressMs int, playing bool) playback.Snapshot {
+	return playback.Snapshot{
+		Active: true,
+		State: api.PlaybackState{
+			IsPlaying:  playing,
+			ProgressMs: progressMs,
+			Context:    api.Context{Uri: "spotify:playlist:mix"},
+			Device:     api.Device{Name: "Phone", Type: "Smartphone"},
+			Item: api.Track{
+				Uri:        uri,
+				Name:       "Song " + uri,
+				DurationMs: 60_000,
+				Artists:    []api.Artist{{Name: "Band"}, {Name: "Guest"}},
+				Album:      api.Album{Name: "Album"},
+			},
+		},
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	var recorder Recorder
+
+	finished := []*Play{}
+	observe := func(seconds int, s playback.Snapshot) {
+		if play := recorder.Observe(s, at(seconds)); play != nil {
+			finished = append(finished, play)
+		}
+	}
+
+	observe(0, snapshot("a", 2_000, true))
+	for i := 1; i <= 6; i++ {
+		observe(i*10, snapshot("a", 2_000+i*10_000, true))
+	}
+	observe(61, snapshot("b", 0, true))
+	observe(71, snapshot("b", 10_000, true))
+	observe(72, snapshot("c", 0, true))
+	observe(80, playback.Snapshot{})
+
+	if len(finished) != 2 {
+		t.Fatalf("invalid finished plays. got=%d, expected=2", len(finished))
+	}
+
+	first := finished[0]
+	if first.Uri != "a" || first.Skipped || first.PlayedMs != 60_000 ||
+		!first.StartedAt.Equal(at(-2)) || !first.EndedAt.Equal(at(61)) {
+		t.Fatalf("invalid first play. got=%+v, expected=a played for 60s", first)
+	}
+
+	if first.Context != "spotify:playlist:mix" || first.Device != "Phone" || first.Artist() != "Band" {
+		t.Fatalf("invalid first play metadata. got=%+v, expected=Band on Phone from the mix", first)
+	}
+
+	if second := finished[1]; second.Uri != "b" || !second.Skipped || second.PlayedMs != 10_000 {
+		t.Fatalf("invalid second play. got=%+v, expected=b skipped after 10s", second)
+	}
+
+	if play := recorder.Close(at(90)); play != nil {
+		t.Fatalf("invalid play on close. got=%+v, expected=nil for a track that never played", play)
+	}
+}
+
+func TestRecorderClose(t *testing.T) {
+	var recorder Recorder
+	start := time.Now()
+
+	recorder.Observe(snapshot("a", 0, true), start)
+	recorder.Observe(snapshot("a", 5_000, true), start.Add(5*time.Second))
+
+	play := recorder.Close(start.Add(6 * time.Second))
+	if play == nil || play.Skipped || play.PlayedMs != 5_000 {
+		t.Fatalf("invalid play on close. got=%+v, expected=a played for 5s", play)
+	}
+}
+
+func TestRecorderRepeat(t *testing.T) {
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	var recorder Recorder
+
+	finished := []*Play{}
+	observe := func(seconds int, s playback.Snapshot) {
+		if play := recorder.Observe(s, at(seconds)); play != nil {
+			finished = append(finished, play)
+		}
+	}
+
+	for seconds := 0; seconds <= 190; seconds += 10 {
+		observe(seconds, snapshot("a", seconds%60*1_000, true))
+	}
+	observe(195, snapshot("a", 0, true))
+	observe(200, snapshot("b", 0, true))
+
+	if len(finished) != 4 {
+		t.Fatalf("invalid number of plays. got=%d, expected=4", len(finished))
+	}
+
+	for i, expected := range []int{60_000, 60_000, 60_000, 10_000} {
+		play := finished[i]
+
+		if play.Uri != "a" || play.Skipped != (i == 3) || play.PlayedMs != expected {
+			t.Fatalf("invalid play %d. got=%+v, expected %dms of a", i, play, expected)
+		}
+
+		if startedAt := at(i * 60); !play.StartedAt.Equal(startedAt) {
+			t.Fatalf("invalid start of play %d. got=%s, expected=%s", i, play.StartedAt, startedAt)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package history
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+const topLimit = 50
+
+type Period int
+
+const (
+	Daily Period = iota
+	Weekly
+)
+
+const (
+	dailyBuckets  = 7
+	weeklyBuckets = 12
+)
+
+type Count struct {
+	Name     string
+	Plays    int
+	Skips    int
+	PlayedMs int
+}
+
+func (c Count) SkipRate() float64 {
+	if c.Plays == 0 {
+		return 0
+	}
+
+	return float64(c.Skips) / float64(c.Plays)
+}
+
+type Bucket struct {
+	Start    time.Time
+	PlayedMs int
+}
+
+type Stats struct {
+	Total   Count
+	Tracks  []Count
+	Artists []Count
+	Buckets []Bucket
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (p Period) Buckets(now time.Time) []time.Time {
+	if p == Weekly {
+		monday := midnight(now)
+		monday = monday.AddDate(0, 0, -(int(monday.Weekday())+6)%7)
+
+		starts := make([]time.Time, weeklyBuckets)
+		for i := range starts {
+			starts[i] = monday.AddDate(0, 0, -7*(weeklyBuckets-1-i))
+		}
+
+		return starts
+	}
+
+	today := midnight(now)
+
+	starts := make([]time.Time, dailyBuckets)
+	for i := range starts {
+		starts[i] = today.AddDate(0, 0, -(dailyBuckets - 1 - i))
+	}
+
+	return starts
+}
+
+func (p Period) Since(now time.Time) time.Time {
+	return p.Buckets(now)[0]
+}
+
+func (c *Count) add(play Play) {
+	c.Plays++
+	c.PlayedMs += play.PlayedMs
+	if play.Skipped {
+		c.Skips++
+	}
+}
+
+func count(counts map[string]*Count, name string, play Play) {
+	entry, ok := counts[name]
+	if !ok {
+		entry = &Count{Name: name}
+		counts[name] = entry
+	}
+
+	entry.add(play)
+}
+
+func top(counts map[string]*Count) []Count {
+	result := make([]Count, 0, len(counts))
+	for _, entry := range counts {
+		result = append(result, *entry)
+	}
+
+	slices.SortFunc(result, func(a, b Count) int {
+		return cmp.Or(
+			cmp.Compare(b.Plays, a.Plays),
+			cmp.Compare(b.PlayedMs, a.PlayedMs),
+			cmp.Compare(a.Name, b.Name))
+	})
+
+	return result[:min(len(result), topLimit)]
+}
+
+func Summarize(plays []Play, starts []time.Time) Stats {
+	stats := Stats{Total: Count{Name: "total"}}
+
+	tracks := map[string]*Count{}
+	artists := map[string]*Count{}
+	trackNames := map[string]string{}
+
+	for _, start := range starts {
+		stats.Buckets = append(stats.Buckets, Bucket{Start: start})
+	}
+
+	for _, play := range plays {
+		if len(starts) > 0 && play.StartedAt.Before(starts[0]) {
+			continue
+		}
+
+		stats.Total.add(play)
+
+		if _, ok := trackNames[play.Uri]; !ok {
+			trackNames[play.Uri] = play.Title
+			if artist := play.Artist(); artist != "" {
+				trackNames[play.Uri] += " - " + artist
+			}
+		}
+		count(tracks, play.Uri, play)
+
+		for _, artist := range play.Artists {
+			count(artists, artist, play)
+		}
+
+		for i := len(stats.Buckets) - 1; i >= 0; i-- {
+			if !play.StartedAt.Before(stats.Buckets[i].Start) {
+				stats.Buckets[i].PlayedMs += play.PlayedMs
+				break
+			}
+		}
+	}
+
+	stats.Tracks = top(tracks)
+	for i := range stats.Tracks {
+		stats.Tracks[i].Name = trackNames[stats.Tracks[i].Name]
+	}
+	stats.Artists = top(artists)
+
+	return stats
+}
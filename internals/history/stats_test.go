@@ -0,0 +1,58 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuckets(t *testing.T) {
+	now := time.Date(2024, 5, 1, 15, 30, 0, 0, time.UTC)
+
+	daily := Daily.Buckets(now)
+	if len(daily) != 7 || !daily[6].Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) ||
+		!daily[0].Equal(time.Date(2024, 4, 25, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("invalid daily buckets. got=%v, expected=7 days up to 2024-05-01", daily)
+	}
+
+	weekly := Weekly.Buckets(now)
+	if len(weekly) != 12 || weekly[11].Weekday() != time.Monday ||
+		!weekly[11].Equal(time.Date(2024, 4, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("invalid weekly buckets. got=%v, expected=12 weeks up to Monday 2024-04-29", weekly)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2024, 5, 1, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+
+	plays := []Play{
+		{Uri: "a", Title: "A", Artists: []string{"Band"}, StartedAt: yesterday, PlayedMs: 60_000},
+		{Uri: "a", Title: "A", Artists: []string{"Band"}, StartedAt: today, PlayedMs: 60_000},
+		{Uri: "b", Title: "B", Artists: []string{"Band", "Guest"}, StartedAt: today, PlayedMs: 10_000, Skipped: true},
+		{Uri: "old", Title: "Old", Artists: []string{"Band"}, StartedAt: today.AddDate(0, -1, 0), PlayedMs: 60_000},
+	}
+
+	stats := Summarize(plays, Daily.Buckets(now))
+
+	if stats.Total.Plays != 3 || stats.Total.Skips != 1 || stats.Total.PlayedMs != 130_000 {
+		t.Fatalf("invalid totals. got=%+v, expected=3 plays, 1 skip and 130s", stats.Total)
+	}
+
+	if len(stats.Tracks) != 2 || stats.Tracks[0].Name != "A - Band" || stats.Tracks[0].Plays != 2 {
+		t.Fatalf("invalid tracks. got=%+v, expected=A - Band played twice first", stats.Tracks)
+	}
+
+	if stats.Tracks[1].SkipRate() != 1 {
+		t.Fatalf("invalid skip rate. got=%f, expected=1", stats.Tracks[1].SkipRate())
+	}
+
+	if len(stats.Artists) != 2 || stats.Artists[0].Name != "Band" || stats.Artists[0].Plays != 3 ||
+		stats.Artists[1].Name != "Guest" {
+		t.Fatalf("invalid artists. got=%+v, expected=Band played 3 times, then Guest", stats.Artists)
+	}
+
+	if stats.Buckets[5].PlayedMs != 60_000 || stats.Buckets[6].PlayedMs != 70_000 {
+		t.Fatalf("invalid buckets. got=%+v, expected=60s yesterday and 70s today", stats.Buckets)
+	}
+}
@@ -0,0 +1,117 @@
+package history
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const openTimeout = 2 * time.Second
+
+var playsBucket = []byte("plays")
+
+var ErrStoreBusy = errors.New("history database is busy")
+
+type Store struct {
+	path string
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+func (s *Store) open(readOnly bool) (*bolt.DB, error) {
+	if readOnly {
+		if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+	} else if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: openTimeout, ReadOnly: readOnly})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, ErrStoreBusy
+	}
+
+	return db, err
+}
+
+func key(play Play, seq uint64) []byte {
+	raw := make([]byte, 16)
+	binary.BigEndian.PutUint64(raw, uint64(play.StartedAt.UnixNano()))
+	binary.BigEndian.PutUint64(raw[8:], seq)
+
+	return raw
+}
+
+func (s *Store) Record(plays ...Play) error {
+	db, err := s.open(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(playsBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, play := range plays {
+			seq, _ := bucket.NextSequence()
+			raw, _ := json.Marshal(play)
+
+			if err := bucket.Put(key(play, seq), raw); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Store) Plays(from, to time.Time) ([]Play, error) {
+	plays := []Play{}
+
+	db, err := s.open(true)
+	if err != nil || db == nil {
+		return plays, err
+	}
+	defer db.Close()
+
+	err = db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(playsBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		start := make([]byte, 8)
+		if !from.IsZero() {
+			binary.BigEndian.PutUint64(start, uint64(from.UnixNano()))
+		}
+
+		cursor := bucket.Cursor()
+		for k, v := cursor.Seek(start); k != nil; k, v = cursor.Next() {
+			var play Play
+			if err := json.Unmarshal(v, &play); err != nil {
+				continue
+			}
+
+			if !to.IsZero() && !play.StartedAt.Before(to) {
+				break
+			}
+
+			plays = append(plays, play)
+		}
+
+		return nil
+	})
+
+	return plays, err
+}
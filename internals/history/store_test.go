@@ -0,0 +1,92 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "data", "history.db"))
+
+	plays, err := store.Plays(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to read plays: %s", err)
+	}
+
+	if len(plays) != 0 {
+		t.Fatalf("invalid plays before recording. got=%v, expected=[]", plays)
+	}
+
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		at := start.Add(time.Duration(i) * time.Hour)
+		if err := store.Record(Play{Uri: string(rune('a' + i)), StartedAt: at, EndedAt: at.Add(time.Minute)}); err != nil {
+			t.Fatalf("failed to record play: %s", err)
+		}
+	}
+	store.Record(Play{Uri: "same", StartedAt: start}, Play{Uri: "time", StartedAt: start})
+
+	plays, _ = store.Plays(time.Time{}, time.Time{})
+	if len(plays) != 7 {
+		t.Fatalf("invalid plays. got=%d, expected=7", len(plays))
+	}
+
+	plays, _ = store.Plays(start.Add(time.Hour), start.Add(3*time.Hour))
+	if len(plays) != 2 || plays[0].Uri != "b" || plays[1].Uri != "c" {
+		t.Fatalf("invalid plays in range. got=%+v, expected=[b c]", plays)
+	}
+}
+
+func TestExport(t *testing.T) {
+	at := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	plays := []Play{{
+		Uri:        "spotify:track:a",
+		Title:      "Song, with comma",
+		Artists:    []string{"Band", "Guest"},
+		StartedAt:  at,
+		EndedAt:    at.Add(time.Minute),
+		DurationMs: 60_000,
+		PlayedMs:   58_000,
+	}}
+
+	var out bytes.Buffer
+	if err := Export(&out, FormatCsv, plays); err != nil {
+		t.Fatalf("failed to export csv: %s", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to read csv: %s", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("invalid csv records. got=%v, expected=header and one row", records)
+	}
+
+	if records[1][0] != "2024-05-01T20:00:00Z" || records[1][3] != "Song, with comma" ||
+		records[1][4] != "Band; Guest" || records[1][11] != "false" {
+		t.Fatalf("invalid csv row. got=%v, expected=the play at 2024-05-01T20:00:00Z", records[1])
+	}
+
+	out.Reset()
+	if err := Export(&out, FormatJson, plays); err != nil {
+		t.Fatalf("failed to export json: %s", err)
+	}
+
+	var decoded []Play
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("failed to decode json: %s", err)
+	}
+
+	if decoded[0].PlayedMs != 58_000 {
+		t.Fatalf("invalid played time. got=%d, expected=58000", decoded[0].PlayedMs)
+	}
+
+	if err := Export(&out, "xml", plays); err != ErrUnknownFormat {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrUnknownFormat)
+	}
+}
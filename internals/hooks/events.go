@@ -0,0 +1,69 @@
+package hooks
+
+import (
+	"slices"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const (
+	TrackChanged  = "track_changed"
+	Paused        = "paused"
+	Resumed       = "resumed"
+	DeviceChanged = "device_changed"
+	VolumeChanged = "volume_changed"
+)
+
+var Events = []string{TrackChanged, Paused, Resumed, DeviceChanged, VolumeChanged}
+
+type Event struct {
+	Type     string          `json:"event"`
+	Time     time.Time       `json:"time"`
+	Status   playback.Status `json:"status"`
+	Previous playback.Status `json:"previous"`
+}
+
+func playing(snapshot playback.Snapshot) bool {
+	return snapshot.Active && snapshot.State.IsPlaying
+}
+
+func Derive(update playback.Update, now time.Time) []Event {
+	previous, current := update.Previous, update.Current
+
+	types := []string{}
+
+	if current.Active && current.State.Item.Uri != "" &&
+		current.State.Item.Uri != previous.State.Item.Uri {
+		types = append(types, TrackChanged)
+	}
+
+	if playing(previous) && !playing(current) {
+		types = append(types, Paused)
+	} else if !playing(previous) && playing(current) {
+		types = append(types, Resumed)
+	}
+
+	if previous.State.Device.Id != current.State.Device.Id {
+		types = append(types, DeviceChanged)
+	} else if current.Active &&
+		previous.State.Device.VolumePercent != current.State.Device.VolumePercent {
+		types = append(types, VolumeChanged)
+	}
+
+	events := make([]Event, 0, len(types))
+	for _, kind := range types {
+		events = append(events, Event{
+			Type:     kind,
+			Time:     now,
+			Status:   current.Status(),
+			Previous: previous.Status(),
+		})
+	}
+
+	return events
+}
+
+func subscribed(events []string, event string) bool {
+	return len(events) == 0 || slices.Contains(events, event)
+}
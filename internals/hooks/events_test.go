@@ -0,0 +1,63 @@
+package hooks
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+func snapshot(uri, device string, volume int, playing bool) playback.Snapshot {
+	return playback.Snapshot{
+		Active: true,
+		State: api.PlaybackState{
+			IsPlaying: playing,
+			Item:      api.Track{Uri: uri, Name: uri},
+			Device:    api.Device{Id: device, Name: device, VolumePercent: volume},
+		},
+	}
+}
+
+func types(events []Event) []string {
+	result := []string{}
+	for _, event := range events {
+		result = append(result, event.Type)
+	}
+
+	return result
+}
+
+func TestDerive(t *testing.T) {
+	base := snapshot("a", "phone", 50, true)
+
+	cases := []struct {
+		name     string
+		previous playback.Snapshot
+		current  playback.Snapshot
+		expected []string
+	}{
+		{"progress only", base, base, []string{}},
+		{"track", base, snapshot("b", "phone", 50, true), []string{TrackChanged}},
+		{"pause", base, snapshot("a", "phone", 50, false), []string{Paused}},
+		{"resume", snapshot("a", "phone", 50, false), base, []string{Resumed}},
+		{"volume", base, snapshot("a", "phone", 70, true), []string{VolumeChanged}},
+		{"device", base, snapshot("a", "laptop", 70, true), []string{DeviceChanged}},
+		{"stopped", base, playback.Snapshot{}, []string{Paused, DeviceChanged}},
+		{"started", playback.Snapshot{}, base, []string{TrackChanged, Resumed, DeviceChanged}},
+	}
+
+	for _, c := range cases {
+		events := Derive(playback.Update{Previous: c.previous, Current: c.current}, time.Now())
+
+		if got := types(events); !slices.Equal(got, c.expected) {
+			t.Fatalf("%s: invalid events. got=%v, expected=%v", c.name, got, c.expected)
+		}
+	}
+
+	events := Derive(playback.Update{Previous: base, Current: snapshot("b", "phone", 50, true)}, time.Now())
+	if events[0].Status.Title != "b" || events[0].Previous.Title != "a" {
+		t.Fatalf("invalid statuses. got=%+v, expected=b after a", events[0])
+	}
+}
@@ -0,0 +1,78 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const outputLimit = 200
+
+func statusEnv(prefix string, status playback.Status) []string {
+	return []string{
+		prefix + "STATE=" + status.State,
+		prefix + "TITLE=" + status.Title,
+		prefix + "ARTIST=" + status.Artist,
+		prefix + "ALBUM=" + status.Album,
+		prefix + "URI=" + status.Uri,
+		prefix + "URL=" + status.Url,
+		prefix + "DEVICE=" + status.Device,
+		prefix + "VOLUME=" + strconv.Itoa(status.Volume),
+		prefix + "PROGRESS_MS=" + strconv.Itoa(status.ProgressMs),
+		prefix + "DURATION_MS=" + strconv.Itoa(status.DurationMs),
+	}
+}
+
+func Env(event Event) []string {
+	env := []string{
+		"SPOTIFY_EVENT=" + event.Type,
+		"SPOTIFY_TIME=" + event.Time.Format(time.RFC3339),
+	}
+	env = append(env, statusEnv("SPOTIFY_", event.Status)...)
+
+	return append(env, statusEnv("SPOTIFY_PREVIOUS_", event.Previous)...)
+}
+
+func (d *Dispatcher) run(ctx context.Context, hook Exec, event Event) error {
+	if !d.acquire(ctx) {
+		return nil
+	}
+	defer d.release()
+
+	ctx, cancel := context.WithTimeout(ctx, hook.Timeout)
+	defer cancel()
+
+	payload, _ := json.Marshal(event)
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	cmd.Env = append(os.Environ(), Env(event)...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", hook.Timeout)
+	}
+
+	detail := strings.TrimSpace(string(output))
+	if len(detail) > outputLimit {
+		detail = detail[:outputLimit] + "..."
+	}
+	if detail != "" {
+		err = fmt.Errorf("%s: %s", err, detail)
+	}
+
+	return fmt.Errorf("%w: %s %q: %s", ErrExecFailed, event.Type, hook.Command, err)
+}
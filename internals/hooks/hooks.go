@@ -0,0 +1,235 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const (
+	DefaultRetries       = 3
+	DefaultTimeout       = 10 * time.Second
+	DefaultMaxConcurrent = 4
+	DefaultBackoff       = time.Second
+)
+
+var (
+	ErrUnknownEvent  = errors.New("unknown event")
+	ErrInvalidUrl    = errors.New("webhook url must be http or https")
+	ErrEmptyCommand  = errors.New("exec hook command is empty")
+	ErrWebhookFailed = errors.New("webhook failed")
+	ErrExecFailed    = errors.New("exec hook failed")
+)
+
+type Webhook struct {
+	Url     string
+	Events  []string
+	Headers map[string]string
+	Retries int
+	Timeout time.Duration
+}
+
+type Exec struct {
+	Command string
+	Events  []string
+	Timeout time.Duration
+}
+
+type Config struct {
+	Webhooks      []Webhook
+	Exec          []Exec
+	MaxConcurrent int
+}
+
+func (c Config) Empty() bool {
+	return len(c.Webhooks) == 0 && len(c.Exec) == 0
+}
+
+type queue struct {
+	mu      sync.Mutex
+	pending []func()
+	running bool
+}
+
+type Dispatcher struct {
+	webhooks []Webhook
+	execs    []Exec
+	queues   []*queue
+	client   *http.Client
+	backoff  time.Duration
+	slots    chan struct{}
+	report   func(error)
+	wg       sync.WaitGroup
+}
+
+func validEvents(events []string) error {
+	for _, event := range events {
+		if !slices.Contains(Events, event) {
+			return fmt.Errorf("%w: %s", ErrUnknownEvent, event)
+		}
+	}
+
+	return nil
+}
+
+func New(config Config, report func(error)) (*Dispatcher, error) {
+	webhooks := slices.Clone(config.Webhooks)
+	for i, webhook := range webhooks {
+		if err := validEvents(webhook.Events); err != nil {
+			return nil, err
+		}
+
+		parsed, err := url.Parse(webhook.Url)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidUrl, webhook.Url)
+		}
+
+		if webhook.Retries == 0 {
+			webhooks[i].Retries = DefaultRetries
+		}
+		if webhook.Retries < 0 {
+			webhooks[i].Retries = 0
+		}
+		if webhook.Timeout <= 0 {
+			webhooks[i].Timeout = DefaultTimeout
+		}
+	}
+
+	execs := slices.Clone(config.Exec)
+	for i, exec := range execs {
+		if err := validEvents(exec.Events); err != nil {
+			return nil, err
+		}
+
+		if strings.TrimSpace(exec.Command) == "" {
+			return nil, ErrEmptyCommand
+		}
+
+		if exec.Timeout <= 0 {
+			execs[i].Timeout = DefaultTimeout
+		}
+	}
+
+	maxConcurrent := config.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultMaxConcurrent
+	}
+
+	if report == nil {
+		report = func(error) {}
+	}
+
+	queues := make([]*queue, len(webhooks)+len(execs))
+	for i := range queues {
+		queues[i] = &queue{}
+	}
+
+	return &Dispatcher{
+		webhooks: webhooks,
+		execs:    execs,
+		queues:   queues,
+		client:   &http.Client{},
+		backoff:  DefaultBackoff,
+		slots:    make(chan struct{}, maxConcurrent),
+		report:   report,
+	}, nil
+}
+
+func (d *Dispatcher) acquire(ctx context.Context) bool {
+	select {
+	case d.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (d *Dispatcher) release() {
+	<-d.slots
+}
+
+func (d *Dispatcher) enqueue(q *queue, deliver func() error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = append(q.pending, func() {
+		if err := deliver(); err != nil {
+			d.report(err)
+		}
+	})
+
+	if q.running {
+		return
+	}
+	q.running = true
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+
+		for {
+			q.mu.Lock()
+			if len(q.pending) == 0 {
+				q.running = false
+				q.mu.Unlock()
+
+				return
+			}
+
+			next := q.pending[0]
+			q.pending = q.pending[1:]
+			q.mu.Unlock()
+
+			next()
+		}
+	}()
+}
+
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event) {
+	for i, webhook := range d.webhooks {
+		if subscribed(webhook.Events, event.Type) {
+			d.enqueue(d.queues[i], func() error {
+				return d.post(ctx, webhook, event)
+			})
+		}
+	}
+
+	for i, exec := range d.execs {
+		if subscribed(exec.Events, event.Type) {
+			d.enqueue(d.queues[len(d.webhooks)+i], func() error {
+				return d.run(ctx, exec, event)
+			})
+		}
+	}
+}
+
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) Run(ctx context.Context, updates <-chan playback.Update) {
+	defer d.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			for _, event := range Derive(update, time.Now()) {
+				d.Dispatch(ctx, event)
+			}
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+type collector struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (c *collector) report(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errs = append(c.errs, err)
+}
+
+func (c *collector) all() []error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.errs
+}
+
+func event(kind string) Event {
+	return Event{
+		Type:     kind,
+		Time:     time.Now(),
+		Status:   snapshot("spotify:track:b", "phone", 40, true).Status(),
+		Previous: snapshot("spotify:track:a", "phone", 40, true).Status(),
+	}
+}
+
+func newDispatcher(t *testing.T, config Config, errs *collector) *Dispatcher {
+	t.Helper()
+
+	d, err := New(config, errs.report)
+	if err != nil {
+		t.Fatalf("failed to create dispatcher: %s", err)
+	}
+	d.backoff = time.Millisecond
+
+	return d
+}
+
+func TestNewValidates(t *testing.T) {
+	if _, err := New(Config{Webhooks: []Webhook{{Url: "ftp://host"}}}, nil); !errors.Is(err, ErrInvalidUrl) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidUrl)
+	}
+
+	if _, err := New(Config{Exec: []Exec{{Command: "true", Events: []string{"stopped"}}}}, nil); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrUnknownEvent)
+	}
+
+	if _, err := New(Config{Exec: []Exec{{Command: " "}}}, nil); !errors.Is(err, ErrEmptyCommand) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrEmptyCommand)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	var attempts atomic.Int32
+	var received Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	errs := &collector{}
+	d := newDispatcher(t, Config{Webhooks: []Webhook{{
+		Url:     server.URL,
+		Events:  []string{TrackChanged},
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	}}}, errs)
+
+	d.Dispatch(context.Background(), event(Paused))
+	d.Dispatch(context.Background(), event(TrackChanged))
+	d.Wait()
+
+	if len(errs.all()) != 0 {
+		t.Fatalf("invalid errors. got=%v, expected=[]", errs.all())
+	}
+
+	if attempts.Load() != 3 {
+		t.Fatalf("invalid attempts. got=%d, expected=3", attempts.Load())
+	}
+
+	if received.Type != TrackChanged || received.Status.Uri != "spotify:track:b" ||
+		received.Previous.Uri != "spotify:track:a" {
+		t.Fatalf("invalid payload. got=%+v, expected=track_changed from a to b", received)
+	}
+}
+
+func TestWebhookGivesUp(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		if r.URL.Path == "/bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	errs := &collector{}
+	d := newDispatcher(t, Config{Webhooks: []Webhook{
+		{Url: server.URL + "/bad"},
+		{Url: server.URL + "/down", Retries: 2},
+	}}, errs)
+
+	d.Dispatch(context.Background(), event(Paused))
+	d.Wait()
+
+	if attempts.Load() != 4 {
+		t.Fatalf("invalid attempts. got=%d, expected=4 (1 on 400 and 3 on 500)", attempts.Load())
+	}
+
+	if got := errs.all(); len(got) != 2 || !errors.Is(got[0], ErrWebhookFailed) {
+		t.Fatalf("invalid errors. got=%v, expected=2 webhook failures", got)
+	}
+}
+
+func TestWebhookWithoutRetries(t *testing.T) {
+	var attempts atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	errs := &collector{}
+	d := newDispatcher(t, Config{Webhooks: []Webhook{{Url: server.URL, Retries: -1}}}, errs)
+
+	d.Dispatch(context.Background(), event(Paused))
+	d.Wait()
+
+	if attempts.Load() != 1 {
+		t.Fatalf("invalid attempts. got=%d, expected=1", attempts.Load())
+	}
+}
+
+func TestWebhookOrder(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	var active, overlapped atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if active.Add(1) > 1 {
+			overlapped.Add(1)
+		}
+		defer active.Add(-1)
+
+		var payload Event
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		if payload.Type == Paused {
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		if r.URL.Path == "/first" {
+			mu.Lock()
+			received = append(received, payload.Type)
+			mu.Unlock()
+		}
+	}))
+	defer server.Close()
+
+	errs := &collector{}
+	d := newDispatcher(t, Config{
+		Webhooks:      []Webhook{{Url: server.URL + "/first"}, {Url: server.URL + "/second"}},
+		MaxConcurrent: 1,
+	}, errs)
+
+	d.Dispatch(context.Background(), event(Paused))
+	d.Dispatch(context.Background(), event(Resumed))
+	d.Dispatch(context.Background(), event(TrackChanged))
+	d.Wait()
+
+	expected := []string{Paused, Resumed, TrackChanged}
+	if strings.Join(received, ",") != strings.Join(expected, ",") {
+		t.Fatalf("invalid order. got=%v, expected=%v", received, expected)
+	}
+
+	if overlapped.Load() != 0 {
+		t.Fatalf("invalid concurrent requests. got=%d, expected=0", overlapped.Load())
+	}
+}
+
+func TestExecEnv(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+
+	errs := &collector{}
+	d := newDispatcher(t, Config{Exec: []Exec{{
+		Command: `echo "$SPOTIFY_EVENT $SPOTIFY_URI $SPOTIFY_PREVIOUS_URI $SPOTIFY_VOLUME" > "$OUT"; cat >> "$OUT"`,
+	}}}, errs)
+
+	t.Setenv("OUT", out)
+
+	d.Dispatch(context.Background(), event(TrackChanged))
+	d.Wait()
+
+	if len(errs.all()) != 0 {
+		t.Fatalf("invalid errors. got=%v, expected=[]", errs.all())
+	}
+
+	raw, _ := os.ReadFile(out)
+	lines := strings.SplitN(string(raw), "\n", 2)
+
+	if lines[0] != "track_changed spotify:track:b spotify:track:a 40" {
+		t.Fatalf("invalid env. got=%q, expected=%q", lines[0], "track_changed spotify:track:b spotify:track:a 40")
+	}
+
+	var payload Event
+	if err := json.Unmarshal([]byte(lines[1]), &payload); err != nil || payload.Type != TrackChanged {
+		t.Fatalf("invalid stdin. got=%q, expected=the track_changed payload", lines[1])
+	}
+}
+
+func TestExecTimeout(t *testing.T) {
+	errs := &collector{}
+	d := newDispatcher(t, Config{Exec: []Exec{{Command: "sleep 5", Timeout: 50 * time.Millisecond}}}, errs)
+
+	started := time.Now()
+	d.Dispatch(context.Background(), event(Paused))
+	d.Wait()
+
+	if time.Since(started) > 2*time.Second {
+		t.Fatal("hook wasn't killed on timeout")
+	}
+
+	if got := errs.all(); len(got) != 1 || !errors.Is(got[0], ErrExecFailed) ||
+		!strings.Contains(got[0].Error(), "timed out") {
+		t.Fatalf("invalid errors. got=%v, expected=one timeout", got)
+	}
+}
+
+func TestExecConcurrencyCap(t *testing.T) {
+	lock := filepath.Join(t.TempDir(), "lock")
+	t.Setenv("LOCK", lock)
+
+	command := `mkdir "$LOCK" || exit 1; sleep 0.05; rmdir "$LOCK"`
+
+	errs := &collector{}
+	d := newDispatcher(t, Config{
+		Exec:          []Exec{{Command: command}, {Command: command}, {Command: command}},
+		MaxConcurrent: 1,
+	}, errs)
+
+	d.Dispatch(context.Background(), event(Resumed))
+	d.Wait()
+
+	if len(errs.all()) != 0 {
+		t.Fatalf("invalid errors. got=%v, expected=no concurrent runs", errs.all())
+	}
+}
+
+func TestRun(t *testing.T) {
+	received := make(chan string, 4)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload Event
+		json.NewDecoder(r.Body).Decode(&payload)
+		received <- payload.Type
+	}))
+	defer server.Close()
+
+	d := newDispatcher(t, Config{Webhooks: []Webhook{{Url: server.URL}}}, &collector{})
+
+	updates := make(chan playback.Update, 1)
+	updates <- playback.Update{
+		Previous: snapshot("a", "phone", 50, true),
+		Current:  snapshot("a", "phone", 50, false),
+	}
+	close(updates)
+
+	d.Run(context.Background(), updates)
+
+	select {
+	case kind := <-received:
+		if kind != Paused {
+			t.Fatalf("invalid event. got=%s, expected=%s", kind, Paused)
+		}
+	default:
+		t.Fatal("no event dispatched")
+	}
+}
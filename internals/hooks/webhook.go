@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, webhook Webhook, payload []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, webhook.Timeout)
+	defer cancel()
+
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(payload))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "spotify-tui")
+	for name, value := range webhook.Headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+
+	return retryable(response.StatusCode), fmt.Errorf("status %s", response.Status)
+}
+
+func (d *Dispatcher) post(ctx context.Context, webhook Webhook, event Event) error {
+	payload, _ := json.Marshal(event)
+
+	backoff := d.backoff
+
+	for attempt := 0; ; attempt++ {
+		if !d.acquire(ctx) {
+			return fmt.Errorf("%w: %s %s: %s", ErrWebhookFailed, event.Type, webhook.Url, ctx.Err())
+		}
+
+		retry, err := d.attempt(ctx, webhook, payload)
+		d.release()
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempt == webhook.Retries || ctx.Err() != nil {
+			return fmt.Errorf("%w: %s %s: %s", ErrWebhookFailed, event.Type, webhook.Url, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %s %s: %s", ErrWebhookFailed, event.Type, webhook.Url, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
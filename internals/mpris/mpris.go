@@ -0,0 +1,416 @@
+package mpris
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+	"github.com/godbus/dbus/v5/prop"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const (
+	BusName = "org.mpris.MediaPlayer2.spotify-tui"
+
+	objectPath  = dbus.ObjectPath("/org/mpris/MediaPlayer2")
+	rootIface   = "org.mpris.MediaPlayer2"
+	playerIface = "org.mpris.MediaPlayer2.Player"
+
+	trackPrefix = "/org/mpris/MediaPlayer2/spotify_tui/track/"
+	noTrack     = dbus.ObjectPath("/org/mpris/MediaPlayer2/TrackList/NoTrack")
+
+	seekTolerance = 2 * time.Second
+)
+
+var ErrNameTaken = errors.New("mpris bus name already taken")
+
+var methodNames = map[string]string{"SeekBy": "Seek"}
+
+var invalidPathChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+type Service struct {
+	conn   *dbus.Conn
+	player api.Player
+	props  *prop.Properties
+
+	mu        sync.Mutex
+	snapshot  playback.Snapshot
+	updatedAt time.Time
+}
+
+func Export(conn *dbus.Conn, player api.Player) (*Service, error) {
+	s := &Service{conn: conn, player: player}
+
+	if err := conn.Export(root{}, objectPath, rootIface); err != nil {
+		return nil, err
+	}
+
+	if err := conn.ExportWithMap(methods{s}, methodNames, objectPath, playerIface); err != nil {
+		return nil, err
+	}
+
+	props, err := prop.Export(conn, objectPath, s.properties())
+	if err != nil {
+		return nil, err
+	}
+	s.props = props
+
+	node := &introspect.Node{
+		Name: string(objectPath),
+		Interfaces: []introspect.Interface{
+			introspect.IntrospectData,
+			prop.IntrospectData,
+			{
+				Name:       rootIface,
+				Methods:    introspect.Methods(root{}),
+				Properties: props.Introspection(rootIface),
+			},
+			{
+				Name:       playerIface,
+				Methods:    playerMethods(),
+				Properties: props.Introspection(playerIface),
+				Signals: []introspect.Signal{{
+					Name: "Seeked",
+					Args: []introspect.Arg{{Name: "Position", Type: "x"}},
+				}},
+			},
+		},
+	}
+
+	if err := conn.Export(introspect.NewIntrospectable(node), objectPath,
+		"org.freedesktop.DBus.Introspectable"); err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.RequestName(BusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return nil, ErrNameTaken
+	}
+
+	return s, nil
+}
+
+func (s *Service) Close() error {
+	_, err := s.conn.ReleaseName(BusName)
+
+	return err
+}
+
+func (s *Service) properties() prop.Map {
+	return prop.Map{
+		rootIface: {
+			"CanQuit":             {Value: false, Emit: prop.EmitConst},
+			"CanRaise":            {Value: false, Emit: prop.EmitConst},
+			"HasTrackList":        {Value: false, Emit: prop.EmitConst},
+			"Identity":            {Value: "spotify-tui", Emit: prop.EmitConst},
+			"SupportedUriSchemes": {Value: []string{}, Emit: prop.EmitConst},
+			"SupportedMimeTypes":  {Value: []string{}, Emit: prop.EmitConst},
+		},
+		playerIface: {
+			"PlaybackStatus": {Value: "Stopped", Emit: prop.EmitTrue},
+			"LoopStatus":     {Value: "None", Writable: true, Emit: prop.EmitTrue, Callback: s.setLoopStatus},
+			"Rate":           {Value: 1.0, Writable: true, Emit: prop.EmitTrue, Callback: s.setRate},
+			"Shuffle":        {Value: false, Writable: true, Emit: prop.EmitTrue, Callback: s.setShuffle},
+			"Metadata":       {Value: metadata(playback.Snapshot{}), Emit: prop.EmitTrue},
+			"Volume":         {Value: 0.0, Writable: true, Emit: prop.EmitTrue, Callback: s.setVolume},
+			"Position":       {Value: int64(0), Emit: prop.EmitFalse},
+			"MinimumRate":    {Value: 1.0, Emit: prop.EmitConst},
+			"MaximumRate":    {Value: 1.0, Emit: prop.EmitConst},
+			"CanGoNext":      {Value: false, Emit: prop.EmitTrue},
+			"CanGoPrevious":  {Value: false, Emit: prop.EmitTrue},
+			"CanPlay":        {Value: false, Emit: prop.EmitTrue},
+			"CanPause":       {Value: false, Emit: prop.EmitTrue},
+			"CanSeek":        {Value: false, Emit: prop.EmitTrue},
+			"CanControl":     {Value: true, Emit: prop.EmitConst},
+		},
+	}
+}
+
+func trackId(track api.Track) dbus.ObjectPath {
+	if track.Id == "" {
+		return noTrack
+	}
+
+	return dbus.ObjectPath(trackPrefix + invalidPathChars.ReplaceAllString(track.Id, "_"))
+}
+
+func micros(ms int) int64 {
+	return int64(ms) * 1000
+}
+
+func metadata(snapshot playback.Snapshot) map[string]dbus.Variant {
+	if !snapshot.Active || snapshot.State.Item.Name == "" {
+		return map[string]dbus.Variant{
+			"mpris:trackid": dbus.MakeVariant(noTrack),
+		}
+	}
+
+	track := snapshot.State.Item
+
+	artists := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		artists = append(artists, artist.Name)
+	}
+
+	albumArtists := make([]string, 0, len(track.Album.Artists))
+	for _, artist := range track.Album.Artists {
+		albumArtists = append(albumArtists, artist.Name)
+	}
+
+	meta := map[string]dbus.Variant{
+		"mpris:trackid":     dbus.MakeVariant(trackId(track)),
+		"mpris:length":      dbus.MakeVariant(micros(track.DurationMs)),
+		"xesam:title":       dbus.MakeVariant(track.Name),
+		"xesam:artist":      dbus.MakeVariant(artists),
+		"xesam:album":       dbus.MakeVariant(track.Album.Name),
+		"xesam:albumArtist": dbus.MakeVariant(albumArtists),
+	}
+
+	if track.ExternalUrls.Spotify != "" {
+		meta["xesam:url"] = dbus.MakeVariant(track.ExternalUrls.Spotify)
+	}
+
+	if len(track.Album.Images) > 0 {
+		meta["mpris:artUrl"] = dbus.MakeVariant(track.Album.Images[0].Url)
+	}
+
+	return meta
+}
+
+func playbackStatus(snapshot playback.Snapshot) string {
+	switch {
+	case !snapshot.Active:
+		return "Stopped"
+	case snapshot.State.IsPlaying:
+		return "Playing"
+	}
+
+	return "Paused"
+}
+
+func loopStatus(repeat string) string {
+	switch repeat {
+	case "track":
+		return "Track"
+	case "context":
+		return "Playlist"
+	}
+
+	return "None"
+}
+
+func (s *Service) set(iface, name string, value any) {
+	if reflect.DeepEqual(s.props.GetMust(iface, name), value) {
+		return
+	}
+
+	s.props.SetMust(iface, name, value)
+}
+
+func (s *Service) position() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.positionLocked(time.Now())
+}
+
+func (s *Service) positionLocked(now time.Time) time.Duration {
+	position := time.Duration(s.snapshot.State.ProgressMs) * time.Millisecond
+	if s.snapshot.State.IsPlaying {
+		position += now.Sub(s.updatedAt)
+	}
+
+	return position
+}
+
+func (s *Service) Update(snapshot playback.Snapshot) {
+	now := time.Now()
+
+	s.mu.Lock()
+	expected := s.positionLocked(now)
+	sameTrack := s.snapshot.Active && snapshot.Active &&
+		s.snapshot.State.Item.Id == snapshot.State.Item.Id
+	s.snapshot, s.updatedAt = snapshot, now
+	s.mu.Unlock()
+
+	state := snapshot.State
+	active := snapshot.Active
+	progress := time.Duration(state.ProgressMs) * time.Millisecond
+
+	volume := 0.0
+	if active {
+		volume = float64(state.Device.VolumePercent) / 100
+	}
+
+	s.set(playerIface, "PlaybackStatus", playbackStatus(snapshot))
+	s.set(playerIface, "LoopStatus", loopStatus(state.RepeatState))
+	s.set(playerIface, "Shuffle", state.ShuffleState)
+	s.set(playerIface, "Metadata", metadata(snapshot))
+	s.set(playerIface, "Volume", volume)
+	s.set(playerIface, "Position", micros(state.ProgressMs))
+	s.set(playerIface, "CanGoNext", active)
+	s.set(playerIface, "CanGoPrevious", active)
+	s.set(playerIface, "CanPlay", active)
+	s.set(playerIface, "CanPause", active)
+	s.set(playerIface, "CanSeek", active && state.Item.DurationMs > 0)
+
+	if sameTrack && (progress-expected).Abs() > seekTolerance {
+		s.seeked(progress)
+	}
+}
+
+func (s *Service) seeked(position time.Duration) {
+	s.conn.Emit(objectPath, playerIface+".Seeked", position.Microseconds())
+}
+
+func failed(err error) *dbus.Error {
+	if err == nil {
+		return nil
+	}
+
+	return dbus.MakeFailedError(err)
+}
+
+func (s *Service) setLoopStatus(change *prop.Change) *dbus.Error {
+	switch change.Value.(string) {
+	case "None":
+		return failed(s.player.DisableRepeat())
+	case "Track":
+		return failed(s.player.SetRepeatTrack())
+	case "Playlist":
+		return failed(s.player.SetRepeatContext())
+	}
+
+	return prop.ErrInvalidArg
+}
+
+func (s *Service) setRate(change *prop.Change) *dbus.Error {
+	if change.Value.(float64) != 1.0 {
+		return prop.ErrInvalidArg
+	}
+
+	return nil
+}
+
+func (s *Service) setShuffle(change *prop.Change) *dbus.Error {
+	if change.Value.(bool) {
+		return failed(s.player.EnableShuffle())
+	}
+
+	return failed(s.player.DisableShuffle())
+}
+
+func (s *Service) setVolume(change *prop.Change) *dbus.Error {
+	volume := min(max(change.Value.(float64), 0), 1)
+
+	return failed(s.player.SetVolume(int(volume*100 + 0.5)))
+}
+
+func playerMethods() []introspect.Method {
+	exported := introspect.Methods(methods{})
+	for i, method := range exported {
+		if name, ok := methodNames[method.Name]; ok {
+			exported[i].Name = name
+		}
+	}
+
+	return exported
+}
+
+type root struct{}
+
+func (root) Raise() *dbus.Error {
+	return nil
+}
+
+func (root) Quit() *dbus.Error {
+	return nil
+}
+
+type methods struct {
+	s *Service
+}
+
+func (m methods) Next() *dbus.Error {
+	return failed(m.s.player.SkipToNext())
+}
+
+func (m methods) Previous() *dbus.Error {
+	return failed(m.s.player.SkipToPrevious())
+}
+
+func (m methods) Pause() *dbus.Error {
+	return failed(m.s.player.Pause())
+}
+
+func (m methods) Play() *dbus.Error {
+	return failed(m.s.player.Resume())
+}
+
+func (m methods) Stop() *dbus.Error {
+	return failed(m.s.player.Pause())
+}
+
+func (m methods) PlayPause() *dbus.Error {
+	m.s.mu.Lock()
+	playing := m.s.snapshot.State.IsPlaying
+	m.s.mu.Unlock()
+
+	if playing {
+		return m.Pause()
+	}
+
+	return m.Play()
+}
+
+func (m methods) seekTo(position time.Duration) *dbus.Error {
+	if err := m.s.player.Seek(int(position.Milliseconds())); err != nil {
+		return failed(err)
+	}
+
+	m.s.seeked(position)
+
+	return nil
+}
+
+func (m methods) SeekBy(offset int64) *dbus.Error {
+	m.s.mu.Lock()
+	duration := time.Duration(m.s.snapshot.State.Item.DurationMs) * time.Millisecond
+	m.s.mu.Unlock()
+
+	position := max(m.s.position()+time.Duration(offset)*time.Microsecond, 0)
+	if position > duration {
+		return m.Next()
+	}
+
+	return m.seekTo(position)
+}
+
+func (m methods) SetPosition(track dbus.ObjectPath, position int64) *dbus.Error {
+	m.s.mu.Lock()
+	current := m.s.snapshot.State.Item
+	m.s.mu.Unlock()
+
+	target := time.Duration(position) * time.Microsecond
+	duration := time.Duration(current.DurationMs) * time.Millisecond
+
+	if track != trackId(current) || target < 0 || target > duration {
+		return nil
+	}
+
+	return m.seekTo(target)
+}
+
+func (m methods) OpenUri(uri string) *dbus.Error {
+	return dbus.MakeFailedError(errors.New("opening uris isn't supported"))
+}
@@ -0,0 +1,238 @@
+package mpris
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/apitest"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+func startBus(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("dbus-daemon"); err != nil {
+		t.Skip("dbus-daemon isn't available")
+	}
+
+	cmd := exec.Command("dbus-daemon", "--session", "--nofork", "--print-address=1")
+	cmd.Stderr = os.Stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("failed to pipe dbus-daemon output: %s", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start dbus-daemon: %s", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read bus address: %s", err)
+	}
+
+	return strings.TrimSpace(address)
+}
+
+func connect(t *testing.T, address string) *dbus.Conn {
+	t.Helper()
+
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("failed to connect to bus: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func snapshot(playing bool) playback.Snapshot {
+	var state api.PlaybackState
+	state.IsPlaying = playing
+	state.ProgressMs = 5_000
+	state.RepeatState = "context"
+	state.Device.VolumePercent = 30
+	state.Item = api.Track{
+		Id:         "track1",
+		Name:       "Song",
+		DurationMs: 180_000,
+		Artists:    []api.Artist{{Name: "Band"}},
+		Album:      api.Album{Name: "Record"},
+	}
+
+	return playback.Snapshot{State: state, Active: true}
+}
+
+func setup(t *testing.T) (*Service, *apitest.FakePlayer, dbus.BusObject) {
+	address := startBus(t)
+
+	player := &apitest.FakePlayer{}
+
+	service, err := Export(connect(t, address), player)
+	if err != nil {
+		t.Fatalf("failed to export service: %s", err)
+	}
+
+	client := connect(t, address)
+
+	return service, player, client.Object(BusName, objectPath)
+}
+
+func TestPlayerMethods(t *testing.T) {
+	service, player, object := setup(t)
+
+	service.Update(snapshot(false))
+
+	calls := []struct {
+		method string
+		args   []any
+	}{
+		{playerIface + ".PlayPause", nil},
+		{playerIface + ".Next", nil},
+		{playerIface + ".Previous", nil},
+		{playerIface + ".Seek", []any{int64(10_000_000)}},
+		{playerIface + ".SetPosition", []any{dbus.ObjectPath(trackPrefix + "track1"), int64(60_000_000)}},
+		{playerIface + ".SetPosition", []any{dbus.ObjectPath(trackPrefix + "other"), int64(60_000_000)}},
+	}
+
+	for _, call := range calls {
+		if err := object.Call(call.method, 0, call.args...).Err; err != nil {
+			t.Fatalf("failed to call %s: %s", call.method, err)
+		}
+	}
+
+	properties := []struct {
+		name  string
+		value any
+	}{
+		{"Volume", 0.5},
+		{"LoopStatus", "Track"},
+		{"Shuffle", true},
+	}
+
+	for _, property := range properties {
+		if err := object.SetProperty(playerIface+"."+property.name, dbus.MakeVariant(property.value)); err != nil {
+			t.Fatalf("failed to set %s: %s", property.name, err)
+		}
+	}
+
+	if err := object.SetProperty(playerIface+".LoopStatus", dbus.MakeVariant("Sometimes")); err == nil {
+		t.Fatal("invalid loop status was accepted")
+	}
+
+	expected := []string{
+		"Resume", "SkipToNext", "SkipToPrevious", "Seek 15000", "Seek 60000",
+		"SetVolume 50", "SetRepeatTrack", "EnableShuffle",
+	}
+
+	if calls := player.Calls(); !slices.Equal(calls, expected) {
+		t.Fatalf("invalid calls. got=%v, expected=%v", calls, expected)
+	}
+}
+
+func TestUpdateProperties(t *testing.T) {
+	address := startBus(t)
+
+	service, err := Export(connect(t, address), &apitest.FakePlayer{})
+	if err != nil {
+		t.Fatalf("failed to export service: %s", err)
+	}
+
+	client := connect(t, address)
+
+	if err := client.AddMatchSignal(
+		dbus.WithMatchObjectPath(objectPath),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+	); err != nil {
+		t.Fatalf("failed to add match: %s", err)
+	}
+
+	signals := make(chan *dbus.Signal, 16)
+	client.Signal(signals)
+
+	service.Update(snapshot(true))
+
+	select {
+	case signal := <-signals:
+		if signal.Body[0] != playerIface {
+			t.Fatalf("invalid interface. got=%v, expected=%s", signal.Body[0], playerIface)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("PropertiesChanged not received")
+	}
+
+	object := client.Object(BusName, objectPath)
+
+	status, err := object.GetProperty(playerIface + ".PlaybackStatus")
+	if err != nil {
+		t.Fatalf("failed to get playback status: %s", err)
+	}
+
+	if status.Value() != "Playing" {
+		t.Fatalf("invalid playback status. got=%v, expected=Playing", status.Value())
+	}
+
+	loop, err := object.GetProperty(playerIface + ".LoopStatus")
+	if err != nil {
+		t.Fatalf("failed to get loop status: %s", err)
+	}
+
+	if loop.Value() != "Playlist" {
+		t.Fatalf("invalid loop status. got=%v, expected=Playlist", loop.Value())
+	}
+
+	variant, err := object.GetProperty(playerIface + ".Metadata")
+	if err != nil {
+		t.Fatalf("failed to get metadata: %s", err)
+	}
+
+	metadata := variant.Value().(map[string]dbus.Variant)
+
+	if title := metadata["xesam:title"].Value(); title != "Song" {
+		t.Fatalf("invalid title. got=%v, expected=Song", title)
+	}
+
+	if artists := metadata["xesam:artist"].Value().([]string); !slices.Equal(artists, []string{"Band"}) {
+		t.Fatalf("invalid artists. got=%v, expected=[Band]", artists)
+	}
+
+	if length := metadata["mpris:length"].Value(); length != int64(180_000_000) {
+		t.Fatalf("invalid length. got=%v, expected=180000000", length)
+	}
+
+	service.Update(playback.Snapshot{})
+
+	status, err = object.GetProperty(playerIface + ".PlaybackStatus")
+	if err != nil {
+		t.Fatalf("failed to get playback status: %s", err)
+	}
+
+	if status.Value() != "Stopped" {
+		t.Fatalf("invalid playback status. got=%v, expected=Stopped", status.Value())
+	}
+}
+
+func TestNameTaken(t *testing.T) {
+	address := startBus(t)
+
+	if _, err := Export(connect(t, address), &apitest.FakePlayer{}); err != nil {
+		t.Fatalf("failed to export service: %s", err)
+	}
+
+	if _, err := Export(connect(t, address), &apitest.FakePlayer{}); err != ErrNameTaken {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrNameTaken)
+	}
+}
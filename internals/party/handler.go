@@ -0,0 +1,197 @@
+package party
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/uri"
+)
+
+const searchLimit = 10
+
+type Catalog interface {
+	SearchTracks(query string, limit int) ([]api.Track, error)
+	GetTrack(id string) (api.Track, error)
+}
+
+type SearchResult struct {
+	Uri      string `json:"uri"`
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	Album    string `json:"album"`
+	Explicit bool   `json:"explicit"`
+}
+
+type submitRequest struct {
+	Uri  string `json:"uri"`
+	Name string `json:"name"`
+}
+
+type voteRequest struct {
+	Vote int `json:"vote"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, ErrExplicit):
+		status = http.StatusForbidden
+	case errors.Is(err, ErrUnknownRequest):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrRequestClosed):
+		status = http.StatusConflict
+	case errors.Is(err, ErrInvalidVote), errors.Is(err, uri.ErrInvalidUri):
+		status = http.StatusBadRequest
+	}
+
+	writeJson(w, status, errorResponse{err.Error()})
+}
+
+func guestId(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func decode(w http.ResponseWriter, r *http.Request, body any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, 4096)
+
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeJson(w, http.StatusBadRequest, errorResponse{"invalid body"})
+		return false
+	}
+
+	return true
+}
+
+func withCode(code string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.PathValue("code")), []byte(code)) != 1 {
+			http.NotFound(w, r)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func Handler(p *Party, catalog Catalog, code string) http.Handler {
+	mux := http.NewServeMux()
+
+	handle := func(pattern string, handler http.HandlerFunc) {
+		mux.HandleFunc(pattern, withCode(code, handler))
+	}
+
+	handle("GET /{code}/{$}", func(w http.ResponseWriter, r *http.Request) {
+		renderPage(w)
+	})
+
+	handle("GET /{code}/api/search", func(w http.ResponseWriter, r *http.Request) {
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		if query == "" {
+			writeJson(w, http.StatusOK, []SearchResult{})
+			return
+		}
+
+		tracks, err := catalog.SearchTracks(query, searchLimit)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		results := []SearchResult{}
+		for _, track := range tracks {
+			if !p.Allowed(track) {
+				continue
+			}
+
+			artists := make([]string, 0, len(track.Artists))
+			for _, artist := range track.Artists {
+				artists = append(artists, artist.Name)
+			}
+
+			results = append(results, SearchResult{
+				Uri:      track.Uri,
+				Title:    track.Name,
+				Artist:   strings.Join(artists, ", "),
+				Album:    track.Album.Name,
+				Explicit: track.Explicit,
+			})
+		}
+
+		writeJson(w, http.StatusOK, results)
+	})
+
+	handle("GET /{code}/api/requests", func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, p.Requests(guestId(r)))
+	})
+
+	handle("POST /{code}/api/requests", func(w http.ResponseWriter, r *http.Request) {
+		guest := guestId(r)
+
+		var body submitRequest
+		if !decode(w, r, &body) {
+			return
+		}
+
+		trackUri, err := uri.TrackUri(body.Uri)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		track, err := catalog.GetTrack(strings.TrimPrefix(trackUri, "spotify:track:"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		entry, err := p.Submit(guest, body.Name, track)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJson(w, http.StatusCreated, entry)
+	})
+
+	handle("POST /{code}/api/requests/{id}/vote", func(w http.ResponseWriter, r *http.Request) {
+		guest := guestId(r)
+
+		var body voteRequest
+		if !decode(w, r, &body) {
+			return
+		}
+
+		entry, err := p.Vote(guest, r.PathValue("id"), body.Vote)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJson(w, http.StatusOK, entry)
+	})
+
+	return mux
+}
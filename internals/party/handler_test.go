@@ -0,0 +1,146 @@
+package party
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const testCode = "code"
+
+type fakeCatalog struct {
+	tracks []api.Track
+}
+
+func (f fakeCatalog) SearchTracks(query string, limit int) ([]api.Track, error) {
+	return f.tracks, nil
+}
+
+func (f fakeCatalog) GetTrack(id string) (api.Track, error) {
+	for _, track := range f.tracks {
+		if track.Id == id {
+			return track, nil
+		}
+	}
+
+	return api.Track{}, api.ErrResponse{Message: "not found", Status: http.StatusNotFound}
+}
+
+const (
+	cleanId    = "4uLU6hMCjMI75M1A2tKUQC"
+	explicitId = "6rqhFgbbKwnb9MLmUQDhG6"
+)
+
+func do(t *testing.T, handler http.Handler, guest, method, path, body string, result any) int {
+	t.Helper()
+
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.RemoteAddr = guest + ":52310"
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if result != nil {
+		if err := json.NewDecoder(recorder.Body).Decode(result); err != nil {
+			t.Fatalf("failed to decode response: %s", err)
+		}
+	}
+
+	return recorder.Code
+}
+
+func TestGuestFlow(t *testing.T) {
+	catalog := fakeCatalog{[]api.Track{track(cleanId, false), track(explicitId, true)}}
+
+	p := New(Settings{MaxRequests: 1})
+	handler := Handler(p, catalog, testCode)
+
+	base := "/" + testCode
+	alice, bob := "192.168.1.20", "192.168.1.21"
+
+	if status := do(t, handler, alice, http.MethodGet, "/wrong/", "", nil); status != http.StatusNotFound {
+		t.Fatalf("invalid status for a wrong code. got=%d, expected=%d", status, http.StatusNotFound)
+	}
+
+	if status := do(t, handler, alice, http.MethodGet, base+"/", "", nil); status != http.StatusOK {
+		t.Fatalf("invalid status for the page. got=%d, expected=%d", status, http.StatusOK)
+	}
+
+	var results []SearchResult
+	do(t, handler, alice, http.MethodGet, base+"/api/search?q=song", "", &results)
+	if len(results) != 1 || results[0].Uri != "spotify:track:"+cleanId {
+		t.Fatalf("invalid search results. got=%+v, expected only %s", results, cleanId)
+	}
+
+	if status := do(t, handler, alice, http.MethodPost, base+"/api/requests",
+		`{"uri": "spotify:track:`+explicitId+`"}`, nil); status != http.StatusForbidden {
+		t.Fatalf("invalid status for an explicit track. got=%d, expected=%d", status, http.StatusForbidden)
+	}
+
+	var entry Entry
+	if status := do(t, handler, alice, http.MethodPost, base+"/api/requests",
+		`{"uri": "spotify:track:`+cleanId+`", "name": "Alice"}`, &entry); status != http.StatusCreated {
+		t.Fatalf("invalid status for a request. got=%d, expected=%d", status, http.StatusCreated)
+	}
+
+	if status := do(t, handler, alice, http.MethodPost, base+"/api/requests",
+		`{"uri": "spotify:track:`+cleanId+`"}`, nil); status != http.StatusCreated {
+		t.Fatalf("invalid status for a duplicate request. got=%d, expected=%d", status, http.StatusCreated)
+	}
+
+	if status := do(t, handler, bob, http.MethodPost, base+"/api/requests/"+entry.Id+"/vote",
+		`{"vote": -1}`, &entry); status != http.StatusOK {
+		t.Fatalf("invalid status for a vote. got=%d, expected=%d", status, http.StatusOK)
+	}
+
+	if entry.Score != 0 || entry.Vote != -1 {
+		t.Fatalf("invalid entry after vote. got=%+v, expected score=0 and vote=-1", entry)
+	}
+
+	var requests []Entry
+	do(t, handler, alice, http.MethodGet, base+"/api/requests", "", &requests)
+	if len(requests) != 1 || requests[0].Vote != 1 {
+		t.Fatalf("invalid requests. got=%+v, expected alice's own vote", requests)
+	}
+
+	p.Approve(entry.Id)
+	p.PopApproved()
+
+	if status := do(t, handler, alice, http.MethodPost, base+"/api/requests",
+		`{"uri": "spotify:track:`+cleanId+`"}`, nil); status != http.StatusTooManyRequests {
+		t.Fatalf("invalid status over the limit. got=%d, expected=%d", status, http.StatusTooManyRequests)
+	}
+}
+
+func TestGuestsWithoutCookies(t *testing.T) {
+	catalog := fakeCatalog{[]api.Track{track(cleanId, false), track(explicitId, false)}}
+
+	p := New(Settings{MaxRequests: 1})
+	handler := Handler(p, catalog, testCode)
+
+	base := "/" + testCode
+	guest := "192.168.1.30"
+
+	var entry Entry
+	if status := do(t, handler, guest, http.MethodPost, base+"/api/requests",
+		`{"uri": "spotify:track:`+cleanId+`"}`, &entry); status != http.StatusCreated {
+		t.Fatalf("invalid status for a request. got=%d, expected=%d", status, http.StatusCreated)
+	}
+
+	if status := do(t, handler, guest, http.MethodPost, base+"/api/requests",
+		`{"uri": "spotify:track:`+explicitId+`"}`, nil); status != http.StatusTooManyRequests {
+		t.Fatalf("invalid status over the limit. got=%d, expected=%d", status, http.StatusTooManyRequests)
+	}
+
+	for range 3 {
+		do(t, handler, "192.168.1.31", http.MethodPost, base+"/api/requests/"+entry.Id+"/vote", `{"vote": 1}`, &entry)
+	}
+
+	if entry.Score != 2 {
+		t.Fatalf("invalid score after repeated votes. got=%d, expected=2", entry.Score)
+	}
+}
@@ -0,0 +1,125 @@
+package party
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var guestPage = template.Must(template.New("party").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="referrer" content="no-referrer">
+<title>Spotify TUI - Party</title>
+<style>
+* { box-sizing: border-box; }
+body { font-family: sans-serif; background: #282828; color: #ebdbb2; margin: 0; padding: 1.5em; display: flex; justify-content: center; }
+main { width: 100%; max-width: 32em; }
+h1 { color: #b8bb26; font-size: 1.4em; }
+h2 { color: #928374; font-size: 1em; margin-top: 1.5em; }
+#error { color: #fb4934; min-height: 1.2em; }
+form { display: flex; gap: .5em; margin-bottom: .5em; }
+input { flex: 1; background: #3c3836; color: #ebdbb2; border: 0; border-radius: .5em; padding: .7em; }
+button { background: #3c3836; color: #ebdbb2; border: 0; border-radius: .5em; padding: .5em .8em; }
+button.on { background: #fe8019; color: #282828; }
+ul { list-style: none; padding: 0; }
+li { display: flex; align-items: center; gap: .5em; padding: .5em 0; border-bottom: 1px solid #3c3836; }
+li .track { flex: 1; min-width: 0; }
+li .track span { display: block; color: #928374; font-size: .9em; }
+.score { min-width: 2em; text-align: center; }
+.approved { color: #b8bb26; }
+</style>
+</head>
+<body>
+<main>
+<h1>Party requests</h1>
+<form id="name-form"><input id="name" type="text" maxlength="32" placeholder="Your name"></form>
+<form id="search-form"><input id="query" type="search" placeholder="Search for a track"><button>Search</button></form>
+<ul id="results"></ul>
+<p id="error"></p>
+<h2>Requested</h2>
+<ul id="requests"></ul>
+</main>
+<script>
+const $ = (id) => document.getElementById(id);
+$("name").value = localStorage.getItem("name") || "";
+$("name").onchange = () => localStorage.setItem("name", $("name").value);
+$("name-form").onsubmit = (event) => event.preventDefault();
+async function call(method, path, body) {
+  const response = await fetch(path, {
+    method,
+    headers: { "Content-Type": "application/json" },
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  const data = await response.json();
+  if (!response.ok) throw new Error(data.error);
+  return data;
+}
+function report(promise) {
+  promise.then(() => { $("error").textContent = ""; refresh(); })
+    .catch((err) => { $("error").textContent = err.message; });
+}
+function track(item) {
+  const div = document.createElement("div");
+  div.className = "track";
+  const artist = document.createElement("span");
+  artist.textContent = item.artist + (item.explicit ? " · explicit" : "");
+  div.append(item.title, artist);
+  return div;
+}
+function button(label, onclick, on) {
+  const b = document.createElement("button");
+  b.textContent = label;
+  b.className = on ? "on" : "";
+  b.onclick = onclick;
+  return b;
+}
+$("search-form").onsubmit = async (event) => {
+  event.preventDefault();
+  try {
+    const results = await call("GET", "api/search?q=" + encodeURIComponent($("query").value));
+    $("results").replaceChildren(...results.map((item) => {
+      const li = document.createElement("li");
+      li.append(track(item), button("Request", () => {
+        report(call("POST", "api/requests", { uri: item.uri, name: $("name").value }).then(() => $("results").replaceChildren()));
+      }));
+      return li;
+    }));
+    $("error").textContent = results.length ? "" : "No tracks found";
+  } catch (err) {
+    $("error").textContent = err.message;
+  }
+};
+async function refresh() {
+  try {
+    const requests = await call("GET", "api/requests");
+    $("requests").replaceChildren(...requests.map((item) => {
+      const li = document.createElement("li");
+      const score = document.createElement("span");
+      score.className = "score";
+      score.textContent = item.score;
+      const info = track(item);
+      info.querySelector("span").textContent += " · by " + item.guest;
+      if (item.status === "approved") info.classList.add("approved");
+      const vote = (value) => () => report(call("POST", "api/requests/" + item.id + "/vote", { vote: item.vote === value ? 0 : value }));
+      li.append(button("▲", vote(1), item.vote === 1), score, button("▼", vote(-1), item.vote === -1), info);
+      return li;
+    }));
+  } catch (err) {
+    $("error").textContent = err.message;
+  }
+}
+refresh();
+setInterval(refresh, 5000);
+</script>
+</body>
+</html>
+`))
+
+func renderPage(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+
+	guestPage.Execute(w, nil)
+}
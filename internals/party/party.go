@@ -0,0 +1,336 @@
+package party
+
+import (
+	"cmp"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const (
+	DefaultAddress     = "0.0.0.0:6781"
+	DefaultMaxRequests = 3
+	DefaultWindow      = 10 * time.Minute
+)
+
+var (
+	ErrExplicit       = errors.New("explicit tracks aren't allowed")
+	ErrRateLimited    = errors.New("too many requests, try again later")
+	ErrUnknownRequest = errors.New("unknown request")
+	ErrRequestClosed  = errors.New("request was already moderated")
+	ErrInvalidVote    = errors.New("vote must be -1, 0 or 1")
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+	StatusQueued   Status = "queued"
+)
+
+type Settings struct {
+	MaxRequests   int
+	Window        time.Duration
+	AllowExplicit bool
+}
+
+type Entry struct {
+	Id       string `json:"id"`
+	Uri      string `json:"uri"`
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	Explicit bool   `json:"explicit"`
+	Guest    string `json:"guest"`
+	Score    int    `json:"score"`
+	Status   Status `json:"status"`
+	Vote     int    `json:"vote"`
+}
+
+type request struct {
+	entry     Entry
+	guest     string
+	votes     map[string]int
+	createdAt time.Time
+}
+
+func (r *request) score() int {
+	score := 0
+	for _, vote := range r.votes {
+		score += vote
+	}
+
+	return score
+}
+
+func (r *request) view(guest string) Entry {
+	entry := r.entry
+	entry.Score = r.score()
+	entry.Vote = r.votes[guest]
+
+	return entry
+}
+
+type Party struct {
+	settings Settings
+	now      func() time.Time
+	changes  chan struct{}
+	approved chan struct{}
+
+	mu          sync.Mutex
+	queueErr    error
+	requests    map[string]*request
+	submissions map[string][]time.Time
+}
+
+func New(settings Settings) *Party {
+	if settings.MaxRequests <= 0 {
+		settings.MaxRequests = DefaultMaxRequests
+	}
+
+	if settings.Window <= 0 {
+		settings.Window = DefaultWindow
+	}
+
+	return &Party{
+		settings:    settings,
+		now:         time.Now,
+		changes:     make(chan struct{}, 1),
+		approved:    make(chan struct{}, 1),
+		requests:    map[string]*request{},
+		submissions: map[string][]time.Time{},
+	}
+}
+
+func newId() string {
+	raw := make([]byte, 8)
+	rand.Read(raw)
+
+	return hex.EncodeToString(raw)
+}
+
+func NewCode() string {
+	return newId()[:8]
+}
+
+func (p *Party) changed() {
+	select {
+	case p.changes <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Party) Changes() <-chan struct{} {
+	return p.changes
+}
+
+func (p *Party) Settings() Settings {
+	return p.settings
+}
+
+func (p *Party) Allowed(track api.Track) bool {
+	return p.settings.AllowExplicit || !track.Explicit
+}
+
+func (p *Party) rateLimited(guest string, now time.Time) bool {
+	recent := slices.DeleteFunc(p.submissions[guest], func(at time.Time) bool {
+		return now.Sub(at) >= p.settings.Window
+	})
+	p.submissions[guest] = recent
+
+	return len(recent) >= p.settings.MaxRequests
+}
+
+func (p *Party) open(uri string) *request {
+	for _, r := range p.requests {
+		if r.entry.Uri == uri && (r.entry.Status == StatusPending || r.entry.Status == StatusApproved) {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func (p *Party) Submit(guest, name string, track api.Track) (Entry, error) {
+	if !p.Allowed(track) {
+		return Entry{}, ErrExplicit
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if existing := p.open(track.Uri); existing != nil {
+		existing.votes[guest] = 1
+		p.changed()
+
+		return existing.view(guest), nil
+	}
+
+	now := p.now()
+	if p.rateLimited(guest, now) {
+		return Entry{}, ErrRateLimited
+	}
+	p.submissions[guest] = append(p.submissions[guest], now)
+
+	artists := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		artists = append(artists, artist.Name)
+	}
+
+	if name = strings.TrimSpace(name); name == "" {
+		name = "guest"
+	}
+
+	r := &request{
+		entry: Entry{
+			Id:       newId(),
+			Uri:      track.Uri,
+			Title:    track.Name,
+			Artist:   strings.Join(artists, ", "),
+			Explicit: track.Explicit,
+			Guest:    name,
+			Status:   StatusPending,
+		},
+		guest:     guest,
+		votes:     map[string]int{guest: 1},
+		createdAt: now,
+	}
+	p.requests[r.entry.Id] = r
+	p.changed()
+
+	return r.view(guest), nil
+}
+
+func (p *Party) Vote(guest, id string, vote int) (Entry, error) {
+	if vote < -1 || vote > 1 {
+		return Entry{}, ErrInvalidVote
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	r, ok := p.requests[id]
+	if !ok {
+		return Entry{}, ErrUnknownRequest
+	}
+
+	if r.entry.Status != StatusPending && r.entry.Status != StatusApproved {
+		return Entry{}, ErrRequestClosed
+	}
+
+	if vote == 0 {
+		delete(r.votes, guest)
+	} else {
+		r.votes[guest] = vote
+	}
+	p.changed()
+
+	return r.view(guest), nil
+}
+
+func (p *Party) moderate(id string, status Status) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	r, ok := p.requests[id]
+	if !ok {
+		return ErrUnknownRequest
+	}
+
+	if r.entry.Status != StatusPending {
+		return ErrRequestClosed
+	}
+
+	r.entry.Status = status
+	p.changed()
+
+	return nil
+}
+
+func (p *Party) Approve(id string) error {
+	if err := p.moderate(id, StatusApproved); err != nil {
+		return err
+	}
+
+	select {
+	case p.approved <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+func (p *Party) Reject(id string) error {
+	return p.moderate(id, StatusRejected)
+}
+
+func (p *Party) sorted(guest string, statuses ...Status) []Entry {
+	matching := []*request{}
+	for _, r := range p.requests {
+		if slices.Contains(statuses, r.entry.Status) {
+			matching = append(matching, r)
+		}
+	}
+
+	slices.SortFunc(matching, func(a, b *request) int {
+		if c := cmp.Compare(b.score(), a.score()); c != 0 {
+			return c
+		}
+
+		return a.createdAt.Compare(b.createdAt)
+	})
+
+	entries := make([]Entry, 0, len(matching))
+	for _, r := range matching {
+		entries = append(entries, r.view(guest))
+	}
+
+	return entries
+}
+
+func (p *Party) Requests(guest string) []Entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.sorted(guest, StatusPending, StatusApproved)
+}
+
+func (p *Party) Moderation() []Entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append(p.sorted("", StatusApproved), p.sorted("", StatusPending)...)
+}
+
+func (p *Party) PopApproved() (Entry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	approved := p.sorted("", StatusApproved)
+	if len(approved) == 0 {
+		return Entry{}, false
+	}
+
+	next := approved[0]
+	p.requests[next.Id].entry.Status = StatusQueued
+	p.changed()
+
+	return next, true
+}
+
+func (p *Party) Restore(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if r, ok := p.requests[id]; ok && r.entry.Status == StatusQueued {
+		r.entry.Status = StatusApproved
+		p.changed()
+	}
+}
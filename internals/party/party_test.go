@@ -0,0 +1,172 @@
+package party
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+func track(id string, explicit bool) api.Track {
+	return api.Track{
+		Id:       id,
+		Name:     "Track " + id,
+		Uri:      "spotify:track:" + id,
+		Explicit: explicit,
+		Artists:  []api.Artist{{Name: "Band"}},
+	}
+}
+
+func titles(entries []Entry) []string {
+	result := []string{}
+	for _, entry := range entries {
+		result = append(result, entry.Title)
+	}
+
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestSubmitAndVote(t *testing.T) {
+	p := New(Settings{})
+
+	first, err := p.Submit("alice", "Alice", track("a", false))
+	if err != nil {
+		t.Fatalf("failed to submit request: %s", err)
+	}
+
+	if first.Score != 1 || first.Vote != 1 || first.Guest != "Alice" || first.Status != StatusPending {
+		t.Fatalf("invalid entry. got=%+v, expected=pending by Alice with one vote", first)
+	}
+
+	second, _ := p.Submit("bob", "", track("b", false))
+	if second.Guest != "guest" {
+		t.Fatalf("invalid guest name. got=%s, expected=guest", second.Guest)
+	}
+
+	if _, err := p.Vote("carol", second.Id, 1); err != nil {
+		t.Fatalf("failed to vote: %s", err)
+	}
+
+	if got := titles(p.Requests("")); !equal(got, []string{"Track b", "Track a"}) {
+		t.Fatalf("invalid order. got=%v, expected=[Track b Track a]", got)
+	}
+
+	if _, err := p.Vote("carol", second.Id, -1); err != nil {
+		t.Fatalf("failed to vote: %s", err)
+	}
+
+	duplicate, err := p.Submit("dave", "Dave", track("a", false))
+	if err != nil {
+		t.Fatalf("failed to submit duplicate: %s", err)
+	}
+
+	if duplicate.Id != first.Id || duplicate.Score != 2 || duplicate.Guest != "Alice" {
+		t.Fatalf("invalid duplicate. got=%+v, expected=the first request upvoted", duplicate)
+	}
+
+	if got := titles(p.Requests("")); !equal(got, []string{"Track a", "Track b"}) {
+		t.Fatalf("invalid order. got=%v, expected=[Track a Track b]", got)
+	}
+
+	if _, err := p.Vote("carol", second.Id, 2); !errors.Is(err, ErrInvalidVote) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidVote)
+	}
+
+	if _, err := p.Vote("carol", "missing", 1); !errors.Is(err, ErrUnknownRequest) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrUnknownRequest)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Now()
+
+	p := New(Settings{MaxRequests: 2, Window: time.Minute})
+	p.now = func() time.Time { return now }
+
+	for _, id := range []string{"a", "b"} {
+		if _, err := p.Submit("alice", "", track(id, false)); err != nil {
+			t.Fatalf("failed to submit request: %s", err)
+		}
+	}
+
+	if _, err := p.Submit("alice", "", track("c", false)); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrRateLimited)
+	}
+
+	if _, err := p.Submit("bob", "", track("c", false)); err != nil {
+		t.Fatalf("failed to submit as another guest: %s", err)
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, err := p.Submit("alice", "", track("d", false)); err != nil {
+		t.Fatalf("failed to submit after the window: %s", err)
+	}
+}
+
+func TestExplicitFilter(t *testing.T) {
+	p := New(Settings{})
+
+	if _, err := p.Submit("alice", "", track("a", true)); !errors.Is(err, ErrExplicit) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrExplicit)
+	}
+
+	p = New(Settings{AllowExplicit: true})
+
+	if _, err := p.Submit("alice", "", track("a", true)); err != nil {
+		t.Fatalf("failed to submit explicit track: %s", err)
+	}
+}
+
+func TestModeration(t *testing.T) {
+	p := New(Settings{})
+
+	a, _ := p.Submit("alice", "", track("a", false))
+	b, _ := p.Submit("bob", "", track("b", false))
+	c, _ := p.Submit("carol", "", track("c", false))
+	p.Vote("dave", c.Id, 1)
+
+	if err := p.Approve(a.Id); err != nil {
+		t.Fatalf("failed to approve request: %s", err)
+	}
+	if err := p.Approve(c.Id); err != nil {
+		t.Fatalf("failed to approve request: %s", err)
+	}
+	if err := p.Reject(b.Id); err != nil {
+		t.Fatalf("failed to reject request: %s", err)
+	}
+
+	if err := p.Approve(b.Id); !errors.Is(err, ErrRequestClosed) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrRequestClosed)
+	}
+
+	if _, err := p.Vote("dave", b.Id, 1); !errors.Is(err, ErrRequestClosed) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrRequestClosed)
+	}
+
+	next, ok := p.PopApproved()
+	if !ok || next.Id != c.Id {
+		t.Fatalf("invalid next request. got=%+v, expected=%s", next, c.Id)
+	}
+
+	p.Restore(next.Id)
+
+	if got := titles(p.Moderation()); !equal(got, []string{"Track c", "Track a"}) {
+		t.Fatalf("invalid moderation list. got=%v, expected=[Track c Track a]", got)
+	}
+}
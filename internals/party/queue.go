@@ -0,0 +1,61 @@
+package party
+
+import (
+	"context"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const DefaultQueueInterval = 5 * time.Second
+
+func (p *Party) setQueueErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.queueErr != err {
+		p.queueErr = err
+		p.changed()
+	}
+}
+
+func (p *Party) QueueErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.queueErr
+}
+
+func (p *Party) Run(ctx context.Context, player api.Player, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var current string
+	queued := false
+
+	for {
+		if state, err := player.GetPlaybackState(); err == nil && state.Item.Uri != current {
+			current = state.Item.Uri
+			queued = false
+		}
+
+		if !queued {
+			if next, ok := p.PopApproved(); ok {
+				if err := player.AddToQueue(next.Uri); err != nil {
+					p.Restore(next.Id)
+					p.setQueueErr(err)
+				} else {
+					p.setQueueErr(nil)
+					queued = true
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-p.approved:
+		}
+	}
+}
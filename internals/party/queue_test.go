@@ -0,0 +1,74 @@
+package party
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/apitest"
+)
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func queued(player *apitest.FakePlayer) []string {
+	return slices.DeleteFunc(player.Calls(), func(call string) bool {
+		return call == "GetPlaybackState"
+	})
+}
+
+func TestRunQueuesInVoteOrder(t *testing.T) {
+	p := New(Settings{})
+
+	a, _ := p.Submit("alice", "", track("a", false))
+	b, _ := p.Submit("bob", "", track("b", false))
+	p.Vote("carol", b.Id, 1)
+
+	p.Approve(a.Id)
+	p.Approve(b.Id)
+
+	player := &apitest.FakePlayer{}
+	player.State.Item = api.Track{Uri: "spotify:track:playing"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx, player, 10*time.Millisecond)
+		close(done)
+	}()
+
+	waitFor(t, func() bool { return len(queued(player)) == 1 })
+
+	time.Sleep(50 * time.Millisecond)
+	if got := queued(player); !slices.Equal(got, []string{"AddToQueue spotify:track:b"}) {
+		t.Fatalf("invalid queued tracks. got=%v, expected=[AddToQueue spotify:track:b]", got)
+	}
+
+	cancel()
+	<-done
+
+	player.State.Item = api.Track{Uri: "spotify:track:b"}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx, player, 10*time.Millisecond)
+
+	waitFor(t, func() bool { return len(queued(player)) == 2 })
+
+	if got := queued(player); got[1] != "AddToQueue spotify:track:a" {
+		t.Fatalf("invalid queued tracks. got=%v, expected=spotify:track:a after the change", got)
+	}
+}
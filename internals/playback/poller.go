@@ -0,0 +1,175 @@
+package playback
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const updatesBuffer = 16
+
+type Snapshot struct {
+	State  api.PlaybackState
+	Active bool
+}
+
+func (s Snapshot) Status() Status {
+	if !s.Active {
+		return Stopped()
+	}
+
+	return FromState(s.State)
+}
+
+type Update struct {
+	Previous Snapshot
+	Current  Snapshot
+}
+
+type Poller struct {
+	fetch    func() (api.PlaybackState, error)
+	interval time.Duration
+	kick     chan struct{}
+
+	mu      sync.Mutex
+	current Snapshot
+	err     error
+	fetched bool
+	subs    map[int]chan Update
+	nextSub int
+}
+
+func NewPoller(fetch func() (api.PlaybackState, error), interval time.Duration) *Poller {
+	return &Poller{
+		fetch:    fetch,
+		interval: interval,
+		kick:     make(chan struct{}, 1),
+		subs:     map[int]chan Update{},
+	}
+}
+
+func (p *Poller) poll() {
+	state, err := p.fetch()
+
+	snapshot := Snapshot{State: state, Active: true}
+	if errors.Is(err, api.ErrNoActiveDevice) {
+		snapshot, err = Snapshot{}, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+	if err != nil {
+		return
+	}
+
+	previous, fetched := p.current, p.fetched
+	p.current, p.fetched = snapshot, true
+
+	if fetched && reflect.DeepEqual(previous, snapshot) {
+		return
+	}
+
+	update := Update{Previous: previous, Current: snapshot}
+	for _, sub := range p.subs {
+		deliver(sub, update)
+	}
+}
+
+func deliver(sub chan Update, update Update) {
+	select {
+	case sub <- update:
+		return
+	default:
+	}
+
+	oldest, drained := true, false
+	for !drained {
+		select {
+		case queued := <-sub:
+			if oldest {
+				update.Previous, oldest = queued.Previous, false
+			}
+		default:
+			drained = true
+		}
+	}
+
+	sub <- update
+}
+
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.poll()
+
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			for id, sub := range p.subs {
+				close(sub)
+				delete(p.subs, id)
+			}
+			p.mu.Unlock()
+
+			return
+		case <-ticker.C:
+		case <-p.kick:
+			ticker.Reset(p.interval)
+		}
+	}
+}
+
+func (p *Poller) Refresh() {
+	select {
+	case p.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Poller) Current() (Snapshot, error) {
+	p.mu.Lock()
+	fetched := p.fetched
+	p.mu.Unlock()
+
+	if !fetched {
+		p.poll()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.fetched {
+		return Snapshot{}, p.err
+	}
+
+	return p.current, nil
+}
+
+func (p *Poller) Subscribe() (<-chan Update, func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := p.nextSub
+	p.nextSub++
+
+	sub := make(chan Update, updatesBuffer)
+	p.subs[id] = sub
+
+	return sub, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if _, ok := p.subs[id]; ok {
+			close(sub)
+			delete(p.subs, id)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package playback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+type fakePlayer struct {
+	mu    sync.Mutex
+	state api.PlaybackState
+	err   error
+}
+
+func (f *fakePlayer) set(state api.PlaybackState, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.state, f.err = state, err
+}
+
+func (f *fakePlayer) fetch() (api.PlaybackState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.state, f.err
+}
+
+func receive(t *testing.T, updates <-chan Update) Update {
+	t.Helper()
+
+	select {
+	case update := <-updates:
+		return update
+	case <-time.After(time.Second):
+		t.Fatal("update not received")
+	}
+
+	return Update{}
+}
+
+func TestPollerUpdates(t *testing.T) {
+	player := &fakePlayer{}
+	player.set(api.PlaybackState{IsPlaying: true, Item: api.Track{Name: "First"}}, nil)
+
+	poller := NewPoller(player.fetch, time.Hour)
+	updates, unsubscribe := poller.Subscribe()
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go poller.Run(ctx)
+
+	update := receive(t, updates)
+	if update.Previous.Active || !update.Current.Active || update.Current.State.Item.Name != "First" {
+		t.Fatalf("invalid first update. got=%+v, expected=First playing", update)
+	}
+
+	player.set(api.PlaybackState{}, api.ErrNoActiveDevice)
+	poller.Refresh()
+
+	update = receive(t, updates)
+	if update.Previous.State.Item.Name != "First" || update.Current.Active {
+		t.Fatalf("invalid stop update. got=%+v, expected=inactive after First", update)
+	}
+
+	if status := update.Current.Status(); status.State != StateStopped {
+		t.Fatalf("invalid status. got=%s, expected=%s", status.State, StateStopped)
+	}
+}
+
+func TestPollerKeepsStateOnError(t *testing.T) {
+	player := &fakePlayer{}
+	player.set(api.PlaybackState{Item: api.Track{Name: "Song"}}, nil)
+
+	poller := NewPoller(player.fetch, time.Hour)
+
+	if _, err := poller.Current(); err != nil {
+		t.Fatalf("failed to get current state: %s", err)
+	}
+
+	updates, unsubscribe := poller.Subscribe()
+	defer unsubscribe()
+
+	player.set(api.PlaybackState{}, errors.New("boom"))
+	poller.poll()
+
+	select {
+	case update := <-updates:
+		t.Fatalf("invalid update. got=%+v, expected=none", update)
+	default:
+	}
+
+	snapshot, err := poller.Current()
+	if err != nil {
+		t.Fatalf("failed to get current state: %s", err)
+	}
+
+	if snapshot.State.Item.Name != "Song" {
+		t.Fatalf("invalid cached state. got=%+v, expected=Song", snapshot)
+	}
+}
+
+func TestPollerFirstFetchError(t *testing.T) {
+	player := &fakePlayer{}
+	player.set(api.PlaybackState{}, api.ErrUnauthorized)
+
+	poller := NewPoller(player.fetch, time.Hour)
+
+	if _, err := poller.Current(); !errors.Is(err, api.ErrUnauthorized) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, api.ErrUnauthorized)
+	}
+}
+
+func TestPollerCoalescesFullSubscriber(t *testing.T) {
+	player := &fakePlayer{}
+	player.set(api.PlaybackState{Item: api.Track{Name: "Song 0"}}, nil)
+
+	poller := NewPoller(player.fetch, time.Hour)
+	poller.poll()
+
+	updates, unsubscribe := poller.Subscribe()
+	defer unsubscribe()
+
+	for i := 1; i <= updatesBuffer+4; i++ {
+		player.set(api.PlaybackState{Item: api.Track{Name: fmt.Sprintf("Song %d", i)}}, nil)
+		poller.poll()
+	}
+
+	previous := "Song 0"
+	for len(updates) > 0 {
+		update := <-updates
+		if update.Previous.State.Item.Name != previous {
+			t.Fatalf("invalid previous state. got=%s, expected=%s", update.Previous.State.Item.Name, previous)
+		}
+
+		previous = update.Current.State.Item.Name
+	}
+
+	expected := fmt.Sprintf("Song %d", updatesBuffer+4)
+	if previous != expected {
+		t.Fatalf("invalid current state. got=%s, expected=%s", previous, expected)
+	}
+}
@@ -0,0 +1,19 @@
+package playback
+
+import "time"
+
+const restartMargin = 5 * time.Second
+
+func Listened(lastProgress int, lastAt time.Time, progress int, now time.Time) time.Duration {
+	elapsed := now.Sub(lastAt)
+	advanced := time.Duration(progress-lastProgress) * time.Millisecond
+
+	return max(min(elapsed, advanced), 0)
+}
+
+func Restarted(lastProgress int, lastAt time.Time, progress, durationMs int, now time.Time) bool {
+	margin := int(restartMargin.Milliseconds())
+	reached := lastProgress + int(now.Sub(lastAt).Milliseconds())
+
+	return lastProgress-progress >= margin && reached >= durationMs-margin
+}
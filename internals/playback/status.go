@@ -0,0 +1,88 @@
+package playback
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const (
+	StatePlaying = "playing"
+	StatePaused  = "paused"
+	StateStopped = "stopped"
+)
+
+type Status struct {
+	State      string   `json:"state"`
+	Playing    bool     `json:"playing"`
+	Title      string   `json:"title"`
+	Artist     string   `json:"artist"`
+	Artists    []string `json:"artists"`
+	Album      string   `json:"album"`
+	Uri        string   `json:"uri"`
+	Url        string   `json:"url"`
+	Explicit   bool     `json:"explicit"`
+	Position   string   `json:"position"`
+	Duration   string   `json:"duration"`
+	ProgressMs int      `json:"progress_ms"`
+	DurationMs int      `json:"duration_ms"`
+	Context    string   `json:"context"`
+	Device     string   `json:"device"`
+	DeviceType string   `json:"device_type"`
+	Volume     int      `json:"volume"`
+	Shuffle    bool     `json:"shuffle"`
+	Repeat     string   `json:"repeat"`
+}
+
+func formatMs(ms int) string {
+	d := time.Duration(ms) * time.Millisecond
+
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func Stopped() Status {
+	return Status{
+		State:    StateStopped,
+		Artists:  []string{},
+		Position: formatMs(0),
+		Duration: formatMs(0),
+		Repeat:   "off",
+	}
+}
+
+func FromState(state api.PlaybackState) Status {
+	artists := make([]string, 0, len(state.Item.Artists))
+	for _, artist := range state.Item.Artists {
+		artists = append(artists, artist.Name)
+	}
+
+	status := Status{
+		State:      StatePaused,
+		Playing:    state.IsPlaying,
+		Title:      state.Item.Name,
+		Artist:     strings.Join(artists, ", "),
+		Artists:    artists,
+		Album:      state.Item.Album.Name,
+		Uri:        state.Item.Uri,
+		Url:        state.Item.ExternalUrls.Spotify,
+		Explicit:   state.Item.Explicit,
+		Position:   formatMs(state.ProgressMs),
+		Duration:   formatMs(state.Item.DurationMs),
+		ProgressMs: state.ProgressMs,
+		DurationMs: state.Item.DurationMs,
+		Context:    state.Context.Uri,
+		Device:     state.Device.Name,
+		DeviceType: state.Device.Type,
+		Volume:     state.Device.VolumePercent,
+		Shuffle:    state.ShuffleState,
+		Repeat:     state.RepeatState,
+	}
+
+	if state.IsPlaying {
+		status.State = StatePlaying
+	}
+
+	return status
+}
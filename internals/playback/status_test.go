@@ -0,0 +1,66 @@
+package playback
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+func TestFromState(t *testing.T) {
+	var state api.PlaybackState
+	state.IsPlaying = true
+	state.ProgressMs = 65_000
+	state.RepeatState = "track"
+	state.Device = api.Device{Name: "Desk", Type: "Computer", VolumePercent: 70}
+	state.Item = api.Track{
+		Name:       "Song",
+		DurationMs: 3_725_000,
+		Artists:    []api.Artist{{Name: "A"}, {Name: "B"}},
+		Album:      api.Album{Name: "Record"},
+	}
+
+	status := FromState(state)
+
+	expected := Status{
+		State:      StatePlaying,
+		Playing:    true,
+		Title:      "Song",
+		Artist:     "A, B",
+		Artists:    []string{"A", "B"},
+		Album:      "Record",
+		Position:   "1:05",
+		Duration:   "62:05",
+		ProgressMs: 65_000,
+		DurationMs: 3_725_000,
+		Device:     "Desk",
+		DeviceType: "Computer",
+		Volume:     70,
+		Repeat:     "track",
+	}
+
+	if !reflect.DeepEqual(status, expected) {
+		t.Fatalf("invalid status. got=%+v, expected=%+v", status, expected)
+	}
+}
+
+func TestStoppedJson(t *testing.T) {
+	encoded, err := json.Marshal(Stopped())
+	if err != nil {
+		t.Fatalf("failed to encode: %s", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+
+	if decoded["state"] != StateStopped {
+		t.Fatalf("invalid state. got=%v, expected=%s", decoded["state"], StateStopped)
+	}
+
+	if artists, ok := decoded["artists"].([]any); !ok || len(artists) != 0 {
+		t.Fatalf("invalid artists. got=%v, expected=[]", decoded["artists"])
+	}
+}
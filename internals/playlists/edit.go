@@ -0,0 +1,201 @@
+package playlists
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+var (
+	ErrInvalidMove = errors.New("invalid move")
+	ErrUnknownEdit = errors.New("unknown edit")
+)
+
+type Editor interface {
+	ChangePlaylistDetails(playlistId string, details api.PlaylistDetails) error
+	InsertPlaylistTracks(playlistId string, uris []string, position int) (string, error)
+	RemovePlaylistTracks(playlistId, snapshotId string, uris []string) (string, error)
+	ReorderPlaylistTracks(playlistId string, reorder api.Reorder) (string, error)
+}
+
+type Move struct {
+	RangeStart   int
+	RangeLength  int
+	InsertBefore int
+}
+
+func MoveRow(from, to int) Move {
+	if to > from {
+		return Move{RangeStart: from, RangeLength: 1, InsertBefore: to + 1}
+	}
+
+	return Move{RangeStart: from, RangeLength: 1, InsertBefore: to}
+}
+
+func (m Move) Validate(size int) error {
+	switch {
+	case m.RangeLength < 1,
+		m.RangeStart < 0,
+		m.RangeStart+m.RangeLength > size,
+		m.InsertBefore < 0,
+		m.InsertBefore > size,
+		m.InsertBefore >= m.RangeStart && m.InsertBefore <= m.RangeStart+m.RangeLength:
+		return fmt.Errorf("%w: %+v on %d tracks", ErrInvalidMove, m, size)
+	}
+
+	return nil
+}
+
+func (m Move) Inverse() Move {
+	if m.InsertBefore > m.RangeStart {
+		return Move{
+			RangeStart:   m.InsertBefore - m.RangeLength,
+			RangeLength:  m.RangeLength,
+			InsertBefore: m.RangeStart,
+		}
+	}
+
+	return Move{
+		RangeStart:   m.InsertBefore,
+		RangeLength:  m.RangeLength,
+		InsertBefore: m.RangeStart + m.RangeLength,
+	}
+}
+
+func Reorder[T any](items []T, m Move) []T {
+	moved := slices.Clone(items[m.RangeStart : m.RangeStart+m.RangeLength])
+	rest := slices.Delete(slices.Clone(items), m.RangeStart, m.RangeStart+m.RangeLength)
+
+	at := m.InsertBefore
+	if at > m.RangeStart {
+		at -= m.RangeLength
+	}
+
+	return slices.Insert(rest, at, moved...)
+}
+
+func Positions(uris []string, uri string) []int {
+	positions := []int{}
+	for i, candidate := range uris {
+		if candidate == uri {
+			positions = append(positions, i)
+		}
+	}
+
+	return positions
+}
+
+type Kind int
+
+const (
+	Moved Kind = iota
+	Added
+	Removed
+	Described
+)
+
+type Edit struct {
+	Kind      Kind
+	Move      Move
+	Uri       string
+	Positions []int
+	Kept      []int
+	Details   api.PlaylistDetails
+	Previous  api.PlaylistDetails
+}
+
+func NewAdd(items []api.PlaylistItem, uri string, position int) Edit {
+	return Edit{
+		Kind:      Added,
+		Uri:       uri,
+		Positions: []int{position},
+		Kept:      Positions(itemUris(items), uri),
+	}
+}
+
+func NewRemove(items []api.PlaylistItem, position int) Edit {
+	uri := items[position].Track.Uri
+
+	kept := []int{}
+	for _, other := range Positions(itemUris(items), uri) {
+		switch {
+		case other < position:
+			kept = append(kept, other)
+		case other > position:
+			kept = append(kept, other-1)
+		}
+	}
+
+	return Edit{
+		Kind:      Removed,
+		Uri:       uri,
+		Positions: []int{position},
+		Kept:      kept,
+	}
+}
+
+func (e Edit) Undo() Edit {
+	switch e.Kind {
+	case Moved:
+		e.Move = e.Move.Inverse()
+	case Added:
+		e.Kind = Removed
+	case Removed:
+		e.Kind = Added
+	case Described:
+		e.Details, e.Previous = e.Previous, e.Details
+	}
+
+	return e
+}
+
+func (e Edit) Describe() string {
+	switch e.Kind {
+	case Moved:
+		return "move"
+	case Added:
+		return "add"
+	case Removed:
+		return "removal"
+	}
+
+	return "details change"
+}
+
+func insertAt(editor Editor, playlistId, snapshotId, uri string, positions []int) (string, error) {
+	for _, position := range slices.Sorted(slices.Values(positions)) {
+		snapshot, err := editor.InsertPlaylistTracks(playlistId, []string{uri}, position)
+		if err != nil {
+			return "", err
+		}
+		snapshotId = snapshot
+	}
+
+	return snapshotId, nil
+}
+
+func Apply(editor Editor, playlistId, snapshotId string, e Edit) (string, error) {
+	switch e.Kind {
+	case Moved:
+		return editor.ReorderPlaylistTracks(playlistId, api.Reorder{
+			RangeStart:   e.Move.RangeStart,
+			RangeLength:  e.Move.RangeLength,
+			InsertBefore: e.Move.InsertBefore,
+			SnapshotId:   snapshotId,
+		})
+	case Added:
+		return insertAt(editor, playlistId, snapshotId, e.Uri, e.Positions)
+	case Removed:
+		snapshotId, err := editor.RemovePlaylistTracks(playlistId, snapshotId, []string{e.Uri})
+		if err != nil {
+			return "", err
+		}
+		return insertAt(editor, playlistId, snapshotId, e.Uri, e.Kept)
+	case Described:
+		return snapshotId, editor.ChangePlaylistDetails(playlistId, e.Details)
+	}
+
+	return "", ErrUnknownEdit
+}
@@ -0,0 +1,184 @@
+package playlists
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/apitest"
+)
+
+type fakeEditor struct {
+	calls []string
+}
+
+func (f *fakeEditor) ChangePlaylistDetails(playlistId string, details api.PlaylistDetails) error {
+	f.calls = append(f.calls, fmt.Sprintf("details %s %s", playlistId, details.Name))
+	return nil
+}
+
+func (f *fakeEditor) InsertPlaylistTracks(playlistId string, uris []string, position int) (string, error) {
+	f.calls = append(f.calls, fmt.Sprintf("insert %s %v %d", playlistId, uris, position))
+	return fmt.Sprintf("s%d", len(f.calls)), nil
+}
+
+func (f *fakeEditor) RemovePlaylistTracks(playlistId, snapshotId string, uris []string) (string, error) {
+	f.calls = append(f.calls, fmt.Sprintf("remove %s %s %v", playlistId, snapshotId, uris))
+	return fmt.Sprintf("s%d", len(f.calls)), nil
+}
+
+func (f *fakeEditor) ReorderPlaylistTracks(playlistId string, reorder api.Reorder) (string, error) {
+	f.calls = append(f.calls, fmt.Sprintf("reorder %s %+v", playlistId, reorder))
+	return fmt.Sprintf("s%d", len(f.calls)), nil
+}
+
+func TestReorder(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		move     Move
+		expected []string
+	}{
+		{Move{RangeStart: 0, RangeLength: 1, InsertBefore: 3}, []string{"b", "c", "a", "d", "e"}},
+		{Move{RangeStart: 3, RangeLength: 2, InsertBefore: 0}, []string{"d", "e", "a", "b", "c"}},
+		{Move{RangeStart: 1, RangeLength: 2, InsertBefore: 5}, []string{"a", "d", "e", "b", "c"}},
+		{MoveRow(4, 1), []string{"a", "e", "b", "c", "d"}},
+		{MoveRow(1, 2), []string{"a", "c", "b", "d", "e"}},
+	}
+
+	for _, test := range tests {
+		if err := test.move.Validate(len(items)); err != nil {
+			t.Fatalf("failed to validate %+v: %s", test.move, err)
+		}
+
+		reordered := Reorder(items, test.move)
+		if !reflect.DeepEqual(reordered, test.expected) {
+			t.Fatalf("%+v: invalid order. got=%v, expected=%v", test.move, reordered, test.expected)
+		}
+
+		if restored := Reorder(reordered, test.move.Inverse()); !reflect.DeepEqual(restored, items) {
+			t.Fatalf("%+v: invalid inverse. got=%v, expected=%v", test.move, restored, items)
+		}
+	}
+}
+
+func TestMoveValidate(t *testing.T) {
+	moves := []Move{
+		{RangeStart: 0, RangeLength: 0, InsertBefore: 2},
+		{RangeStart: 2, RangeLength: 2, InsertBefore: 0},
+		{RangeStart: 0, RangeLength: 1, InsertBefore: 4},
+		{RangeStart: 1, RangeLength: 1, InsertBefore: 2},
+		MoveRow(1, 1),
+	}
+
+	for _, move := range moves {
+		if err := move.Validate(3); !errors.Is(err, ErrInvalidMove) {
+			t.Fatalf("%+v: invalid error. got=%v, expected=%v", move, err, ErrInvalidMove)
+		}
+	}
+}
+
+func TestPositions(t *testing.T) {
+	positions := Positions([]string{"a", "b", "a"}, "a")
+
+	if !reflect.DeepEqual(positions, []int{0, 2}) {
+		t.Fatalf("invalid positions. got=%v, expected=[0 2]", positions)
+	}
+}
+
+func TestApplyAndUndo(t *testing.T) {
+	editor := &fakeEditor{}
+
+	removal := Edit{Kind: Removed, Uri: "spotify:track:t1", Positions: []int{4, 1}}
+
+	snapshot, err := Apply(editor, "p1", "s0", removal)
+	if err != nil {
+		t.Fatalf("failed to apply removal: %s", err)
+	}
+
+	if _, err := Apply(editor, "p1", snapshot, removal.Undo()); err != nil {
+		t.Fatalf("failed to undo removal: %s", err)
+	}
+
+	rename := Edit{
+		Kind:     Described,
+		Details:  api.PlaylistDetails{Name: "New"},
+		Previous: api.PlaylistDetails{Name: "Old"},
+	}
+
+	if _, err := Apply(editor, "p1", "", rename.Undo()); err != nil {
+		t.Fatalf("failed to undo rename: %s", err)
+	}
+
+	if _, err := Apply(editor, "p1", "s9", Edit{Kind: Moved, Move: MoveRow(0, 2)}.Undo()); err != nil {
+		t.Fatalf("failed to undo move: %s", err)
+	}
+
+	expected := []string{
+		"remove p1 s0 [spotify:track:t1]",
+		"insert p1 [spotify:track:t1] 1",
+		"insert p1 [spotify:track:t1] 4",
+		"details p1 Old",
+		"reorder p1 {RangeStart:2 RangeLength:1 InsertBefore:0 SnapshotId:s9}",
+	}
+	if !reflect.DeepEqual(editor.calls, expected) {
+		t.Fatalf("invalid calls. got=%q, expected=%q", editor.calls, expected)
+	}
+}
+
+func TestUndoAddOfDuplicate(t *testing.T) {
+	items := []api.PlaylistItem{item("a", "A", "X"), item("b", "B", "X"), item("a", "A", "X"), item("c", "C", "X")}
+	uris := itemUris(items)
+
+	library := &apitest.FakeLibrary{Items: map[string][]api.PlaylistItem{"p1": slices.Clone(items)}}
+
+	add := NewAdd(items, "spotify:track:a", len(items))
+
+	snapshot, err := Apply(library, "p1", "s0", add)
+	if err != nil {
+		t.Fatalf("failed to add track: %s", err)
+	}
+
+	added := append(slices.Clone(uris), "spotify:track:a")
+	if got := library.Uris("p1"); !slices.Equal(got, added) {
+		t.Fatalf("invalid tracks after add. got=%v, expected=%v", got, added)
+	}
+
+	if _, err := Apply(library, "p1", snapshot, add.Undo()); err != nil {
+		t.Fatalf("failed to undo add: %s", err)
+	}
+
+	if got := library.Uris("p1"); !slices.Equal(got, uris) {
+		t.Fatalf("invalid tracks after undo. got=%v, expected=%v", got, uris)
+	}
+}
+
+func TestRemoveOneOfDuplicates(t *testing.T) {
+	items := []api.PlaylistItem{item("a", "A", "X"), item("b", "B", "X"), item("a", "A", "X"), item("c", "C", "X"), item("a", "A", "X")}
+	uris := itemUris(items)
+
+	library := &apitest.FakeLibrary{Items: map[string][]api.PlaylistItem{"p1": slices.Clone(items)}}
+
+	removal := NewRemove(items, 2)
+
+	snapshot, err := Apply(library, "p1", "s0", removal)
+	if err != nil {
+		t.Fatalf("failed to remove track: %s", err)
+	}
+
+	removed := slices.Delete(slices.Clone(uris), 2, 3)
+	if got := library.Uris("p1"); !slices.Equal(got, removed) {
+		t.Fatalf("invalid tracks after removal. got=%v, expected=%v", got, removed)
+	}
+
+	if _, err := Apply(library, "p1", snapshot, removal.Undo()); err != nil {
+		t.Fatalf("failed to undo removal: %s", err)
+	}
+
+	if got := library.Uris("p1"); !slices.Equal(got, uris) {
+		t.Fatalf("invalid tracks after undo. got=%v, expected=%v", got, uris)
+	}
+}
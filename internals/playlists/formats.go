@@ -0,0 +1,347 @@
+package playlists
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const (
+	FormatM3u  = "m3u8"
+	FormatCsv  = "csv"
+	FormatJson = "json"
+	FormatXspf = "xspf"
+)
+
+var Formats = []string{FormatM3u, FormatCsv, FormatJson, FormatXspf}
+
+var (
+	ErrUnknownFormat = errors.New("unknown playlist format")
+	ErrInvalidFile   = errors.New("invalid playlist file")
+)
+
+var csvHeader = []string{"title", "artist", "album", "duration_ms", "isrc", "uri"}
+
+var csvColumns = map[string][]string{
+	"title":       {"title", "name", "track name", "track"},
+	"artist":      {"artist", "artists", "artist name(s)", "artist name", "creator"},
+	"album":       {"album", "album name"},
+	"duration_ms": {"duration_ms", "duration (ms)", "duration"},
+	"isrc":        {"isrc"},
+	"uri":         {"uri", "track uri", "spotify uri", "url", "location"},
+}
+
+type Entry struct {
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Album      string `json:"album,omitempty"`
+	DurationMs int    `json:"duration_ms,omitempty"`
+	Isrc       string `json:"isrc,omitempty"`
+	Uri        string `json:"uri,omitempty"`
+}
+
+func (e Entry) String() string {
+	if e.Artist == "" {
+		return e.Title
+	}
+
+	return fmt.Sprintf("%s - %s", e.Artist, e.Title)
+}
+
+func FromTrack(track api.Track) Entry {
+	names := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		names = append(names, artist.Name)
+	}
+
+	return Entry{
+		Title:      track.Name,
+		Artist:     strings.Join(names, ", "),
+		Album:      track.Album.Name,
+		DurationMs: track.DurationMs,
+		Isrc:       track.ExternalIds.Isrc,
+		Uri:        track.Uri,
+	}
+}
+
+func trackUrl(uri string) string {
+	if id, found := strings.CutPrefix(uri, "spotify:track:"); found {
+		return "https://open.spotify.com/track/" + id
+	}
+
+	return uri
+}
+
+func FormatFromPath(path string) (string, error) {
+	extension := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if extension == "m3u" {
+		extension = FormatM3u
+	}
+
+	if !slices.Contains(Formats, extension) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, path)
+	}
+
+	return extension, nil
+}
+
+type xspfTrack struct {
+	Location   string `xml:"location,omitempty"`
+	Identifier string `xml:"identifier,omitempty"`
+	Title      string `xml:"title"`
+	Creator    string `xml:"creator,omitempty"`
+	Album      string `xml:"album,omitempty"`
+	Duration   int    `xml:"duration,omitempty"`
+}
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"http://xspf.org/ns/0/ playlist"`
+	Version string      `xml:"version,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type jsonPlaylist struct {
+	Name   string  `json:"name"`
+	Tracks []Entry `json:"tracks"`
+}
+
+func Write(w io.Writer, format, name string, entries []Entry) error {
+	switch format {
+	case FormatM3u:
+		out := bufio.NewWriter(w)
+		fmt.Fprintln(out, "#EXTM3U")
+		if name != "" {
+			fmt.Fprintf(out, "#PLAYLIST:%s\n", name)
+		}
+		for _, entry := range entries {
+			fmt.Fprintf(out, "#EXTINF:%d,%s\n", entry.DurationMs/1000, entry)
+			if entry.Album != "" {
+				fmt.Fprintf(out, "#EXTALB:%s\n", entry.Album)
+			}
+			fmt.Fprintln(out, trackUrl(entry.Uri))
+		}
+		return out.Flush()
+	case FormatCsv:
+		out := csv.NewWriter(w)
+		out.Write(csvHeader)
+		for _, entry := range entries {
+			out.Write([]string{entry.Title, entry.Artist, entry.Album,
+				strconv.Itoa(entry.DurationMs), entry.Isrc, entry.Uri})
+		}
+		out.Flush()
+		return out.Error()
+	case FormatJson:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(jsonPlaylist{Name: name, Tracks: entries})
+	case FormatXspf:
+		playlist := xspfPlaylist{Version: "1", Title: name}
+		for _, entry := range entries {
+			track := xspfTrack{
+				Location: trackUrl(entry.Uri),
+				Title:    entry.Title,
+				Creator:  entry.Artist,
+				Album:    entry.Album,
+				Duration: entry.DurationMs,
+			}
+			if entry.Isrc != "" {
+				track.Identifier = "isrc:" + entry.Isrc
+			}
+			playlist.Tracks = append(playlist.Tracks, track)
+		}
+		io.WriteString(w, xml.Header)
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", "  ")
+		if err := encoder.Encode(playlist); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, "\n")
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+func Read(r io.Reader, format string) (string, []Entry, error) {
+	switch format {
+	case FormatM3u:
+		return readM3u(r)
+	case FormatCsv:
+		return readCsv(r)
+	case FormatJson:
+		return readJson(r)
+	case FormatXspf:
+		return readXspf(r)
+	}
+
+	return "", nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+}
+
+func splitArtist(info string) (string, string) {
+	if artist, title, found := strings.Cut(info, " - "); found {
+		return strings.TrimSpace(artist), strings.TrimSpace(title)
+	}
+
+	return "", strings.TrimSpace(info)
+}
+
+func readM3u(r io.Reader) (string, []Entry, error) {
+	name := ""
+	entries := []Entry{}
+	pending := Entry{}
+
+	scanner := bufio.NewScanner(skipBom(r))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "", line == "#EXTM3U":
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			seconds, info, _ := strings.Cut(strings.TrimPrefix(line, "#EXTINF:"), ",")
+			if fields := strings.Fields(seconds); len(fields) > 0 {
+				seconds = fields[0]
+			}
+			if duration, err := strconv.Atoi(seconds); err == nil && duration > 0 {
+				pending.DurationMs = duration * 1000
+			}
+			pending.Artist, pending.Title = splitArtist(info)
+		case strings.HasPrefix(line, "#EXTALB:"):
+			pending.Album = strings.TrimSpace(strings.TrimPrefix(line, "#EXTALB:"))
+		case strings.HasPrefix(line, "#"):
+		default:
+			pending.Uri = line
+			if pending.Title == "" {
+				base := filepath.Base(strings.ReplaceAll(line, "\\", "/"))
+				pending.Artist, pending.Title = splitArtist(strings.TrimSuffix(base, filepath.Ext(base)))
+			}
+			entries = append(entries, pending)
+			pending = Entry{}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	return name, entries, nil
+}
+
+func skipBom(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\ufeff" {
+		buffered.Discard(3)
+	}
+
+	return buffered
+}
+
+func readCsv(r io.Reader) (string, []Entry, error) {
+	reader := csv.NewReader(skipBom(r))
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+	if len(rows) == 0 {
+		return "", []Entry{}, nil
+	}
+
+	columns := map[string]int{}
+	for i, header := range rows[0] {
+		header = strings.ToLower(strings.TrimSpace(header))
+		for field, aliases := range csvColumns {
+			if _, ok := columns[field]; !ok && slices.Contains(aliases, header) {
+				columns[field] = i
+			}
+		}
+	}
+
+	if _, ok := columns["title"]; !ok {
+		if _, ok := columns["uri"]; !ok {
+			return "", nil, fmt.Errorf("%w: missing a title or uri column", ErrInvalidFile)
+		}
+	}
+
+	entries := []Entry{}
+	for _, row := range rows[1:] {
+		cell := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		duration, _ := strconv.Atoi(cell("duration_ms"))
+
+		entries = append(entries, Entry{
+			Title:      cell("title"),
+			Artist:     cell("artist"),
+			Album:      cell("album"),
+			DurationMs: duration,
+			Isrc:       cell("isrc"),
+			Uri:        cell("uri"),
+		})
+	}
+
+	return "", entries, nil
+}
+
+func readJson(r io.Reader) (string, []Entry, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	var playlist jsonPlaylist
+	if err := json.Unmarshal(raw, &playlist); err == nil {
+		if playlist.Tracks == nil {
+			playlist.Tracks = []Entry{}
+		}
+		return playlist.Name, playlist.Tracks, nil
+	}
+
+	entries := []Entry{}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	return "", entries, nil
+}
+
+func readXspf(r io.Reader) (string, []Entry, error) {
+	var playlist xspfPlaylist
+	if err := xml.NewDecoder(r).Decode(&playlist); err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	entries := []Entry{}
+	for _, track := range playlist.Tracks {
+		entry := Entry{
+			Title:      track.Title,
+			Artist:     track.Creator,
+			Album:      track.Album,
+			DurationMs: track.Duration,
+			Uri:        track.Location,
+		}
+		if isrc, found := strings.CutPrefix(track.Identifier, "isrc:"); found {
+			entry.Isrc = isrc
+		}
+		entries = append(entries, entry)
+	}
+
+	return playlist.Title, entries, nil
+}
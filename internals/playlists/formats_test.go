@@ -0,0 +1,118 @@
+package playlists
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteAndRead(t *testing.T) {
+	entries := []Entry{
+		{Title: "Song", Artist: "Band, Guest", Album: "Record", DurationMs: 215_000,
+			Isrc: "USABC1234567", Uri: "spotify:track:6rqhFgbbKwnb9MLmUQDhG6"},
+		{Title: "Other & <Song>", Artist: "Solo", DurationMs: 61_000, Uri: "spotify:local:Solo::Other:61"},
+	}
+
+	for _, format := range Formats {
+		var out bytes.Buffer
+		if err := Write(&out, format, "Mix", entries); err != nil {
+			t.Fatalf("%s: failed to write playlist: %s", format, err)
+		}
+
+		name, read, err := Read(&out, format)
+		if err != nil {
+			t.Fatalf("%s: failed to read playlist: %s", format, err)
+		}
+
+		expected := slices.Clone(entries)
+		if format == FormatM3u || format == FormatXspf {
+			expected[0].Uri = "https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6"
+		}
+		if format == FormatM3u {
+			expected[0].Isrc = ""
+		}
+
+		if name != "Mix" && format != FormatCsv {
+			t.Fatalf("%s: invalid name. got=%s, expected=Mix", format, name)
+		}
+
+		if !reflect.DeepEqual(read, expected) {
+			t.Fatalf("%s: invalid entries. got=%+v, expected=%+v", format, read, expected)
+		}
+	}
+}
+
+func TestReadExternalFiles(t *testing.T) {
+	exportify := "\ufeff\"Track URI\",\"Track Name\",\"Artist Name(s)\",\"Album Name\",\"Duration (ms)\",\"ISRC\"\n" +
+		"\"spotify:track:abc\",\"Song\",\"Band\",\"Record\",\"215000\",\"USABC1234567\"\n"
+
+	_, entries, err := Read(strings.NewReader(exportify), FormatCsv)
+	if err != nil {
+		t.Fatalf("failed to read csv: %s", err)
+	}
+
+	expected := []Entry{{Title: "Song", Artist: "Band", Album: "Record", DurationMs: 215_000,
+		Isrc: "USABC1234567", Uri: "spotify:track:abc"}}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("invalid csv entries. got=%+v, expected=%+v", entries, expected)
+	}
+
+	m3u := "#EXTM3U\n#EXTINF:-1,Band - Song\nmusic/song.mp3\nmusic/Other Band - Tune.flac\n"
+
+	_, entries, err = Read(strings.NewReader(m3u), FormatM3u)
+	if err != nil {
+		t.Fatalf("failed to read m3u: %s", err)
+	}
+
+	expected = []Entry{
+		{Title: "Song", Artist: "Band", Uri: "music/song.mp3"},
+		{Title: "Tune", Artist: "Other Band", Uri: "music/Other Band - Tune.flac"},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("invalid m3u entries. got=%+v, expected=%+v", entries, expected)
+	}
+
+	_, entries, err = Read(strings.NewReader(`[{"title": "Song", "artist": "Band"}]`), FormatJson)
+	if err != nil {
+		t.Fatalf("failed to read json: %s", err)
+	}
+
+	if len(entries) != 1 || entries[0].Title != "Song" {
+		t.Fatalf("invalid json entries. got=%+v, expected=[Song]", entries)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	if _, _, err := Read(strings.NewReader("a,b\n1,2\n"), FormatCsv); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidFile)
+	}
+
+	if _, _, err := Read(strings.NewReader("<playlist"), FormatXspf); !errors.Is(err, ErrInvalidFile) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidFile)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]string{
+		"list.m3u":      FormatM3u,
+		"list.M3U8":     FormatM3u,
+		"dir/list.csv":  FormatCsv,
+		"list.xspf":     FormatXspf,
+		"backup.tar.gz": "",
+		"no-extension":  "",
+		"list.json":     FormatJson,
+	}
+
+	for path, expected := range tests {
+		format, err := FormatFromPath(path)
+		if expected == "" && !errors.Is(err, ErrUnknownFormat) {
+			t.Fatalf("%s: invalid error. got=%v, expected=%v", path, err, ErrUnknownFormat)
+		}
+		if format != expected {
+			t.Fatalf("%s: invalid format. got=%q, expected=%q", path, format, expected)
+		}
+	}
+}
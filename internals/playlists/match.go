@@ -0,0 +1,248 @@
+package playlists
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/uri"
+)
+
+const (
+	searchLimit     = 10
+	matchThreshold  = 0.85
+	guessThreshold  = 0.5
+	ambiguityMargin = 0.05
+)
+
+var (
+	bracketsPattern = regexp.MustCompile(`\s*[(\[][^)\]]*[)\]]`)
+	versionPattern  = regexp.MustCompile(`(?i)\s+-\s+.*\b(remaster(ed)?|version|mix|edit|live|mono|stereo|deluxe)\b.*$`)
+	featPattern     = regexp.MustCompile(`(?i)\s+(feat\.?|ft\.?|featuring)\s.*$`)
+)
+
+type Catalog interface {
+	SearchTracks(query string, limit int) ([]api.Track, error)
+	GetTrack(id string) (api.Track, error)
+}
+
+type Status int
+
+const (
+	Matched Status = iota
+	Ambiguous
+	Unmatched
+)
+
+type Match struct {
+	Entry      Entry
+	Status     Status
+	Track      api.Track
+	Candidates []api.Track
+	Score      float64
+}
+
+func (m Match) Resolved() bool {
+	return m.Status == Matched
+}
+
+func Normalize(value string) string {
+	value = bracketsPattern.ReplaceAllString(value, "")
+	value = versionPattern.ReplaceAllString(value, "")
+	value = featPattern.ReplaceAllString(value, "")
+
+	var normalized strings.Builder
+	space := false
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if space && normalized.Len() > 0 {
+				normalized.WriteRune(' ')
+			}
+			normalized.WriteRune(r)
+			space = false
+		} else {
+			space = true
+		}
+	}
+
+	return normalized.String()
+}
+
+func similarity(a, b string) float64 {
+	first, second := []rune(a), []rune(b)
+	if len(first) == 0 && len(second) == 0 {
+		return 1
+	}
+
+	previous := make([]int, len(second)+1)
+	current := make([]int, len(second)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(first); i++ {
+		current[0] = i
+		for j := 1; j <= len(second); j++ {
+			cost := 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return 1 - float64(previous[len(second)])/float64(max(len(first), len(second)))
+}
+
+func Score(entry Entry, track api.Track) float64 {
+	title := similarity(Normalize(entry.Title), Normalize(track.Name))
+
+	artist := 0.0
+	if entry.Artist == "" {
+		artist = 0.5
+	}
+	for _, name := range strings.Split(entry.Artist, ",") {
+		if name = Normalize(name); name == "" {
+			continue
+		}
+		for _, candidate := range track.Artists {
+			artist = max(artist, similarity(name, Normalize(candidate.Name)))
+		}
+	}
+
+	if entry.DurationMs <= 0 || track.DurationMs <= 0 {
+		return 0.6*title + 0.4*artist
+	}
+
+	difference := max(entry.DurationMs-track.DurationMs, track.DurationMs-entry.DurationMs)
+	duration := max(0, 1-float64(difference)/10_000)
+
+	return 0.5*title + 0.35*artist + 0.15*duration
+}
+
+func equivalent(a, b api.Track) bool {
+	if a.ExternalIds.Isrc != "" && a.ExternalIds.Isrc == b.ExternalIds.Isrc {
+		return true
+	}
+
+	if len(a.Artists) == 0 || len(b.Artists) == 0 {
+		return false
+	}
+
+	return Normalize(a.Name) == Normalize(b.Name) &&
+		Normalize(a.Artists[0].Name) == Normalize(b.Artists[0].Name)
+}
+
+func quote(value string) string {
+	return strings.ReplaceAll(value, `"`, "")
+}
+
+func resolve(catalog Catalog, entry Entry) (Match, error) {
+	if trackUri, err := uri.TrackUri(entry.Uri); err == nil {
+		track, err := catalog.GetTrack(strings.TrimPrefix(trackUri, "spotify:track:"))
+		if err == nil {
+			return Match{Entry: entry, Status: Matched, Track: track, Score: 1}, nil
+		}
+	}
+
+	if entry.Isrc != "" {
+		tracks, err := catalog.SearchTracks("isrc:"+entry.Isrc, 1)
+		if err != nil {
+			return Match{}, err
+		}
+		if len(tracks) > 0 {
+			return Match{Entry: entry, Status: Matched, Track: tracks[0], Score: 1}, nil
+		}
+	}
+
+	if entry.Title == "" {
+		return Match{Entry: entry, Status: Unmatched}, nil
+	}
+
+	query := fmt.Sprintf(`track:"%s"`, quote(entry.Title))
+	if artist, _, _ := strings.Cut(entry.Artist, ","); artist != "" {
+		query += fmt.Sprintf(` artist:"%s"`, quote(strings.TrimSpace(artist)))
+	}
+
+	tracks, err := catalog.SearchTracks(query, searchLimit)
+	if err != nil {
+		return Match{}, err
+	}
+
+	if len(tracks) == 0 {
+		tracks, err = catalog.SearchTracks(entry.String(), searchLimit)
+		if err != nil {
+			return Match{}, err
+		}
+	}
+
+	match := Match{Entry: entry, Status: Unmatched}
+
+	scores := map[string]float64{}
+	for _, track := range tracks {
+		scores[track.Uri] = Score(entry, track)
+	}
+	slices.SortStableFunc(tracks, func(a, b api.Track) int {
+		return cmp.Compare(scores[b.Uri], scores[a.Uri])
+	})
+
+	second := 0.0
+	for i, track := range tracks {
+		score := scores[track.Uri]
+		switch {
+		case i == 0:
+			match.Score, match.Track = score, track
+		case second == 0 && !equivalent(match.Track, track):
+			second = score
+		}
+		if score >= guessThreshold {
+			match.Candidates = append(match.Candidates, track)
+		}
+	}
+
+	switch {
+	case match.Score >= matchThreshold && match.Score-second > ambiguityMargin:
+		match.Status = Matched
+	case match.Score >= guessThreshold:
+		match.Status = Ambiguous
+	default:
+		match.Track = api.Track{}
+		match.Candidates = nil
+	}
+
+	return match, nil
+}
+
+func Resolve(catalog Catalog, entries []Entry, progress func(done int)) ([]Match, error) {
+	matches := make([]Match, 0, len(entries))
+
+	for i, entry := range entries {
+		match, err := resolve(catalog, entry)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+
+		if progress != nil {
+			progress(i + 1)
+		}
+	}
+
+	return matches, nil
+}
+
+func Uris(matches []Match, guesses bool) []string {
+	uris := []string{}
+	for _, match := range matches {
+		if match.Status == Matched || guesses && match.Status == Ambiguous {
+			uris = append(uris, match.Track.Uri)
+		}
+	}
+
+	return uris
+}
@@ -0,0 +1,123 @@
+package playlists
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+type fakeCatalog struct {
+	tracks  map[string]api.Track
+	results map[string][]api.Track
+	queries []string
+}
+
+func (f *fakeCatalog) SearchTracks(query string, limit int) ([]api.Track, error) {
+	f.queries = append(f.queries, query)
+	return f.results[query], nil
+}
+
+func (f *fakeCatalog) GetTrack(id string) (api.Track, error) {
+	if track, ok := f.tracks[id]; ok {
+		return track, nil
+	}
+	return api.Track{}, errors.New("not found")
+}
+
+func track(uri, name, artist string, durationMs int) api.Track {
+	return api.Track{Uri: uri, Name: name, DurationMs: durationMs, Artists: []api.Artist{{Name: artist}}}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Song (Remastered 2011)":        "song",
+		"Song - 2011 Remaster":          "song",
+		"Song [Live] feat. Someone":     "song",
+		"  Don't   Stop -  Me Now!":     "don t stop me now",
+		"Café Del Mar - Energy 52 Mix":  "café del mar",
+		"Blue (Da Ba Dee) - Radio Edit": "blue",
+	}
+
+	for value, expected := range tests {
+		if normalized := Normalize(value); normalized != expected {
+			t.Fatalf("%q: invalid normalization. got=%q, expected=%q", value, normalized, expected)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	song := track("spotify:track:a", "Song - Remastered", "Band", 200_000)
+	cover := track("spotify:track:b", "Song", "Cover Band", 180_000)
+	isrc := track("spotify:track:c", "Isrc Song", "Band", 100_000)
+	first := track("spotify:track:d", "Twin", "Duo", 150_000)
+	second := track("spotify:track:e", "Twin", "Trio", 150_000)
+
+	catalog := &fakeCatalog{
+		tracks: map[string]api.Track{"6rqhFgbbKwnb9MLmUQDhG6": song},
+		results: map[string][]api.Track{
+			"isrc:USABC1234567":          {isrc},
+			`track:"Song" artist:"Band"`: {cover, song},
+			`track:"Twin"`:               {first, second},
+			"Nobody - Nothing":           {cover},
+		},
+	}
+
+	entries := []Entry{
+		{Title: "Anything", Uri: "https://open.spotify.com/track/6rqhFgbbKwnb9MLmUQDhG6"},
+		{Title: "Isrc Song", Isrc: "USABC1234567"},
+		{Title: "Song", Artist: "Band", DurationMs: 201_000},
+		{Title: "Twin"},
+		{Title: "Nothing", Artist: "Nobody"},
+	}
+
+	done := 0
+	matches, err := Resolve(catalog, entries, func(n int) { done = n })
+	if err != nil {
+		t.Fatalf("failed to resolve entries: %s", err)
+	}
+
+	if done != len(entries) {
+		t.Fatalf("invalid progress. got=%d, expected=%d", done, len(entries))
+	}
+
+	expected := []struct {
+		status Status
+		uri    string
+	}{
+		{Matched, song.Uri},
+		{Matched, isrc.Uri},
+		{Matched, song.Uri},
+		{Ambiguous, first.Uri},
+		{Unmatched, ""},
+	}
+
+	for i, match := range matches {
+		if match.Status != expected[i].status || match.Track.Uri != expected[i].uri {
+			t.Fatalf("%s: invalid match. got=%v %q (%.2f), expected=%v %q",
+				match.Entry, match.Status, match.Track.Uri, match.Score, expected[i].status, expected[i].uri)
+		}
+	}
+
+	if len(matches[3].Candidates) != 2 {
+		t.Fatalf("invalid candidates. got=%+v, expected=2", matches[3].Candidates)
+	}
+}
+
+func TestResolveEquivalentReleases(t *testing.T) {
+	album := track("spotify:track:a", "Song", "Band", 200_000)
+	single := track("spotify:track:b", "Song", "Band", 200_000)
+
+	catalog := &fakeCatalog{results: map[string][]api.Track{
+		`track:"Song" artist:"Band"`: {album, single},
+	}}
+
+	matches, err := Resolve(catalog, []Entry{{Title: "Song", Artist: "Band"}}, nil)
+	if err != nil {
+		t.Fatalf("failed to resolve entries: %s", err)
+	}
+
+	if matches[0].Status != Matched || matches[0].Track.Uri != album.Uri {
+		t.Fatalf("invalid match. got=%+v, expected=%s matched", matches[0], album.Uri)
+	}
+}
@@ -0,0 +1,346 @@
+package playlists
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+type SortKey string
+
+const (
+	SortArtist   SortKey = "artist"
+	SortAlbum    SortKey = "album"
+	SortAdded    SortKey = "added"
+	SortDuration SortKey = "duration"
+	SortRelease  SortKey = "release"
+)
+
+var SortKeys = []SortKey{SortArtist, SortAlbum, SortAdded, SortDuration, SortRelease}
+
+var (
+	ErrUnknownSortKey = errors.New("unknown sort key")
+	ErrInvalidYears   = errors.New("invalid year range")
+)
+
+type DedupeOptions struct {
+	Isrc  bool
+	Names bool
+}
+
+func trackKeys(track api.Track, options DedupeOptions) []string {
+	keys := []string{"uri:" + track.Uri}
+
+	if track.Id != "" {
+		keys = append(keys, "id:"+track.Id)
+	}
+
+	if options.Isrc && track.ExternalIds.Isrc != "" {
+		keys = append(keys, "isrc:"+strings.ToUpper(track.ExternalIds.Isrc))
+	}
+
+	if options.Names && len(track.Artists) > 0 {
+		name := Normalize(track.Name)
+		artist := Normalize(track.Artists[0].Name)
+		if name != "" && artist != "" {
+			keys = append(keys, "name:"+name+"\x00"+artist)
+		}
+	}
+
+	return keys
+}
+
+func Dedupe(items []api.PlaylistItem, options DedupeOptions) []api.PlaylistItem {
+	seen := map[string]bool{}
+	kept := []api.PlaylistItem{}
+
+	for _, item := range items {
+		keys := trackKeys(item.Track, options)
+
+		duplicate := false
+		for _, key := range keys {
+			duplicate = duplicate || seen[key]
+		}
+		if duplicate {
+			continue
+		}
+
+		for _, key := range keys {
+			seen[key] = true
+		}
+		kept = append(kept, item)
+	}
+
+	return kept
+}
+
+func ParseSortKey(value string) (SortKey, error) {
+	key := SortKey(strings.ToLower(value))
+	if !slices.Contains(SortKeys, key) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownSortKey, value)
+	}
+
+	return key, nil
+}
+
+func firstArtist(track api.Track) string {
+	if len(track.Artists) == 0 {
+		return ""
+	}
+
+	return strings.ToLower(track.Artists[0].Name)
+}
+
+func Sort(items []api.PlaylistItem, key SortKey, descending bool) []api.PlaylistItem {
+	compare := func(a, b api.PlaylistItem) int {
+		switch key {
+		case SortArtist:
+			return cmp.Or(
+				cmp.Compare(firstArtist(a.Track), firstArtist(b.Track)),
+				cmp.Compare(strings.ToLower(a.Track.Album.Name), strings.ToLower(b.Track.Album.Name)))
+		case SortAlbum:
+			return cmp.Compare(strings.ToLower(a.Track.Album.Name), strings.ToLower(b.Track.Album.Name))
+		case SortAdded:
+			return a.AddedAt.Compare(b.AddedAt)
+		case SortDuration:
+			return cmp.Compare(a.Track.DurationMs, b.Track.DurationMs)
+		case SortRelease:
+			return cmp.Compare(a.Track.Album.ReleaseDate, b.Track.Album.ReleaseDate)
+		}
+		return 0
+	}
+
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b api.PlaylistItem) int {
+		if descending {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
+
+	return sorted
+}
+
+func Merge(lists ...[]api.PlaylistItem) []api.PlaylistItem {
+	return slices.Concat(lists...)
+}
+
+type Filter struct {
+	FromYear        int
+	ToYear          int
+	ExcludeArtists  []string
+	ExcludeExplicit bool
+}
+
+func ParseYears(value string) (int, int, error) {
+	if value == "" {
+		return 0, 0, nil
+	}
+
+	from, to, found := strings.Cut(value, "-")
+	if !found {
+		to = from
+	}
+
+	first, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil && from != "" {
+		return 0, 0, fmt.Errorf("%w: %s", ErrInvalidYears, value)
+	}
+
+	last, err := strconv.Atoi(strings.TrimSpace(to))
+	if err != nil && to != "" {
+		return 0, 0, fmt.Errorf("%w: %s", ErrInvalidYears, value)
+	}
+
+	if last != 0 && first > last {
+		return 0, 0, fmt.Errorf("%w: %s", ErrInvalidYears, value)
+	}
+
+	return first, last, nil
+}
+
+func releaseYear(track api.Track) int {
+	year, _ := strconv.Atoi(strings.SplitN(track.Album.ReleaseDate, "-", 2)[0])
+
+	return year
+}
+
+func (f Filter) Keep(track api.Track) bool {
+	if f.ExcludeExplicit && track.Explicit {
+		return false
+	}
+
+	if f.FromYear != 0 || f.ToYear != 0 {
+		year := releaseYear(track)
+		if year == 0 || f.FromYear != 0 && year < f.FromYear || f.ToYear != 0 && year > f.ToYear {
+			return false
+		}
+	}
+
+	for _, excluded := range f.ExcludeArtists {
+		excluded = Normalize(excluded)
+		for _, artist := range track.Artists {
+			if Normalize(artist.Name) == excluded {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func FilterItems(items []api.PlaylistItem, filter Filter) []api.PlaylistItem {
+	return slices.DeleteFunc(slices.Clone(items), func(item api.PlaylistItem) bool {
+		return !filter.Keep(item.Track)
+	})
+}
+
+type Change struct {
+	Item api.PlaylistItem
+	From int
+	To   int
+}
+
+func (c Change) Moved() bool {
+	return c.From >= 0 && c.To >= 0 && c.From != c.To
+}
+
+func occurrences(items []api.PlaylistItem) map[string][]int {
+	positions := map[string][]int{}
+	for i, item := range items {
+		positions[item.Track.Uri] = append(positions[item.Track.Uri], i)
+	}
+
+	return positions
+}
+
+func Compare(before, after []api.PlaylistItem) []Change {
+	positions := occurrences(before)
+	matched := map[int]bool{}
+
+	changes := make([]Change, 0, len(after))
+	for i, item := range after {
+		change := Change{Item: item, From: -1, To: i}
+
+		if remaining := positions[item.Track.Uri]; len(remaining) > 0 {
+			change.From = remaining[0]
+			positions[item.Track.Uri] = remaining[1:]
+			matched[change.From] = true
+		}
+
+		changes = append(changes, change)
+	}
+
+	for i, item := range before {
+		if !matched[i] {
+			changes = append(changes, Change{Item: item, From: i, To: -1})
+		}
+	}
+
+	return changes
+}
+
+func Moves(current, target []string) ([]Move, error) {
+	if !slices.Equal(slices.Sorted(slices.Values(current)), slices.Sorted(slices.Values(target))) {
+		return nil, fmt.Errorf("%w: the tracks differ", ErrInvalidMove)
+	}
+
+	work := slices.Clone(current)
+	moves := []Move{}
+
+	for i := range target {
+		if work[i] == target[i] {
+			continue
+		}
+
+		j := i + 1 + slices.Index(work[i+1:], target[i])
+		move := MoveRow(j, i)
+
+		work = Reorder(work, move)
+		moves = append(moves, move)
+	}
+
+	return moves, nil
+}
+
+func itemUris(items []api.PlaylistItem) []string {
+	uris := make([]string, 0, len(items))
+	for _, item := range items {
+		uris = append(uris, item.Track.Uri)
+	}
+
+	return uris
+}
+
+func Rewrite(editor Editor, playlistId, snapshotId string, before, after []api.PlaylistItem) (string, error) {
+	existing := occurrences(before)
+
+	counts := map[string]int{}
+	for _, uri := range itemUris(after) {
+		if len(existing[uri]) <= counts[uri] {
+			return "", fmt.Errorf("%w: %s is not in the playlist", ErrInvalidMove, uri)
+		}
+		counts[uri]++
+	}
+
+	removed := []string{}
+	for uri, positions := range existing {
+		if len(positions) > counts[uri] {
+			removed = append(removed, uri)
+		}
+	}
+	slices.Sort(removed)
+
+	if len(removed) > 0 {
+		snapshot, err := editor.RemovePlaylistTracks(playlistId, snapshotId, removed)
+		if err != nil {
+			return "", err
+		}
+		snapshotId = snapshot
+	}
+
+	dropped := func(uri string) bool {
+		_, found := slices.BinarySearch(removed, uri)
+		return found
+	}
+
+	current := slices.DeleteFunc(itemUris(before), dropped)
+	target := slices.DeleteFunc(itemUris(after), dropped)
+
+	moves, err := Moves(current, target)
+	if err != nil {
+		return "", err
+	}
+
+	for _, move := range moves {
+		snapshot, err := editor.ReorderPlaylistTracks(playlistId, api.Reorder{
+			RangeStart:   move.RangeStart,
+			RangeLength:  move.RangeLength,
+			InsertBefore: move.InsertBefore,
+			SnapshotId:   snapshotId,
+		})
+		if err != nil {
+			return "", err
+		}
+		snapshotId = snapshot
+	}
+
+	for i, uri := range itemUris(after) {
+		if !dropped(uri) {
+			continue
+		}
+
+		snapshot, err := editor.InsertPlaylistTracks(playlistId, []string{uri}, i)
+		if err != nil {
+			return "", err
+		}
+		snapshotId = snapshot
+	}
+
+	return snapshotId, nil
+}
@@ -0,0 +1,218 @@
+package playlists
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/apitest"
+)
+
+func item(id, name, artist string) api.PlaylistItem {
+	track := api.Track{Id: id, Uri: "spotify:track:" + id, Name: name, Artists: []api.Artist{{Name: artist}}}
+
+	return api.PlaylistItem{Track: track}
+}
+
+func ids(items []api.PlaylistItem) []string {
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.Track.Id)
+	}
+
+	return ids
+}
+
+func TestDedupe(t *testing.T) {
+	remaster := item("b2", "Song (2011 Remaster)", "Band")
+	isrc := item("c2", "Other", "Band")
+	isrc.Track.ExternalIds.Isrc = "usabc123"
+	original := item("c1", "Other", "Band")
+	original.Track.ExternalIds.Isrc = "USABC123"
+
+	items := []api.PlaylistItem{
+		item("a", "A", "X"), item("b1", "Song", "Band"), item("a", "A", "X"), remaster, original, isrc,
+	}
+
+	cases := []struct {
+		options  DedupeOptions
+		expected []string
+	}{
+		{DedupeOptions{}, []string{"a", "b1", "b2", "c1", "c2"}},
+		{DedupeOptions{Isrc: true}, []string{"a", "b1", "b2", "c1"}},
+		{DedupeOptions{Isrc: true, Names: true}, []string{"a", "b1", "c1"}},
+	}
+
+	for _, c := range cases {
+		if got := ids(Dedupe(items, c.options)); !slices.Equal(got, c.expected) {
+			t.Fatalf("%+v: invalid items. got=%v, expected=%v", c.options, got, c.expected)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	first := item("1", "One", "beta")
+	first.AddedAt = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	first.Track.DurationMs = 300
+	first.Track.Album.ReleaseDate = "1999-05-01"
+
+	second := item("2", "Two", "Alpha")
+	second.AddedAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	second.Track.DurationMs = 100
+	second.Track.Album.ReleaseDate = "2010"
+
+	third := item("3", "Three", "gamma")
+	third.AddedAt = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	third.Track.DurationMs = 200
+	third.Track.Album.ReleaseDate = "1985-01-01"
+
+	items := []api.PlaylistItem{first, second, third}
+
+	cases := map[SortKey][]string{
+		SortArtist:   {"2", "1", "3"},
+		SortAdded:    {"2", "3", "1"},
+		SortDuration: {"2", "3", "1"},
+		SortRelease:  {"3", "1", "2"},
+	}
+
+	for key, expected := range cases {
+		if got := ids(Sort(items, key, false)); !slices.Equal(got, expected) {
+			t.Fatalf("%s: invalid order. got=%v, expected=%v", key, got, expected)
+		}
+	}
+
+	if got := ids(Sort(items, SortDuration, true)); !slices.Equal(got, []string{"1", "3", "2"}) {
+		t.Fatalf("invalid descending order. got=%v, expected=[1 3 2]", got)
+	}
+
+	if _, err := ParseSortKey("colour"); !errors.Is(err, ErrUnknownSortKey) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrUnknownSortKey)
+	}
+}
+
+func TestParseYears(t *testing.T) {
+	cases := map[string][2]int{
+		"1990-1999": {1990, 1999},
+		"2001":      {2001, 2001},
+		"1990-":     {1990, 0},
+		"-1980":     {0, 1980},
+	}
+
+	for value, expected := range cases {
+		from, to, err := ParseYears(value)
+		if err != nil {
+			t.Fatalf("%s: failed to parse years: %s", value, err)
+		}
+
+		if from != expected[0] || to != expected[1] {
+			t.Fatalf("%s: invalid years. got=%d-%d, expected=%d-%d", value, from, to, expected[0], expected[1])
+		}
+	}
+
+	for _, value := range []string{"1999-1990", "nineties", "1990-x"} {
+		if _, _, err := ParseYears(value); !errors.Is(err, ErrInvalidYears) {
+			t.Fatalf("%s: invalid error. got=%v, expected=%v", value, err, ErrInvalidYears)
+		}
+	}
+}
+
+func TestFilterItems(t *testing.T) {
+	old := item("1", "Old", "Someone")
+	old.Track.Album.ReleaseDate = "1975"
+
+	nineties := item("2", "Nineties", "Someone")
+	nineties.Track.Album.ReleaseDate = "1994-10-10"
+
+	explicit := item("3", "Explicit", "Someone")
+	explicit.Track.Album.ReleaseDate = "1995"
+	explicit.Track.Explicit = true
+
+	excluded := item("4", "Excluded", "Nobody Else")
+	excluded.Track.Album.ReleaseDate = "1996"
+	excluded.Track.Artists = append(excluded.Track.Artists, api.Artist{Name: "Bad Band"})
+
+	filter := Filter{FromYear: 1990, ToYear: 1999, ExcludeArtists: []string{"bad band"}, ExcludeExplicit: true}
+
+	got := ids(FilterItems([]api.PlaylistItem{old, nineties, explicit, excluded}, filter))
+	if !slices.Equal(got, []string{"2"}) {
+		t.Fatalf("invalid items. got=%v, expected=[2]", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := []api.PlaylistItem{item("a", "", ""), item("b", "", ""), item("a", "", ""), item("c", "", "")}
+	after := []api.PlaylistItem{item("c", "", ""), item("a", "", ""), item("d", "", "")}
+
+	changes := Compare(before, after)
+
+	expected := [][2]int{{3, 0}, {0, 1}, {-1, 2}, {1, -1}, {2, -1}}
+	if len(changes) != len(expected) {
+		t.Fatalf("invalid changes. got=%+v, expected=%v", changes, expected)
+	}
+	for i, change := range changes {
+		if change.From != expected[i][0] || change.To != expected[i][1] {
+			t.Fatalf("invalid change %d. got=%d→%d, expected=%v", i, change.From, change.To, expected[i])
+		}
+	}
+}
+
+func TestMoves(t *testing.T) {
+	random := rand.New(rand.NewPCG(1, 2))
+
+	for range 50 {
+		current := []string{"a", "b", "c", "a", "d", "e", "f", "b"}
+		target := slices.Clone(current)
+		random.Shuffle(len(target), func(i, j int) { target[i], target[j] = target[j], target[i] })
+
+		moves, err := Moves(current, target)
+		if err != nil {
+			t.Fatalf("failed to compute moves: %s", err)
+		}
+
+		work := current
+		for _, move := range moves {
+			if err := move.Validate(len(work)); err != nil {
+				t.Fatalf("failed to validate move: %s", err)
+			}
+			work = Reorder(work, move)
+		}
+
+		if !slices.Equal(work, target) || len(moves) >= len(current) {
+			t.Fatalf("invalid moves from %v. got=%v in %d moves, expected=%v", current, work, len(moves), target)
+		}
+	}
+
+	if _, err := Moves([]string{"a"}, []string{"b"}); !errors.Is(err, ErrInvalidMove) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidMove)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	items := []api.PlaylistItem{
+		item("c", "C", "X"), item("a", "A", "X"), item("b", "B", "X"), item("a", "A", "X"), item("d", "D", "Y"),
+	}
+
+	library := &apitest.FakeLibrary{Items: map[string][]api.PlaylistItem{"p1": slices.Clone(items)}}
+
+	after := Sort(Dedupe(FilterItems(items, Filter{ExcludeArtists: []string{"y"}}), DedupeOptions{}), SortArtist, false)
+	after = slices.SortedStableFunc(slices.Values(after), func(a, b api.PlaylistItem) int {
+		return strings.Compare(a.Track.Name, b.Track.Name)
+	})
+
+	if _, err := Rewrite(library, "p1", "s0", items, after); err != nil {
+		t.Fatalf("failed to rewrite playlist: %s", err)
+	}
+
+	expected := []string{"spotify:track:a", "spotify:track:b", "spotify:track:c"}
+	if got := library.Uris("p1"); !slices.Equal(got, expected) {
+		t.Fatalf("invalid tracks after %v. got=%v, expected=%v", library.Calls(), got, expected)
+	}
+
+	if _, err := Rewrite(library, "p1", "", after, []api.PlaylistItem{item("z", "", "")}); !errors.Is(err, ErrInvalidMove) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidMove)
+	}
+}
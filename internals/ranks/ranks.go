@@ -0,0 +1,110 @@
+package ranks
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var ErrCorruptStore = errors.New("corrupt rankings file")
+
+type Ranking struct {
+	Ids []string  `json:"ids"`
+	At  time.Time `json:"at"`
+}
+
+func (r Ranking) Known() bool {
+	return r.Ids != nil
+}
+
+type Change struct {
+	New   bool
+	Delta int
+}
+
+func Compare(previous Ranking, current []string) []Change {
+	if !previous.Known() {
+		return nil
+	}
+
+	positions := make(map[string]int, len(previous.Ids))
+	for i, id := range previous.Ids {
+		positions[id] = i
+	}
+
+	changes := make([]Change, len(current))
+	for i, id := range current {
+		if position, ok := positions[id]; ok {
+			changes[i].Delta = position - i
+		} else {
+			changes[i].New = true
+		}
+	}
+
+	return changes
+}
+
+type Store struct {
+	path string
+
+	mu sync.Mutex
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+func (s *Store) load() (map[string]Ranking, error) {
+	rankings := map[string]Ranking{}
+
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rankings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &rankings); err != nil {
+		return nil, ErrCorruptStore
+	}
+
+	return rankings, nil
+}
+
+func (s *Store) save(rankings map[string]Ranking) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	raw, _ := json.MarshalIndent(rankings, "", "  ")
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
+
+func (s *Store) Swap(key string, ids []string, now time.Time) (Ranking, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rankings, err := s.load()
+	if err != nil {
+		return Ranking{}, err
+	}
+
+	previous := rankings[key]
+	rankings[key] = Ranking{Ids: append([]string{}, ids...), At: now}
+
+	if err := s.save(rankings); err != nil {
+		return Ranking{}, err
+	}
+
+	return previous, nil
+}
@@ -0,0 +1,63 @@
+package ranks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	previous := Ranking{Ids: []string{"a", "b", "c"}}
+
+	changes := Compare(previous, []string{"c", "a", "d"})
+
+	expected := []Change{{Delta: 2}, {Delta: -1}, {New: true}}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("invalid changes. got=%+v, expected=%+v", changes, expected)
+	}
+}
+
+func TestCompareUnknown(t *testing.T) {
+	if changes := Compare(Ranking{}, []string{"a"}); changes != nil {
+		t.Fatalf("invalid changes. got=%+v, expected=nil", changes)
+	}
+}
+
+func TestStoreSwap(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "data", "top.json"))
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	previous, err := store.Swap("short_term/tracks", []string{"a", "b"}, now)
+	if err != nil {
+		t.Fatalf("failed to swap ranking: %s", err)
+	}
+
+	if previous.Known() {
+		t.Fatalf("invalid previous ranking. got=%+v, expected=unknown", previous)
+	}
+
+	if _, err := store.Swap("long_term/tracks", []string{"z"}, now); err != nil {
+		t.Fatalf("failed to swap ranking: %s", err)
+	}
+
+	previous, err = NewStore(store.path).Swap("short_term/tracks", []string{"b"}, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("failed to swap ranking: %s", err)
+	}
+
+	if !reflect.DeepEqual(previous.Ids, []string{"a", "b"}) || !previous.At.Equal(now) {
+		t.Fatalf("invalid previous ranking. got=%+v, expected=[a b] at %s", previous, now)
+	}
+}
+
+func TestStoreCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "top.json")
+	os.WriteFile(path, []byte("{"), 0o600)
+
+	if _, err := NewStore(path).Swap("key", nil, time.Now()); !errors.Is(err, ErrCorruptStore) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrCorruptStore)
+	}
+}
@@ -0,0 +1,197 @@
+package remote
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+	"github.com/franciscosbf/spotify-tui/internals/uri"
+)
+
+type StatusSource func() (playback.Status, error)
+
+type volumeRequest struct {
+	Percent int `json:"percent"`
+}
+
+type seekRequest struct {
+	PositionMs int `json:"position_ms"`
+}
+
+type queueRequest struct {
+	Uri string `json:"uri"`
+}
+
+type QueueItem struct {
+	Title  string `json:"title"`
+	Artist string `json:"artist"`
+	Uri    string `json:"uri"`
+}
+
+type queueResponse struct {
+	Queue []QueueItem `json:"queue"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func artists(track api.Track) string {
+	names := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		names = append(names, artist.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+
+	var errResponse api.ErrResponse
+	switch {
+	case errors.Is(err, api.ErrNoActiveDevice):
+		status = http.StatusConflict
+	case errors.Is(err, uri.ErrInvalidUri):
+		status = http.StatusBadRequest
+	case errors.As(err, &errResponse) && errResponse.Status == http.StatusForbidden:
+		status = http.StatusForbidden
+	}
+
+	writeJson(w, status, errorResponse{err.Error()})
+}
+
+func Authorized(token string, r *http.Request) bool {
+	bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	return found && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
+
+func authenticated(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !Authorized(token, r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeJson(w, http.StatusUnauthorized, errorResponse{"invalid token"})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func decode(w http.ResponseWriter, r *http.Request, body any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, 4096)
+
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		writeJson(w, http.StatusBadRequest, errorResponse{"invalid body"})
+		return false
+	}
+
+	return true
+}
+
+func command(op func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := op(); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func Handler(player api.Player, status StatusSource, token string) http.Handler {
+	mux := http.NewServeMux()
+
+	handle := func(pattern string, handler http.HandlerFunc) {
+		mux.HandleFunc(pattern, authenticated(token, handler))
+	}
+
+	mux.HandleFunc("GET /{$}", renderPage)
+
+	handle("GET /api/status", func(w http.ResponseWriter, r *http.Request) {
+		current, err := status()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJson(w, http.StatusOK, current)
+	})
+
+	handle("POST /api/play", command(player.Resume))
+	handle("POST /api/pause", command(player.Pause))
+	handle("POST /api/next", command(player.SkipToNext))
+	handle("POST /api/previous", command(player.SkipToPrevious))
+
+	handle("POST /api/toggle", command(func() error {
+		current, err := status()
+		if err != nil {
+			return err
+		}
+
+		if current.Playing {
+			return player.Pause()
+		}
+
+		return player.Resume()
+	}))
+
+	handle("PUT /api/volume", func(w http.ResponseWriter, r *http.Request) {
+		var body volumeRequest
+		if decode(w, r, &body) {
+			command(func() error { return player.SetVolume(body.Percent) })(w, r)
+		}
+	})
+
+	handle("PUT /api/seek", func(w http.ResponseWriter, r *http.Request) {
+		var body seekRequest
+		if decode(w, r, &body) {
+			command(func() error { return player.Seek(body.PositionMs) })(w, r)
+		}
+	})
+
+	handle("GET /api/queue", func(w http.ResponseWriter, r *http.Request) {
+		queue, err := player.GetQueue()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		items := make([]QueueItem, 0, len(queue.Queue))
+		for _, track := range queue.Queue {
+			items = append(items, QueueItem{track.Name, artists(track), track.Uri})
+		}
+
+		writeJson(w, http.StatusOK, queueResponse{items})
+	})
+
+	handle("POST /api/queue", func(w http.ResponseWriter, r *http.Request) {
+		var body queueRequest
+		if !decode(w, r, &body) {
+			return
+		}
+
+		command(func() error {
+			track, err := uri.TrackUri(body.Uri)
+			if err != nil {
+				return err
+			}
+
+			return player.AddToQueue(track)
+		})(w, r)
+	})
+
+	return mux
+}
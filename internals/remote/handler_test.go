@@ -0,0 +1,141 @@
+package remote
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/apitest"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const testToken = "secret"
+
+func serve(t *testing.T, player *apitest.FakePlayer, status StatusSource) *httptest.Server {
+	server := httptest.NewServer(Handler(player, status, testToken))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func send(t *testing.T, server *httptest.Server, method, path, token, body string) *http.Response {
+	t.Helper()
+
+	request, _ := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("failed to send request: %s", err)
+	}
+	t.Cleanup(func() { response.Body.Close() })
+
+	return response
+}
+
+func playing() (playback.Status, error) {
+	return playback.Status{State: playback.StatePlaying, Playing: true, Title: "Song"}, nil
+}
+
+func TestAuthorization(t *testing.T) {
+	player := &apitest.FakePlayer{}
+	server := serve(t, player, playing)
+
+	for _, token := range []string{"", "wrong"} {
+		if response := send(t, server, http.MethodPost, "/api/next", token, ""); response.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("invalid status with token %q. got=%d, expected=%d", token, response.StatusCode, http.StatusUnauthorized)
+		}
+	}
+
+	if calls := player.Calls(); len(calls) != 0 {
+		t.Fatalf("invalid calls. got=%v, expected=[]", calls)
+	}
+
+	response := send(t, server, http.MethodGet, "/", "", "")
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("invalid page status. got=%d, expected=%d", response.StatusCode, http.StatusOK)
+	}
+
+	page, _ := io.ReadAll(response.Body)
+	if !strings.Contains(string(page), "/api/status") {
+		t.Fatalf("invalid page. got=%s, expected to contain /api/status", page)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	server := serve(t, &apitest.FakePlayer{}, playing)
+
+	response := send(t, server, http.MethodGet, "/api/status", testToken, "")
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("invalid status code. got=%d, expected=%d", response.StatusCode, http.StatusOK)
+	}
+
+	var status playback.Status
+	if err := json.NewDecoder(response.Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode status: %s", err)
+	}
+
+	if status.Title != "Song" || !status.Playing {
+		t.Fatalf("invalid status. got=%+v, expected=Song playing", status)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	player := &apitest.FakePlayer{}
+	server := serve(t, player, playing)
+
+	requests := []struct {
+		method, path, body string
+	}{
+		{http.MethodPost, "/api/toggle", ""},
+		{http.MethodPost, "/api/play", ""},
+		{http.MethodPost, "/api/next", ""},
+		{http.MethodPost, "/api/previous", ""},
+		{http.MethodPut, "/api/volume", `{"percent": 40}`},
+		{http.MethodPut, "/api/seek", `{"position_ms": 1000}`},
+		{http.MethodPost, "/api/queue", `{"uri": "https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=abc"}`},
+	}
+
+	for _, r := range requests {
+		if response := send(t, server, r.method, r.path, testToken, r.body); response.StatusCode != http.StatusNoContent {
+			t.Fatalf("%s %s: invalid status code. got=%d, expected=%d", r.method, r.path, response.StatusCode, http.StatusNoContent)
+		}
+	}
+
+	expected := []string{
+		"Pause", "Resume", "SkipToNext", "SkipToPrevious", "SetVolume 40", "Seek 1000",
+		"AddToQueue spotify:track:4uLU6hMCjMI75M1A2tKUQC",
+	}
+
+	if calls := player.Calls(); !slices.Equal(calls, expected) {
+		t.Fatalf("invalid calls. got=%v, expected=%v", calls, expected)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	player := &apitest.FakePlayer{Err: api.ErrNoActiveDevice}
+	server := serve(t, player, playing)
+
+	tests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodPost, "/api/next", "", http.StatusConflict},
+		{http.MethodPost, "/api/queue", `{"uri": "spotify:album:4uLU6hMCjMI75M1A2tKUQC"}`, http.StatusBadRequest},
+		{http.MethodPut, "/api/volume", `not json`, http.StatusBadRequest},
+		{http.MethodGet, "/api/next", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		if response := send(t, server, tt.method, tt.path, testToken, tt.body); response.StatusCode != tt.status {
+			t.Fatalf("%s %s: invalid status code. got=%d, expected=%d", tt.method, tt.path, response.StatusCode, tt.status)
+		}
+	}
+}
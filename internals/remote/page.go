@@ -0,0 +1,140 @@
+package remote
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var controlPage = template.Must(template.New("control").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="referrer" content="no-referrer">
+<title>Spotify TUI - Remote</title>
+<style>
+* { box-sizing: border-box; }
+body { font-family: sans-serif; background: #282828; color: #ebdbb2; margin: 0; padding: 1.5em; display: flex; justify-content: center; }
+main { width: 100%; max-width: 28em; }
+h1 { font-size: 1.1em; color: #928374; margin: 0 0 1em; }
+#title { font-size: 1.4em; color: #b8bb26; margin: 0; }
+#artist { color: #d5c4a1; margin: .3em 0 1em; }
+#error { color: #fb4934; min-height: 1.2em; }
+.controls { display: flex; justify-content: space-between; margin: 1em 0; }
+button { background: #3c3836; color: #ebdbb2; border: 0; border-radius: .5em; font-size: 1.4em; padding: .6em 0; width: 31%; }
+button:active { background: #504945; }
+label { display: block; color: #928374; margin-top: 1em; }
+input[type=range] { width: 100%; }
+form { display: flex; gap: .5em; margin-top: .5em; }
+input[type=text], input[type=password] { flex: 1; background: #3c3836; color: #ebdbb2; border: 0; border-radius: .5em; padding: .7em; }
+form button { width: auto; font-size: 1em; padding: 0 1em; }
+ol { padding-left: 1.2em; color: #d5c4a1; }
+li span { color: #928374; }
+#login { display: none; }
+</style>
+</head>
+<body>
+<main>
+<h1>Spotify TUI</h1>
+<div id="login">
+<p>Enter the remote control token.</p>
+<form id="token-form"><input id="token" type="password" autocomplete="off"><button>Save</button></form>
+</div>
+<div id="player">
+<p id="title">Nothing playing</p>
+<p id="artist"></p>
+<input id="seek" type="range" min="0" max="0" value="0">
+<div class="controls">
+<button id="previous" aria-label="Previous">&#9198;</button>
+<button id="toggle" aria-label="Play or pause">&#9199;</button>
+<button id="next" aria-label="Next">&#9197;</button>
+</div>
+<label for="volume">Volume</label>
+<input id="volume" type="range" min="0" max="100" value="0">
+<label for="uri">Add to queue</label>
+<form id="queue-form"><input id="uri" type="text" placeholder="Spotify track link"><button>Add</button></form>
+<label>Up next</label>
+<ol id="queue"></ol>
+</div>
+<p id="error"></p>
+</main>
+<script>
+const $ = (id) => document.getElementById(id);
+const params = new URLSearchParams(location.hash.slice(1));
+if (params.has("token")) {
+  localStorage.setItem("token", params.get("token"));
+  history.replaceState(null, "", location.pathname);
+}
+let seeking = false, changingVolume = false;
+function showLogin(show) {
+  $("login").style.display = show ? "block" : "none";
+  $("player").style.display = show ? "none" : "block";
+}
+async function call(method, path, body) {
+  const response = await fetch(path, {
+    method,
+    headers: { "Authorization": "Bearer " + localStorage.getItem("token"), "Content-Type": "application/json" },
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (response.status === 401) {
+    showLogin(true);
+    throw new Error("invalid token");
+  }
+  const data = response.status === 204 ? null : await response.json();
+  if (!response.ok) throw new Error(data.error);
+  return data;
+}
+function report(promise) {
+  promise.then(() => { $("error").textContent = ""; refresh(); })
+    .catch((err) => { $("error").textContent = err.message; });
+}
+async function refresh() {
+  try {
+    const status = await call("GET", "/api/status");
+    showLogin(false);
+    $("title").textContent = status.title || "Nothing playing";
+    $("artist").textContent = status.artist;
+    $("toggle").innerHTML = status.playing ? "&#9208;" : "&#9654;";
+    if (!seeking) { $("seek").max = status.duration_ms; $("seek").value = status.progress_ms; }
+    if (!changingVolume) $("volume").value = status.volume;
+    const queue = await call("GET", "/api/queue");
+    $("queue").replaceChildren(...queue.queue.slice(0, 10).map((item) => {
+      const li = document.createElement("li");
+      const artist = document.createElement("span");
+      artist.textContent = " " + item.artist;
+      li.append(item.title, artist);
+      return li;
+    }));
+  } catch (err) {
+    $("error").textContent = err.message;
+  }
+}
+$("previous").onclick = () => report(call("POST", "/api/previous"));
+$("next").onclick = () => report(call("POST", "/api/next"));
+$("toggle").onclick = () => report(call("POST", "/api/toggle"));
+$("seek").oninput = () => { seeking = true; };
+$("seek").onchange = () => { seeking = false; report(call("PUT", "/api/seek", { position_ms: Number($("seek").value) })); };
+$("volume").oninput = () => { changingVolume = true; };
+$("volume").onchange = () => { changingVolume = false; report(call("PUT", "/api/volume", { percent: Number($("volume").value) })); };
+$("queue-form").onsubmit = (event) => {
+  event.preventDefault();
+  report(call("POST", "/api/queue", { uri: $("uri").value }).then(() => { $("uri").value = ""; }));
+};
+$("token-form").onsubmit = (event) => {
+  event.preventDefault();
+  localStorage.setItem("token", $("token").value);
+  refresh();
+};
+refresh();
+setInterval(refresh, 3000);
+</script>
+</body>
+</html>
+`))
+
+func renderPage(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'")
+
+	controlPage.Execute(w, nil)
+}
@@ -0,0 +1,90 @@
+package remote
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+const tokenLength = 32
+
+var ErrMissingToken = errors.New("remote control requires a token")
+
+func GenerateToken() string {
+	raw := make([]byte, tokenLength)
+	rand.Read(raw)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+type Server struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+func Listen(address string) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		listener: listener,
+		server:   &http.Server{ReadHeaderTimeout: 10 * time.Second},
+	}, nil
+}
+
+func lanIp() string {
+	addresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+
+	for _, address := range addresses {
+		ipNet, ok := address.(*net.IPNet)
+		if ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+
+	return ""
+}
+
+func (s *Server) Url() string {
+	addr := s.listener.Addr().(*net.TCPAddr)
+
+	host := addr.IP.String()
+	if addr.IP.IsUnspecified() {
+		host = "localhost"
+		if ip := lanIp(); ip != "" {
+			host = ip
+		}
+	}
+
+	return fmt.Sprintf("http://%s/", net.JoinHostPort(host, fmt.Sprint(addr.Port)))
+}
+
+func (s *Server) Serve(handler http.Handler) error {
+	s.server.Handler = handler
+
+	err := s.server.Serve(s.listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Server) Close() error {
+	err := s.server.Close()
+
+	if closeErr := s.listener.Close(); !errors.Is(closeErr, net.ErrClosed) {
+		err = errors.Join(err, closeErr)
+	}
+
+	return err
+}
@@ -0,0 +1,65 @@
+package remote
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCloseBeforeServe(t *testing.T) {
+	server, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	address := server.listener.Addr().String()
+
+	if err := server.Close(); err != nil {
+		t.Fatalf("failed to close server: %s", err)
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(http.NotFoundHandler())
+	}()
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("invalid serve error. got=%v, expected=<nil>", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server kept serving after being closed")
+	}
+
+	if conn, err := net.Dial("tcp", address); err == nil {
+		conn.Close()
+		t.Fatalf("invalid listener. got=open, expected=closed")
+	}
+}
+
+func TestCloseWhileServing(t *testing.T) {
+	server, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(http.NotFoundHandler())
+	}()
+
+	if err := server.Close(); err != nil {
+		t.Fatalf("failed to close server: %s", err)
+	}
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("invalid serve error. got=%v, expected=<nil>", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server kept serving after being closed")
+	}
+}
@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const notificationsBuffer = 32
+
+var ErrClientClosed = errors.New("rpc connection closed")
+
+type Client struct {
+	conn          net.Conn
+	mu            sync.Mutex
+	enc           *json.Encoder
+	nextId        uint64
+	pending       map[uint64]chan message
+	notifications chan Notification
+	closed        bool
+}
+
+func Dial(path string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(conn), nil
+}
+
+func NewClient(conn net.Conn) *Client {
+	c := &Client{
+		conn:          conn,
+		enc:           json.NewEncoder(conn),
+		pending:       map[uint64]chan message{},
+		notifications: make(chan Notification, notificationsBuffer),
+	}
+
+	go c.read()
+
+	return c
+}
+
+func (c *Client) read() {
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		var msg message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+
+		if msg.Id == nil {
+			if msg.Method == "" {
+				continue
+			}
+
+			select {
+			case c.notifications <- Notification{msg.Method, msg.Params}:
+			default:
+			}
+
+			continue
+		}
+
+		c.mu.Lock()
+		response, ok := c.pending[*msg.Id]
+		delete(c.pending, *msg.Id)
+		c.mu.Unlock()
+
+		if ok {
+			response <- msg
+		}
+	}
+
+	c.mu.Lock()
+	c.closed = true
+	for id, response := range c.pending {
+		close(response)
+		delete(c.pending, id)
+	}
+	c.mu.Unlock()
+
+	close(c.notifications)
+}
+
+func (c *Client) Call(method string, params, result any) error {
+	request := message{Version: version, Method: method}
+
+	if params != nil {
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		request.Params = encoded
+	}
+
+	response := make(chan message, 1)
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClientClosed
+	}
+	c.nextId++
+	id := c.nextId
+	request.Id = &id
+	c.pending[id] = response
+	err := c.enc.Encode(request)
+	c.mu.Unlock()
+
+	if err != nil {
+		return ErrClientClosed
+	}
+
+	msg, ok := <-response
+	if !ok {
+		return ErrClientClosed
+	}
+
+	if msg.Error != nil {
+		return msg.Error
+	}
+
+	if result == nil || len(msg.Result) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(msg.Result, result)
+}
+
+func (c *Client) Notifications() <-chan Notification {
+	return c.notifications
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const version = "2.0"
+
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+func NewError(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func NewErrorWithData(code int, message string, data any) *Error {
+	encoded, _ := json.Marshal(data)
+
+	return &Error{Code: code, Message: message, Data: encoded}
+}
+
+type message struct {
+	Version string          `json:"jsonrpc"`
+	Id      *uint64         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type Notification struct {
+	Method string
+	Params json.RawMessage
+}
@@ -0,0 +1,142 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T, server *Server) string {
+	path := filepath.Join(t.TempDir(), "test.sock")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	return path
+}
+
+func dial(t *testing.T, path string) *Client {
+	client, err := Dial(path, time.Second)
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestCall(t *testing.T) {
+	server := NewServer()
+	server.Handle("sum", func(_ *Conn, params json.RawMessage) (any, error) {
+		var numbers []int
+		if err := DecodeParams(params, &numbers); err != nil {
+			return nil, err
+		}
+
+		total := 0
+		for _, n := range numbers {
+			total += n
+		}
+
+		return total, nil
+	})
+	server.Handle("fail", func(_ *Conn, _ json.RawMessage) (any, error) {
+		return nil, NewError(-32001, "custom failure")
+	})
+
+	client := dial(t, startServer(t, server))
+
+	var total int
+	if err := client.Call("sum", []int{1, 2, 3}, &total); err != nil {
+		t.Fatalf("failed to call sum: %s", err)
+	}
+	if total != 6 {
+		t.Fatalf("invalid total. got=%d, expected=6", total)
+	}
+
+	tests := []struct {
+		method string
+		params any
+		code   int
+	}{
+		{"unknown", nil, CodeMethodNotFound},
+		{"sum", nil, CodeInvalidParams},
+		{"sum", "not numbers", CodeInvalidParams},
+		{"fail", nil, -32001},
+	}
+
+	for _, tt := range tests {
+		err := client.Call(tt.method, tt.params, nil)
+
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != tt.code {
+			t.Fatalf("%s: invalid error. got=%v, expected code %d", tt.method, err, tt.code)
+		}
+	}
+}
+
+func TestNotify(t *testing.T) {
+	server := NewServer()
+	server.Handle("subscribe", func(conn *Conn, _ json.RawMessage) (any, error) {
+		go conn.Notify("event", map[string]string{"name": "changed"})
+
+		return true, nil
+	})
+
+	client := dial(t, startServer(t, server))
+
+	if err := client.Call("subscribe", nil, nil); err != nil {
+		t.Fatalf("failed to subscribe: %s", err)
+	}
+
+	select {
+	case notification := <-client.Notifications():
+		if notification.Method != "event" || string(notification.Params) != `{"name":"changed"}` {
+			t.Fatalf("invalid notification. got=%s %s, expected=event {\"name\":\"changed\"}", notification.Method, notification.Params)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("notification not received")
+	}
+}
+
+func TestMalformedRequest(t *testing.T) {
+	path := startServer(t, NewServer())
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("{not json\n")); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	var response message
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		t.Fatalf("failed to read response: %s", err)
+	}
+
+	if response.Error == nil || response.Error.Code != CodeParseError {
+		t.Fatalf("invalid response. got=%+v, expected code %d", response, CodeParseError)
+	}
+}
+
+func TestServerClose(t *testing.T) {
+	server := NewServer()
+	client := dial(t, startServer(t, server))
+
+	server.Close()
+
+	if err := client.Call("anything", nil, nil); !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrClientClosed)
+	}
+}
@@ -0,0 +1,220 @@
+package rpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+)
+
+var ErrServerClosed = errors.New("rpc server closed")
+
+type Handler func(conn *Conn, params json.RawMessage) (any, error)
+
+type Conn struct {
+	conn net.Conn
+	mu   sync.Mutex
+	enc  *json.Encoder
+	done chan struct{}
+}
+
+func newConn(conn net.Conn) *Conn {
+	return &Conn{conn: conn, enc: json.NewEncoder(conn), done: make(chan struct{})}
+}
+
+func (c *Conn) write(msg message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	msg.Version = version
+
+	return c.enc.Encode(msg)
+}
+
+func (c *Conn) Notify(method string, params any) error {
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	return c.write(message{Method: method, Params: encoded})
+}
+
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+type Server struct {
+	mu       sync.Mutex
+	handlers map[string]Handler
+	listener net.Listener
+	conns    map[*Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+func NewServer() *Server {
+	return &Server{handlers: map[string]Handler{}, conns: map[*Conn]struct{}{}}
+}
+
+func (s *Server) Handle(method string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method] = handler
+}
+
+func (s *Server) handler(method string) (Handler, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	handler, ok := s.handlers[method]
+
+	return handler, ok
+}
+
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		listener.Close()
+		return ErrServerClosed
+	}
+	s.listener = listener
+	s.mu.Unlock()
+
+	for {
+		nc, err := listener.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+
+			if closed {
+				return ErrServerClosed
+			}
+
+			return err
+		}
+
+		conn := newConn(nc)
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			nc.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn *Conn) {
+	defer s.wg.Done()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+
+		close(conn.done)
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn.conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		var request message
+
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			conn.write(message{Error: NewError(CodeParseError, "parse error")})
+			continue
+		}
+
+		if request.Version != version || request.Method == "" {
+			conn.write(message{Id: request.Id, Error: NewError(CodeInvalidRequest, "invalid request")})
+			continue
+		}
+
+		response := s.dispatch(conn, request)
+		if request.Id == nil {
+			continue
+		}
+
+		if err := conn.write(response); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) dispatch(conn *Conn, request message) message {
+	response := message{Id: request.Id}
+
+	handler, ok := s.handler(request.Method)
+	if !ok {
+		response.Error = NewError(CodeMethodNotFound, "method not found")
+		return response
+	}
+
+	result, err := handler(conn, request.Params)
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = NewError(CodeInternalError, err.Error())
+		}
+
+		response.Error = rpcErr
+		return response
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		response.Error = NewError(CodeInternalError, err.Error())
+		return response
+	}
+
+	response.Result = encoded
+
+	return response
+}
+
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	listener := s.listener
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	var err error
+	if listener != nil {
+		err = listener.Close()
+	}
+
+	s.wg.Wait()
+
+	return err
+}
+
+func DecodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		return NewError(CodeInvalidParams, "missing params")
+	}
+
+	if err := json.Unmarshal(params, v); err != nil {
+		return NewError(CodeInvalidParams, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package scrobble
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const (
+	fakeKey     = "key"
+	fakeSecret  = "secret"
+	fakeSession = "session"
+)
+
+var sample = Scrobble{
+	Track: Track{
+		Artist:     "Band",
+		Title:      "Song",
+		Album:      "Album",
+		DurationMs: 200_000,
+		Uri:        "spotify:track:a",
+		Url:        "https://open.spotify.com/track/a",
+	},
+	Timestamp: time.Unix(1714593600, 0),
+}
+
+func fakeLastFm(t *testing.T, respond func(form url.Values) (int, string)) *LastFm {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		signature := r.PostForm.Get("api_sig")
+		check := url.Values{}
+		for key, values := range r.PostForm {
+			if key != "api_sig" {
+				check[key] = values
+			}
+		}
+
+		if signature != NewLastFm(fakeKey, fakeSecret, "").sign(check) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": 13, "message": "Invalid method signature supplied"}`))
+			return
+		}
+
+		status, body := respond(r.PostForm)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewLastFmWithBase(server.URL, fakeKey, fakeSecret, fakeSession)
+}
+
+func TestLastFmSignature(t *testing.T) {
+	params := url.Values{}
+	params.Set("method", "auth.getSession")
+	params.Set("api_key", "xxx")
+	params.Set("token", "yyy")
+	params.Set("format", "json")
+
+	if got := NewLastFm("xxx", "secret", "").sign(params); got != "16f6531c029f18b4bef6b36971bbdf4c" {
+		t.Fatalf("invalid signature. got=%s, expected=16f6531c029f18b4bef6b36971bbdf4c", got)
+	}
+}
+
+func TestLastFmScrobble(t *testing.T) {
+	var received url.Values
+
+	lastFm := fakeLastFm(t, func(form url.Values) (int, string) {
+		received = form
+		return http.StatusOK, `{"scrobbles": {"@attr": {"accepted": 2, "ignored": 0}}}`
+	})
+
+	second := sample
+	second.Title = "Other"
+
+	if err := lastFm.Scrobble([]Scrobble{sample, second}); err != nil {
+		t.Fatalf("failed to scrobble: %s", err)
+	}
+
+	expected := map[string]string{
+		"method":       "track.scrobble",
+		"api_key":      fakeKey,
+		"sk":           fakeSession,
+		"format":       "json",
+		"artist[0]":    "Band",
+		"track[0]":     "Song",
+		"album[0]":     "Album",
+		"duration[0]":  "200",
+		"timestamp[0]": "1714593600",
+		"track[1]":     "Other",
+	}
+	for key, value := range expected {
+		if got := received.Get(key); got != value {
+			t.Fatalf("invalid %s. got=%s, expected=%s", key, got, value)
+		}
+	}
+
+	if err := lastFm.NowPlaying(sample.Track); err != nil {
+		t.Fatalf("failed to update now playing: %s", err)
+	}
+
+	if received.Get("method") != "track.updateNowPlaying" || received.Get("track") != "Song" {
+		t.Fatalf("invalid now playing request. got=%v, expected=track.updateNowPlaying of Song", received)
+	}
+}
+
+func TestLastFmErrors(t *testing.T) {
+	cases := []struct {
+		status   int
+		body     string
+		expected error
+	}{
+		{http.StatusOK, `{"error": 11, "message": "Service Offline"}`, ErrRequestFailed},
+		{http.StatusForbidden, `{"error": 9, "message": "Invalid session key"}`, ErrAuthFailed},
+		{http.StatusBadRequest, `{"error": 6, "message": "Invalid parameters"}`, ErrRejected},
+		{http.StatusBadGateway, `bad gateway`, ErrRequestFailed},
+		{http.StatusNotFound, `not found`, ErrRequestFailed},
+	}
+
+	for _, c := range cases {
+		lastFm := fakeLastFm(t, func(url.Values) (int, string) {
+			return c.status, c.body
+		})
+
+		if err := lastFm.Scrobble([]Scrobble{sample}); !errors.Is(err, c.expected) {
+			t.Fatalf("%s: invalid error. got=%v, expected=%v", c.body, err, c.expected)
+		}
+	}
+}
+
+func TestLastFmSession(t *testing.T) {
+	lastFm := fakeLastFm(t, func(form url.Values) (int, string) {
+		switch form.Get("method") {
+		case "auth.getToken":
+			return http.StatusOK, `{"token": "tok"}`
+		case "auth.getSession":
+			if form.Get("token") != "tok" {
+				return http.StatusForbidden, `{"error": 14, "message": "Unauthorized Token"}`
+			}
+			return http.StatusOK, `{"session": {"name": "user", "key": "new-session"}}`
+		}
+		return http.StatusBadRequest, `{"error": 3, "message": "Invalid Method"}`
+	})
+
+	token, err := lastFm.Token()
+	if err != nil {
+		t.Fatalf("failed to get token: %s", err)
+	}
+
+	if token != "tok" {
+		t.Fatalf("invalid token. got=%s, expected=tok", token)
+	}
+
+	if link := lastFm.AuthUrl(token); link != LastFmAuth+"?api_key=key&token=tok" {
+		t.Fatalf("invalid auth url. got=%s, expected=%s?api_key=key&token=tok", link, LastFmAuth)
+	}
+
+	session, err := lastFm.Session(token)
+	if err != nil {
+		t.Fatalf("failed to get session: %s", err)
+	}
+
+	if session != "new-session" {
+		t.Fatalf("invalid session. got=%s, expected=new-session", session)
+	}
+}
+
+func fakeListenBrainz(t *testing.T, status int, received *submission) *ListenBrainz {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/submit-listens" || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Token user-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code": 401, "error": "Invalid authorization token."}`))
+			return
+		}
+
+		json.NewDecoder(r.Body).Decode(received)
+		w.WriteHeader(status)
+		w.Write([]byte(`{"status": "ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewListenBrainzWithBase(server.URL, "user-token")
+}
+
+func TestListenBrainz(t *testing.T) {
+	var received submission
+	listenBrainz := fakeListenBrainz(t, http.StatusOK, &received)
+
+	if err := listenBrainz.NowPlaying(sample.Track); err != nil {
+		t.Fatalf("failed to update now playing: %s", err)
+	}
+
+	if received.ListenType != listenPlayingNow || received.Payload[0].ListenedAt != 0 {
+		t.Fatalf("invalid now playing. got=%+v, expected=a playing_now listen without timestamp", received)
+	}
+
+	if err := listenBrainz.Scrobble([]Scrobble{sample}); err != nil {
+		t.Fatalf("failed to scrobble: %s", err)
+	}
+
+	listen := received.Payload[0]
+	if received.ListenType != listenSingle || listen.ListenedAt != 1714593600 ||
+		listen.TrackMetadata.ArtistName != "Band" || listen.TrackMetadata.ReleaseName != "Album" ||
+		listen.TrackMetadata.AdditionalInfo.SpotifyId != sample.Url {
+		t.Fatalf("invalid listen. got=%+v, expected=a single listen of the sample", received)
+	}
+
+	if err := listenBrainz.Scrobble([]Scrobble{sample, sample}); err != nil {
+		t.Fatalf("failed to scrobble: %s", err)
+	}
+
+	if received.ListenType != listenImport {
+		t.Fatalf("invalid listen type. got=%s, expected=%s", received.ListenType, listenImport)
+	}
+
+	unauthorized := NewListenBrainzWithBase(listenBrainz.base, "wrong")
+	if err := unauthorized.Scrobble([]Scrobble{sample}); !errors.Is(err, ErrAuthFailed) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrAuthFailed)
+	}
+
+	if err := fakeListenBrainz(t, http.StatusServiceUnavailable, &received).Scrobble([]Scrobble{sample}); !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrRequestFailed)
+	}
+
+	if err := fakeListenBrainz(t, http.StatusBadRequest, &received).Scrobble([]Scrobble{sample}); !errors.Is(err, ErrRejected) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrRejected)
+	}
+
+	if err := fakeListenBrainz(t, http.StatusForbidden, &received).Scrobble([]Scrobble{sample}); !errors.Is(err, ErrRequestFailed) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrRequestFailed)
+	}
+}
@@ -0,0 +1,181 @@
+package scrobble
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	LastFmApi  = "https://ws.audioscrobbler.com/2.0/"
+	LastFmAuth = "https://www.last.fm/api/auth/"
+)
+
+var lastFmAuthErrors = []int{4, 9, 10, 14, 15, 26}
+
+var lastFmRejected = []int{6, 7}
+
+type lastFmError struct {
+	Code    int    `json:"error"`
+	Message string `json:"message"`
+}
+
+type LastFm struct {
+	base    string
+	key     string
+	secret  string
+	session string
+	client  *http.Client
+}
+
+func NewLastFm(key, secret, session string) *LastFm {
+	return NewLastFmWithBase(LastFmApi, key, secret, session)
+}
+
+func NewLastFmWithBase(base, key, secret, session string) *LastFm {
+	return &LastFm{
+		base:    base,
+		key:     key,
+		secret:  secret,
+		session: session,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (l *LastFm) Name() string {
+	return "lastfm"
+}
+
+func (l *LastFm) sign(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if key != "format" && key != "callback" {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var raw strings.Builder
+	for _, key := range keys {
+		raw.WriteString(key)
+		raw.WriteString(params.Get(key))
+	}
+	raw.WriteString(l.secret)
+
+	sum := md5.Sum([]byte(raw.String()))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (l *LastFm) call(method string, params url.Values, result any) error {
+	params.Set("method", method)
+	params.Set("api_key", l.key)
+	if l.session != "" {
+		params.Set("sk", l.session)
+	}
+	params.Set("api_sig", l.sign(params))
+	params.Set("format", "json")
+
+	request, _ := http.NewRequest(http.MethodPost, l.base, strings.NewReader(params.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("User-Agent", userAgent)
+
+	response, err := l.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRequestFailed, err)
+	}
+	defer response.Body.Close()
+
+	raw, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRequestFailed, err)
+	}
+
+	failure := lastFmError{Message: response.Status}
+	json.Unmarshal(raw, &failure)
+
+	switch {
+	case failure.Code == 0 && response.StatusCode == http.StatusOK:
+	case slices.Contains(lastFmAuthErrors, failure.Code):
+		return fmt.Errorf("%w: %s", ErrAuthFailed, failure.Message)
+	case slices.Contains(lastFmRejected, failure.Code):
+		return fmt.Errorf("%w: %s", ErrRejected, failure.Message)
+	default:
+		return fmt.Errorf("%w: %s", ErrRequestFailed, failure.Message)
+	}
+
+	if result != nil && json.Unmarshal(raw, result) != nil {
+		return fmt.Errorf("%w: invalid response", ErrRequestFailed)
+	}
+
+	return nil
+}
+
+func (l *LastFm) NowPlaying(track Track) error {
+	params := url.Values{}
+	params.Set("artist", track.Artist)
+	params.Set("track", track.Title)
+	if track.Album != "" {
+		params.Set("album", track.Album)
+	}
+	params.Set("duration", strconv.Itoa(track.DurationMs/1000))
+
+	return l.call("track.updateNowPlaying", params, nil)
+}
+
+func (l *LastFm) Scrobble(scrobbles []Scrobble) error {
+	params := url.Values{}
+	for i, scrobble := range scrobbles {
+		index := fmt.Sprintf("[%d]", i)
+
+		params.Set("artist"+index, scrobble.Artist)
+		params.Set("track"+index, scrobble.Title)
+		params.Set("timestamp"+index, strconv.FormatInt(scrobble.Timestamp.Unix(), 10))
+		if scrobble.Album != "" {
+			params.Set("album"+index, scrobble.Album)
+		}
+		params.Set("duration"+index, strconv.Itoa(scrobble.DurationMs/1000))
+	}
+
+	return l.call("track.scrobble", params, nil)
+}
+
+func (l *LastFm) Token() (string, error) {
+	var result struct {
+		Token string `json:"token"`
+	}
+
+	if err := l.call("auth.getToken", url.Values{}, &result); err != nil {
+		return "", err
+	}
+
+	return result.Token, nil
+}
+
+func (l *LastFm) AuthUrl(token string) string {
+	return fmt.Sprintf("%s?api_key=%s&token=%s", LastFmAuth, url.QueryEscape(l.key), url.QueryEscape(token))
+}
+
+func (l *LastFm) Session(token string) (string, error) {
+	params := url.Values{}
+	params.Set("token", token)
+
+	var result struct {
+		Session struct {
+			Key string `json:"key"`
+		} `json:"session"`
+	}
+
+	if err := l.call("auth.getSession", params, &result); err != nil {
+		return "", err
+	}
+
+	return result.Session.Key, nil
+}
@@ -0,0 +1,137 @@
+package scrobble
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const ListenBrainzApi = "https://api.listenbrainz.org"
+
+const (
+	listenPlayingNow = "playing_now"
+	listenSingle     = "single"
+	listenImport     = "import"
+)
+
+type listenInfo struct {
+	DurationMs       int    `json:"duration_ms,omitempty"`
+	SpotifyId        string `json:"spotify_id,omitempty"`
+	OriginUrl        string `json:"origin_url,omitempty"`
+	MediaPlayer      string `json:"media_player"`
+	SubmissionClient string `json:"submission_client"`
+	MusicService     string `json:"music_service"`
+}
+
+type listenMetadata struct {
+	ArtistName     string     `json:"artist_name"`
+	TrackName      string     `json:"track_name"`
+	ReleaseName    string     `json:"release_name,omitempty"`
+	AdditionalInfo listenInfo `json:"additional_info"`
+}
+
+type listen struct {
+	ListenedAt    int64          `json:"listened_at,omitempty"`
+	TrackMetadata listenMetadata `json:"track_metadata"`
+}
+
+type submission struct {
+	ListenType string   `json:"listen_type"`
+	Payload    []listen `json:"payload"`
+}
+
+type ListenBrainz struct {
+	base   string
+	token  string
+	client *http.Client
+}
+
+func NewListenBrainz(token string) *ListenBrainz {
+	return NewListenBrainzWithBase(ListenBrainzApi, token)
+}
+
+func NewListenBrainzWithBase(base, token string) *ListenBrainz {
+	return &ListenBrainz{
+		base:   strings.TrimSuffix(base, "/"),
+		token:  token,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (l *ListenBrainz) Name() string {
+	return "listenbrainz"
+}
+
+func toListen(track Track) listen {
+	return listen{
+		TrackMetadata: listenMetadata{
+			ArtistName:  track.Artist,
+			TrackName:   track.Title,
+			ReleaseName: track.Album,
+			AdditionalInfo: listenInfo{
+				DurationMs:       track.DurationMs,
+				SpotifyId:        track.Url,
+				OriginUrl:        track.Url,
+				MediaPlayer:      userAgent,
+				SubmissionClient: userAgent,
+				MusicService:     "spotify.com",
+			},
+		},
+	}
+}
+
+func (l *ListenBrainz) submit(body submission) error {
+	raw, _ := json.Marshal(body)
+
+	request, _ := http.NewRequest(http.MethodPost, l.base+"/1/submit-listens", bytes.NewReader(raw))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set("Authorization", "Token "+l.token)
+
+	response, err := l.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrRequestFailed, err)
+	}
+	defer response.Body.Close()
+
+	failure := struct {
+		Error string `json:"error"`
+	}{response.Status}
+	json.NewDecoder(response.Body).Decode(&failure)
+
+	switch {
+	case response.StatusCode == http.StatusOK:
+		return nil
+	case response.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("%w: %s", ErrAuthFailed, failure.Error)
+	case response.StatusCode == http.StatusBadRequest:
+		return fmt.Errorf("%w: %s", ErrRejected, failure.Error)
+	default:
+		return fmt.Errorf("%w: %s", ErrRequestFailed, failure.Error)
+	}
+}
+
+func (l *ListenBrainz) NowPlaying(track Track) error {
+	return l.submit(submission{
+		ListenType: listenPlayingNow,
+		Payload:    []listen{toListen(track)},
+	})
+}
+
+func (l *ListenBrainz) Scrobble(scrobbles []Scrobble) error {
+	listenType := listenSingle
+	if len(scrobbles) > 1 {
+		listenType = listenImport
+	}
+
+	payload := make([]listen, 0, len(scrobbles))
+	for _, scrobble := range scrobbles {
+		entry := toListen(scrobble.Track)
+		entry.ListenedAt = scrobble.Timestamp.Unix()
+		payload = append(payload, entry)
+	}
+
+	return l.submit(submission{ListenType: listenType, Payload: payload})
+}
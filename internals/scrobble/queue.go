@@ -0,0 +1,87 @@
+package scrobble
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type Queue struct {
+	path string
+
+	mu      sync.Mutex
+	pending map[string][]Scrobble
+}
+
+func OpenQueue(path string) (*Queue, error) {
+	q := &Queue{path: path, pending: map[string][]Scrobble{}}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &q.pending); err != nil {
+		return nil, ErrCorruptQueue
+	}
+
+	return q, nil
+}
+
+func (q *Queue) save() error {
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o700); err != nil {
+		return err
+	}
+
+	raw, _ := json.MarshalIndent(q.pending, "", "  ")
+
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, q.path)
+}
+
+func (q *Queue) Add(backend string, scrobbles ...Scrobble) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending[backend] = append(q.pending[backend], scrobbles...)
+
+	return q.save()
+}
+
+func (q *Queue) Peek(backend string, n int) []Scrobble {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := q.pending[backend]
+
+	return append([]Scrobble{}, pending[:min(n, len(pending))]...)
+}
+
+func (q *Queue) Drop(backend string, n int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := q.pending[backend]
+	q.pending[backend] = pending[min(n, len(pending)):]
+	if len(q.pending[backend]) == 0 {
+		delete(q.pending, backend)
+	}
+
+	return q.save()
+}
+
+func (q *Queue) Len(backend string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending[backend])
+}
@@ -0,0 +1,48 @@
+package scrobble
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQueuePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "scrobbles.json")
+
+	q, err := OpenQueue(path)
+	if err != nil {
+		t.Fatalf("failed to open queue: %s", err)
+	}
+
+	at := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		q.Add("lastfm", Scrobble{Track: Track{Title: string(rune('a' + i))}, Timestamp: at})
+	}
+	q.Add("listenbrainz", Scrobble{Track: Track{Title: "z"}, Timestamp: at})
+
+	if err := q.Drop("lastfm", 1); err != nil {
+		t.Fatalf("failed to drop scrobbles: %s", err)
+	}
+
+	reopened, err := OpenQueue(path)
+	if err != nil {
+		t.Fatalf("failed to reopen queue: %s", err)
+	}
+
+	if reopened.Len("lastfm") != 2 || reopened.Len("listenbrainz") != 1 {
+		t.Fatalf("invalid lengths. got=%d %d, expected=2 1", reopened.Len("lastfm"), reopened.Len("listenbrainz"))
+	}
+
+	batch := reopened.Peek("lastfm", 5)
+	if len(batch) != 2 || batch[0].Title != "b" || !batch[0].Timestamp.Equal(at) {
+		t.Fatalf("invalid batch. got=%+v, expected=2 scrobbles starting at b", batch)
+	}
+
+	os.WriteFile(path, []byte("{"), 0o600)
+
+	if _, err := OpenQueue(path); !errors.Is(err, ErrCorruptQueue) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrCorruptQueue)
+	}
+}
@@ -0,0 +1,76 @@
+package scrobble
+
+import (
+	"errors"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const (
+	minDuration      = 30 * time.Second
+	maxScrobbleDelay = 4 * time.Minute
+	userAgent        = "spotify-tui"
+	requestTimeout   = 10 * time.Second
+)
+
+var (
+	ErrRejected      = errors.New("scrobble rejected")
+	ErrRequestFailed = errors.New("scrobble request failed")
+	ErrAuthFailed    = errors.New("scrobbler authentication failed")
+	ErrCorruptQueue  = errors.New("scrobble queue is corrupt")
+)
+
+type Track struct {
+	Artist     string `json:"artist"`
+	Title      string `json:"title"`
+	Album      string `json:"album"`
+	DurationMs int    `json:"duration_ms"`
+	Uri        string `json:"uri"`
+	Url        string `json:"url"`
+}
+
+func (t Track) duration() time.Duration {
+	return time.Duration(t.DurationMs) * time.Millisecond
+}
+
+func (t Track) threshold() time.Duration {
+	return min(t.duration()/2, maxScrobbleDelay)
+}
+
+func (t Track) Scrobblable() bool {
+	return t.Artist != "" && t.Title != "" && t.duration() > minDuration
+}
+
+type Scrobble struct {
+	Track
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type Scrobbler interface {
+	Name() string
+	NowPlaying(track Track) error
+	Scrobble(scrobbles []Scrobble) error
+}
+
+func FromSnapshot(snapshot playback.Snapshot) Track {
+	if !snapshot.Active {
+		return Track{}
+	}
+
+	item := snapshot.State.Item
+
+	artist := ""
+	if len(item.Artists) > 0 {
+		artist = item.Artists[0].Name
+	}
+
+	return Track{
+		Artist:     artist,
+		Title:      item.Name,
+		Album:      item.Album.Name,
+		DurationMs: item.DurationMs,
+		Uri:        item.Uri,
+		Url:        item.ExternalUrls.Spotify,
+	}
+}
@@ -0,0 +1,113 @@
+package scrobble
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const (
+	batchSize            = 50
+	DefaultRetryInterval = time.Minute
+)
+
+type Service struct {
+	backends []Scrobbler
+	queue    *Queue
+	tracker  Tracker
+	report   func(error)
+	now      func() time.Time
+}
+
+func NewService(backends []Scrobbler, queue *Queue, report func(error)) *Service {
+	if report == nil {
+		report = func(error) {}
+	}
+
+	return &Service{
+		backends: backends,
+		queue:    queue,
+		report:   report,
+		now:      time.Now,
+	}
+}
+
+func (s *Service) fail(backend Scrobbler, err error) {
+	s.report(fmt.Errorf("%s: %w", backend.Name(), err))
+}
+
+func (s *Service) Flush() {
+	for _, backend := range s.backends {
+		size := batchSize
+
+		for s.queue.Len(backend.Name()) > 0 {
+			batch := s.queue.Peek(backend.Name(), size)
+
+			err := backend.Scrobble(batch)
+			if errors.Is(err, ErrRejected) && len(batch) > 1 {
+				size = len(batch) / 2
+				continue
+			}
+
+			if err != nil {
+				s.fail(backend, err)
+			}
+
+			if err != nil && !errors.Is(err, ErrRejected) {
+				break
+			}
+
+			if err := s.queue.Drop(backend.Name(), len(batch)); err != nil {
+				s.fail(backend, err)
+				break
+			}
+		}
+	}
+}
+
+func (s *Service) Observe(snapshot playback.Snapshot) {
+	nowPlaying, scrobble := s.tracker.Observe(snapshot, s.now())
+
+	if nowPlaying != nil {
+		for _, backend := range s.backends {
+			if err := backend.NowPlaying(*nowPlaying); err != nil {
+				s.fail(backend, err)
+			}
+		}
+	}
+
+	if scrobble != nil {
+		for _, backend := range s.backends {
+			if err := s.queue.Add(backend.Name(), *scrobble); err != nil {
+				s.fail(backend, err)
+			}
+		}
+
+		s.Flush()
+	}
+}
+
+func (s *Service) Run(ctx context.Context, updates <-chan playback.Update, retry time.Duration) {
+	ticker := time.NewTicker(retry)
+	defer ticker.Stop()
+
+	s.Flush()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Flush()
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+
+			s.Observe(update.Current)
+		}
+	}
+}
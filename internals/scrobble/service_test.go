@@ -0,0 +1,120 @@
+package scrobble
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeScrobbler struct {
+	name       string
+	err        error
+	rejects    string
+	nowPlaying []Track
+	scrobbled  []Scrobble
+}
+
+func (f *fakeScrobbler) Name() string {
+	return f.name
+}
+
+func (f *fakeScrobbler) NowPlaying(track Track) error {
+	f.nowPlaying = append(f.nowPlaying, track)
+
+	return f.err
+}
+
+func (f *fakeScrobbler) Scrobble(scrobbles []Scrobble) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	for _, scrobble := range scrobbles {
+		if scrobble.Title == f.rejects {
+			return ErrRejected
+		}
+	}
+
+	f.scrobbled = append(f.scrobbled, scrobbles...)
+
+	return nil
+}
+
+func TestServiceQueuesWhileOffline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scrobbles.json")
+	queue, _ := OpenQueue(path)
+
+	online := &fakeScrobbler{name: "online"}
+	offline := &fakeScrobbler{name: "offline", err: ErrRequestFailed}
+
+	var reported []error
+	service := NewService([]Scrobbler{online, offline}, queue, func(err error) {
+		reported = append(reported, err)
+	})
+
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	for at := time.Duration(0); at <= 100*time.Second; at += 10 * time.Second {
+		now = now.Add(10 * time.Second)
+		service.Observe(snapshot("a", int(at.Milliseconds()), true))
+	}
+
+	if len(online.nowPlaying) != 1 || len(online.scrobbled) != 1 || queue.Len("online") != 0 {
+		t.Fatalf("invalid online backend. got=%+v, expected=one scrobble sent", online)
+	}
+
+	if queue.Len("offline") != 1 || len(reported) != 2 || !errors.Is(reported[1], ErrRequestFailed) {
+		t.Fatalf("invalid offline queue. got=%d (reported %v), expected=1", queue.Len("offline"), reported)
+	}
+
+	reopened, _ := OpenQueue(path)
+	offline.err = nil
+
+	NewService([]Scrobbler{offline}, reopened, nil).Flush()
+
+	if len(offline.scrobbled) != 1 || offline.scrobbled[0].Uri != "a" || reopened.Len("offline") != 0 {
+		t.Fatalf("invalid scrobbles. got=%+v, expected=the queued one sent", offline.scrobbled)
+	}
+}
+
+func TestServiceDropsOnlyRejected(t *testing.T) {
+	queue, _ := OpenQueue(filepath.Join(t.TempDir(), "scrobbles.json"))
+
+	invalid := sample
+	invalid.Title = "Invalid"
+	queue.Add("strict", sample, invalid, sample, sample)
+
+	strict := &fakeScrobbler{name: "strict", rejects: "Invalid"}
+
+	var reported []error
+	NewService([]Scrobbler{strict}, queue, func(err error) {
+		reported = append(reported, err)
+	}).Flush()
+
+	if queue.Len("strict") != 0 {
+		t.Fatalf("invalid queue length. got=%d, expected=0", queue.Len("strict"))
+	}
+
+	if len(strict.scrobbled) != 3 {
+		t.Fatalf("invalid scrobbles. got=%d, expected=3", len(strict.scrobbled))
+	}
+
+	if len(reported) != 1 || !errors.Is(reported[0], ErrRejected) {
+		t.Fatalf("invalid reported errors. got=%v, expected=one %v", reported, ErrRejected)
+	}
+}
+
+func TestServiceKeepsOnFailure(t *testing.T) {
+	queue, _ := OpenQueue(filepath.Join(t.TempDir(), "scrobbles.json"))
+	queue.Add("flaky", sample, sample)
+
+	flaky := &fakeScrobbler{name: "flaky", err: ErrRequestFailed}
+
+	NewService([]Scrobbler{flaky}, queue, nil).Flush()
+
+	if queue.Len("flaky") != 2 {
+		t.Fatalf("invalid queue length. got=%d, expected=2", queue.Len("flaky"))
+	}
+}
@@ -0,0 +1,65 @@
+package scrobble
+
+import (
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+type play struct {
+	track     Track
+	startedAt time.Time
+	played    time.Duration
+	announced bool
+	scrobbled bool
+}
+
+type Tracker struct {
+	current      *play
+	playing      bool
+	lastAt       time.Time
+	lastProgress int
+}
+
+func (t *Tracker) Observe(snapshot playback.Snapshot, now time.Time) (*Track, *Scrobble) {
+	track := FromSnapshot(snapshot)
+	progress := snapshot.State.ProgressMs
+
+	if t.current != nil && t.current.track.Uri == track.Uri && t.playing {
+		t.current.played += playback.Listened(t.lastProgress, t.lastAt, progress, now)
+	}
+
+	switch {
+	case track.Uri == "":
+		t.current = nil
+	case t.current == nil || t.current.track.Uri != track.Uri:
+		t.current = &play{
+			track:     track,
+			startedAt: now.Add(-time.Duration(progress) * time.Millisecond),
+		}
+	}
+
+	t.playing = snapshot.Active && snapshot.State.IsPlaying
+	t.lastAt, t.lastProgress = now, progress
+
+	if t.current == nil || !t.current.track.Scrobblable() {
+		return nil, nil
+	}
+
+	var nowPlaying *Track
+	var scrobble *Scrobble
+
+	if !t.playing {
+		t.current.announced = false
+	} else if !t.current.announced {
+		t.current.announced = true
+		nowPlaying = &t.current.track
+	}
+
+	if !t.current.scrobbled && t.current.played >= t.current.track.threshold() {
+		t.current.scrobbled = true
+		scrobble = &Scrobble{Track: t.current.track, Timestamp: t.current.startedAt}
+	}
+
+	return nowPlaying, scrobble
+}
@@ -0,0 +1,162 @@
+package scrobble
+
+import (
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const trackMs = 200_000
+
+func snapshot(uri string, progressMs int, playing bool) playback.Snapshot {
+	return playback.Snapshot{
+		Active: true,
+		State: api.PlaybackState{
+			IsPlaying:  playing,
+			ProgressMs: progressMs,
+			Item: api.Track{
+				Uri:        uri,
+				Name:       "Song " + uri,
+				DurationMs: trackMs,
+				Artists:    []api.Artist{{Name: "Band"}, {Name: "Guest"}},
+				Album:      api.Album{Name: "Album"},
+			},
+		},
+	}
+}
+
+type step struct {
+	at       time.Duration
+	snapshot playback.Snapshot
+}
+
+func replay(steps []step) (nowPlaying []Track, scrobbles []Scrobble, start time.Time) {
+	start = time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+
+	var tracker Tracker
+	for _, s := range steps {
+		np, scrobble := tracker.Observe(s.snapshot, start.Add(s.at))
+		if np != nil {
+			nowPlaying = append(nowPlaying, *np)
+		}
+		if scrobble != nil {
+			scrobbles = append(scrobbles, *scrobble)
+		}
+	}
+
+	return nowPlaying, scrobbles, start
+}
+
+func playSteps(uri string, from, to time.Duration, offsetMs int) []step {
+	steps := []step{}
+	for at := from; at <= to; at += 10 * time.Second {
+		steps = append(steps, step{at, snapshot(uri, offsetMs+int((at-from).Milliseconds()), true)})
+	}
+
+	return steps
+}
+
+func TestScrobbleAfterHalf(t *testing.T) {
+	nowPlaying, scrobbles, start := replay(playSteps("a", 0, 90*time.Second, 5000))
+
+	if len(nowPlaying) != 1 || nowPlaying[0].Artist != "Band" || nowPlaying[0].Album != "Album" {
+		t.Fatalf("invalid now playing. got=%+v, expected=one update with artist and album", nowPlaying)
+	}
+
+	if len(scrobbles) != 0 {
+		t.Fatalf("invalid scrobbles before half of the track. got=%+v, expected=[]", scrobbles)
+	}
+
+	_, scrobbles, _ = replay(playSteps("a", 0, 100*time.Second, 5000))
+
+	if len(scrobbles) != 1 {
+		t.Fatalf("invalid scrobbles. got=%+v, expected=1", scrobbles)
+	}
+
+	if expected := start.Add(-5 * time.Second); !scrobbles[0].Timestamp.Equal(expected) {
+		t.Fatalf("invalid timestamp. got=%s, expected=%s", scrobbles[0].Timestamp, expected)
+	}
+}
+
+func TestScrobbleAfterFourMinutes(t *testing.T) {
+	var tracker Tracker
+	start := time.Now()
+
+	long := snapshot("a", 0, true)
+	long.State.Item.DurationMs = 20 * 60 * 1000
+
+	for at := time.Duration(0); at < 4*time.Minute; at += 10 * time.Second {
+		long.State.ProgressMs = int(at.Milliseconds())
+		if _, scrobble := tracker.Observe(long, start.Add(at)); scrobble != nil {
+			t.Fatalf("invalid scrobble after %s. expected=none before 4 minutes", at)
+		}
+	}
+
+	long.State.ProgressMs = int((4 * time.Minute).Milliseconds())
+	if _, scrobble := tracker.Observe(long, start.Add(4*time.Minute)); scrobble == nil {
+		t.Fatal("missing scrobble after 4 minutes")
+	}
+}
+
+func TestSeeksDontCount(t *testing.T) {
+	steps := []step{
+		{0, snapshot("a", 0, true)},
+		{10 * time.Second, snapshot("a", 10_000, true)},
+		{20 * time.Second, snapshot("a", 180_000, true)},
+		{30 * time.Second, snapshot("a", 190_000, true)},
+	}
+
+	if _, scrobbles, _ := replay(steps); len(scrobbles) != 0 {
+		t.Fatalf("invalid scrobbles after seeking forward. got=%+v, expected=[]", scrobbles)
+	}
+}
+
+func TestPausesDontCount(t *testing.T) {
+	steps := []step{
+		{0, snapshot("a", 0, true)},
+		{10 * time.Second, snapshot("a", 10_000, false)},
+		{200 * time.Second, snapshot("a", 10_000, true)},
+		{210 * time.Second, snapshot("a", 20_000, true)},
+	}
+
+	nowPlaying, scrobbles, _ := replay(steps)
+	if len(scrobbles) != 0 {
+		t.Fatalf("invalid scrobbles with paused time. got=%+v, expected=[]", scrobbles)
+	}
+
+	if len(nowPlaying) != 2 {
+		t.Fatalf("invalid now playing updates. got=%d, expected=2", len(nowPlaying))
+	}
+}
+
+func TestRepeatsScrobbleOnce(t *testing.T) {
+	steps := playSteps("a", 0, 190*time.Second, 0)
+	steps = append(steps, playSteps("a", 200*time.Second, 390*time.Second, 0)...)
+
+	if _, scrobbles, _ := replay(steps); len(scrobbles) != 1 {
+		t.Fatalf("invalid scrobbles of a repeated track. got=%d, expected=1", len(scrobbles))
+	}
+
+	steps = append(steps, playSteps("b", 400*time.Second, 500*time.Second, 0)...)
+
+	if _, scrobbles, _ := replay(steps); len(scrobbles) != 2 || scrobbles[1].Uri != "b" {
+		t.Fatalf("invalid scrobbles. got=%+v, expected=b scrobbled second", scrobbles)
+	}
+}
+
+func TestShortTracksIgnored(t *testing.T) {
+	short := snapshot("a", 0, true)
+	short.State.Item.DurationMs = 25_000
+
+	var tracker Tracker
+	start := time.Now()
+
+	for at := time.Duration(0); at <= 25*time.Second; at += 5 * time.Second {
+		short.State.ProgressMs = int(at.Milliseconds())
+		if nowPlaying, scrobble := tracker.Observe(short, start.Add(at)); nowPlaying != nil || scrobble != nil {
+			t.Fatal("tracks under 30 seconds shouldn't be reported")
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package smart
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const defaultDescription = "Smart playlist kept up to date by spotify-tui"
+
+var ErrCorruptState = errors.New("corrupt smart playlists file")
+
+type Entry struct {
+	PlaylistId  string    `json:"playlist_id"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	Tracks      int       `json:"tracks"`
+	FailedAt    time.Time `json:"failed_at"`
+	Failures    int       `json:"failures,omitempty"`
+}
+
+type State struct {
+	path string
+
+	mu sync.Mutex
+}
+
+func NewState(path string) *State {
+	return &State{path: path}
+}
+
+func (s *State) load() (map[string]Entry, error) {
+	entries := map[string]Entry{}
+
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, ErrCorruptState
+	}
+
+	return entries, nil
+}
+
+func (s *State) Entries() (map[string]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+func (s *State) Update(name string, entry Entry) error {
+	return s.change(name, func(Entry) Entry { return entry })
+}
+
+func (s *State) Fail(name string, at time.Time) error {
+	return s.change(name, func(entry Entry) Entry {
+		entry.FailedAt = at
+		entry.Failures++
+
+		return entry
+	})
+}
+
+func (s *State) change(name string, apply func(Entry) Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	entries[name] = apply(entries[name])
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	raw, _ := json.MarshalIndent(entries, "", "  ")
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.path)
+}
+
+type Result struct {
+	Playlist api.Playlist
+	Tracks   []api.PlaylistItem
+	Created  bool
+}
+
+func (d Definition) description() string {
+	if d.Description == "" {
+		return defaultDescription
+	}
+
+	return d.Description
+}
+
+func (e *Evaluator) target(definition Definition, entry Entry) (api.Playlist, bool, error) {
+	if e.owned == nil {
+		profile, err := e.library.GetUserProfile()
+		if err != nil {
+			return api.Playlist{}, false, err
+		}
+
+		owned, err := e.library.GetMyPlaylists()
+		if err != nil {
+			return api.Playlist{}, false, err
+		}
+
+		e.profile, e.owned = profile, owned
+	}
+
+	for _, playlist := range e.owned {
+		if entry.PlaylistId != "" && playlist.Id == entry.PlaylistId {
+			return playlist, false, nil
+		}
+	}
+
+	for _, playlist := range e.owned {
+		if playlist.Owner.Id == e.profile.Id && playlist.Name == definition.Name &&
+			playlist.Description == definition.description() {
+			return playlist, false, nil
+		}
+	}
+
+	playlist, err := e.library.CreatePlaylist(e.profile.Id, api.PlaylistDetails{
+		Name:        definition.Name,
+		Description: definition.description(),
+		Public:      definition.Public,
+	})
+	if err != nil {
+		return api.Playlist{}, false, err
+	}
+
+	e.owned = append(e.owned, playlist)
+
+	return playlist, true, nil
+}
+
+func (e *Evaluator) Refresh(definition Definition, state *State) (Result, error) {
+	tracks, err := e.Tracks(definition)
+	if err != nil {
+		return Result{}, err
+	}
+
+	entries, err := state.Entries()
+	if err != nil {
+		return Result{}, err
+	}
+
+	playlist, created, err := e.target(definition, entries[definition.Name])
+	if err != nil {
+		return Result{}, err
+	}
+
+	uris := make([]string, 0, len(tracks))
+	for _, item := range tracks {
+		uris = append(uris, item.Track.Uri)
+	}
+
+	if _, err := e.library.ReplacePlaylistTracks(playlist.Id, uris); err != nil {
+		return Result{}, err
+	}
+
+	entry := Entry{PlaylistId: playlist.Id, RefreshedAt: e.now, Tracks: len(uris)}
+	if err := state.Update(definition.Name, entry); err != nil {
+		return Result{}, err
+	}
+
+	return Result{Playlist: playlist, Tracks: tracks, Created: created}, nil
+}
@@ -0,0 +1,448 @@
+package smart
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/history"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+)
+
+const (
+	topLimit    = 50
+	recentLimit = 50
+	retryDelay  = 2 * time.Minute
+)
+
+type Source string
+
+const (
+	SourceLiked    Source = "liked"
+	SourceTop      Source = "top_tracks"
+	SourceReleases Source = "followed_artists"
+	SourcePlayed   Source = "played"
+	SourcePlaylist Source = "playlist"
+)
+
+var Sources = []Source{SourceLiked, SourceTop, SourceReleases, SourcePlayed, SourcePlaylist}
+
+var (
+	ErrUnknownSource = errors.New("unknown rule source")
+	ErrInvalidRule   = errors.New("invalid rule")
+	ErrNoName        = errors.New("smart playlist without a name")
+)
+
+type Rule struct {
+	Source    Source
+	Days      int
+	TimeRange api.TimeRange
+	Playlist  string
+}
+
+func (r Rule) String() string {
+	switch r.Source {
+	case SourceTop:
+		return fmt.Sprintf("%s %s", r.Source, r.timeRange())
+	case SourcePlaylist:
+		return fmt.Sprintf("%s %s", r.Source, r.Playlist)
+	}
+
+	if r.Days > 0 {
+		return fmt.Sprintf("%s %dd", r.Source, r.Days)
+	}
+
+	return string(r.Source)
+}
+
+func (r Rule) timeRange() api.TimeRange {
+	if r.TimeRange == "" {
+		return api.MediumTerm
+	}
+
+	return r.TimeRange
+}
+
+func (r Rule) Validate() error {
+	switch {
+	case !slices.Contains(Sources, r.Source):
+		return fmt.Errorf("%w: %s", ErrUnknownSource, r.Source)
+	case r.Days < 0:
+		return fmt.Errorf("%w: %s with negative days", ErrInvalidRule, r.Source)
+	case r.Source == SourceTop && !slices.Contains(api.TimeRanges, r.timeRange()):
+		return fmt.Errorf("%w: unknown time range %s", ErrInvalidRule, r.TimeRange)
+	case r.Source == SourcePlaylist && playlistId(r.Playlist) == "":
+		return fmt.Errorf("%w: playlist rule without a playlist", ErrInvalidRule)
+	}
+
+	return nil
+}
+
+type Definition struct {
+	Name           string
+	Description    string
+	Public         bool
+	Include        []Rule
+	Exclude        []Rule
+	Released       string
+	NoExplicit     bool
+	ExcludeArtists []string
+	Sort           playlists.SortKey
+	Descending     bool
+	Limit          int
+	Refresh        time.Duration
+}
+
+func (d Definition) Validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return ErrNoName
+	}
+
+	if len(d.Include) == 0 {
+		return fmt.Errorf("%w: %s includes nothing", ErrInvalidRule, d.Name)
+	}
+
+	for _, rule := range slices.Concat(d.Include, d.Exclude) {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", d.Name, err)
+		}
+	}
+
+	if _, _, err := releasedYears(d.Released, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", d.Name, err)
+	}
+
+	if d.Sort != "" {
+		if _, err := playlists.ParseSortKey(string(d.Sort)); err != nil {
+			return fmt.Errorf("%s: %w", d.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (d Definition) retryDelay(failures int) time.Duration {
+	return min(retryDelay<<min(failures-1, 10), d.Refresh)
+}
+
+func (d Definition) Due(entry Entry, now time.Time) bool {
+	if d.Refresh <= 0 {
+		return false
+	}
+
+	if entry.Failures > 0 {
+		return now.Sub(entry.FailedAt) >= d.retryDelay(entry.Failures)
+	}
+
+	return now.Sub(entry.RefreshedAt) >= d.Refresh
+}
+
+func releasedYears(value string, now time.Time) (int, int, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "this-year":
+		return now.Year(), now.Year(), nil
+	case "last-year":
+		return now.Year() - 1, now.Year() - 1, nil
+	}
+
+	return playlists.ParseYears(value)
+}
+
+func playlistId(value string) string {
+	value = strings.TrimSpace(value)
+
+	if rest, found := strings.CutPrefix(value, "https://open.spotify.com/playlist/"); found {
+		value, _, _ = strings.Cut(rest, "?")
+	}
+
+	return strings.TrimPrefix(value, "spotify:playlist:")
+}
+
+type Library interface {
+	GetUserProfile() (api.UserProfile, error)
+	GetSavedTracks() ([]api.SavedTrack, error)
+	GetTopTracks(timeRange api.TimeRange, limit int) ([]api.Track, error)
+	GetFollowedArtists() ([]api.Artist, error)
+	GetArtistAlbums(artistId string) ([]api.Album, error)
+	GetAlbumTracks(albumId string) ([]api.Track, error)
+	GetRecentlyPlayed(cursors api.Cursors, limit int) (api.RecentlyPlayed, error)
+	GetMyPlaylists() ([]api.Playlist, error)
+	GetPlaylistItems(playlistId string) ([]api.PlaylistItem, error)
+	CreatePlaylist(userId string, details api.PlaylistDetails) (api.Playlist, error)
+	ReplacePlaylistTracks(playlistId string, uris []string) (string, error)
+}
+
+type PlayLog interface {
+	Played(since time.Time) ([]string, error)
+}
+
+type historyLog struct {
+	store *history.Store
+}
+
+func NewHistoryLog(store *history.Store) PlayLog {
+	return historyLog{store}
+}
+
+func (h historyLog) Played(since time.Time) ([]string, error) {
+	plays, err := h.store.Plays(since, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	uris := make([]string, 0, len(plays))
+	for _, play := range plays {
+		uris = append(uris, play.Uri)
+	}
+
+	return uris, nil
+}
+
+type Evaluator struct {
+	library Library
+	plays   PlayLog
+	now     time.Time
+	cache   map[string][]api.PlaylistItem
+	albums  map[string][]api.Album
+	owned   []api.Playlist
+	profile api.UserProfile
+}
+
+func NewEvaluator(library Library, plays PlayLog, now time.Time) *Evaluator {
+	return &Evaluator{
+		library: library,
+		plays:   plays,
+		now:     now,
+		cache:   map[string][]api.PlaylistItem{},
+		albums:  map[string][]api.Album{},
+	}
+}
+
+func (e *Evaluator) since(days int) time.Time {
+	if days == 0 {
+		return time.Time{}
+	}
+
+	return e.now.AddDate(0, 0, -days)
+}
+
+func asItems(tracks []api.Track) []api.PlaylistItem {
+	items := make([]api.PlaylistItem, 0, len(tracks))
+	for _, track := range tracks {
+		items = append(items, api.PlaylistItem{Track: track})
+	}
+
+	return items
+}
+
+func (e *Evaluator) liked(rule Rule) ([]api.PlaylistItem, error) {
+	saved, err := e.library.GetSavedTracks()
+	if err != nil {
+		return nil, err
+	}
+
+	since := e.since(rule.Days)
+
+	items := []api.PlaylistItem{}
+	for _, track := range saved {
+		if !track.AddedAt.Before(since) {
+			items = append(items, api.PlaylistItem{AddedAt: track.AddedAt, Track: track.Track})
+		}
+	}
+
+	return items, nil
+}
+
+func (e *Evaluator) artistAlbums(artistId string) ([]api.Album, error) {
+	if albums, ok := e.albums[artistId]; ok {
+		return albums, nil
+	}
+
+	albums, err := e.library.GetArtistAlbums(artistId)
+	if err != nil {
+		return nil, err
+	}
+
+	e.albums[artistId] = albums
+
+	return albums, nil
+}
+
+func (e *Evaluator) releases(rule Rule, from, to int) ([]api.PlaylistItem, error) {
+	artists, err := e.library.GetFollowedArtists()
+	if err != nil {
+		return nil, err
+	}
+
+	since := e.since(rule.Days).Format(time.DateOnly)
+
+	seen := map[string]bool{}
+	albums := []api.Album{}
+	for _, artist := range artists {
+		released, err := e.artistAlbums(artist.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, album := range released {
+			year, _ := strconv.Atoi(strings.SplitN(album.ReleaseDate, "-", 2)[0])
+
+			switch {
+			case seen[album.Id]:
+			case rule.Days > 0 && album.ReleaseDate < since:
+			case from != 0 && year < from, to != 0 && year > to:
+			default:
+				seen[album.Id] = true
+				albums = append(albums, album)
+			}
+		}
+	}
+
+	slices.SortStableFunc(albums, func(a, b api.Album) int {
+		return cmp.Compare(b.ReleaseDate, a.ReleaseDate)
+	})
+
+	items := []api.PlaylistItem{}
+	for _, album := range albums {
+		tracks, err := e.library.GetAlbumTracks(album.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, track := range tracks {
+			track.Album = album
+			items = append(items, api.PlaylistItem{Track: track})
+		}
+	}
+
+	return items, nil
+}
+
+func (e *Evaluator) played(rule Rule) ([]api.PlaylistItem, error) {
+	since := e.since(rule.Days)
+
+	cursors := api.Cursors{}
+	if !since.IsZero() {
+		cursors.After = strconv.FormatInt(since.UnixMilli(), 10)
+	}
+
+	recent, err := e.library.GetRecentlyPlayed(cursors, recentLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []api.PlaylistItem{}
+	for _, play := range recent.Items {
+		items = append(items, api.PlaylistItem{AddedAt: play.PlayedAt, Track: play.Track})
+	}
+
+	if e.plays == nil {
+		return items, nil
+	}
+
+	uris, err := e.plays.Played(since)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, uri := range uris {
+		items = append(items, api.PlaylistItem{Track: api.Track{Uri: uri}})
+	}
+
+	return items, nil
+}
+
+func (e *Evaluator) fetch(rule Rule, from, to int) ([]api.PlaylistItem, error) {
+	key := rule.String()
+	if rule.Source == SourceReleases {
+		key = fmt.Sprintf("%s %d-%d", key, from, to)
+	}
+
+	if items, ok := e.cache[key]; ok {
+		return items, nil
+	}
+
+	var items []api.PlaylistItem
+	var err error
+
+	switch rule.Source {
+	case SourceLiked:
+		items, err = e.liked(rule)
+	case SourceTop:
+		var tracks []api.Track
+		tracks, err = e.library.GetTopTracks(rule.timeRange(), topLimit)
+		items = asItems(tracks)
+	case SourceReleases:
+		items, err = e.releases(rule, from, to)
+	case SourcePlayed:
+		items, err = e.played(rule)
+	case SourcePlaylist:
+		items, err = e.library.GetPlaylistItems(playlistId(rule.Playlist))
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnknownSource, rule.Source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e.cache[key] = items
+
+	return items, nil
+}
+
+func (e *Evaluator) Tracks(definition Definition) ([]api.PlaylistItem, error) {
+	from, to, err := releasedYears(definition.Released, e.now)
+	if err != nil {
+		return nil, err
+	}
+
+	included := []api.PlaylistItem{}
+	for _, rule := range definition.Include {
+		items, err := e.fetch(rule, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		included = append(included, items...)
+	}
+
+	excluded := map[string]bool{}
+	for _, rule := range definition.Exclude {
+		items, err := e.fetch(rule, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			excluded[item.Track.Uri] = true
+		}
+	}
+
+	filter := playlists.Filter{
+		FromYear:        from,
+		ToYear:          to,
+		ExcludeArtists:  definition.ExcludeArtists,
+		ExcludeExplicit: definition.NoExplicit,
+	}
+
+	tracks := slices.DeleteFunc(playlists.Dedupe(included, playlists.DedupeOptions{}), func(item api.PlaylistItem) bool {
+		uri := item.Track.Uri
+
+		return excluded[uri] || uri == "" || strings.HasPrefix(uri, "spotify:local:") || !filter.Keep(item.Track)
+	})
+
+	if definition.Sort != "" {
+		tracks = playlists.Sort(tracks, definition.Sort, definition.Descending)
+	}
+
+	if definition.Limit > 0 && len(tracks) > definition.Limit {
+		tracks = tracks[:definition.Limit]
+	}
+
+	return tracks, nil
+}
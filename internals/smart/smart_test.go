@@ -0,0 +1,245 @@
+package smart
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/apitest"
+)
+
+var now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+type playLog []string
+
+func (p playLog) Played(since time.Time) ([]string, error) {
+	return p, nil
+}
+
+func track(id string) api.Track {
+	return api.Track{Id: id, Uri: "spotify:track:" + id, Name: id, Artists: []api.Artist{{Name: "Artist " + id}}}
+}
+
+func ids(items []api.PlaylistItem) []string {
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.Track.Id)
+	}
+
+	return ids
+}
+
+func TestLikedInLastDays(t *testing.T) {
+	library := &apitest.FakeLibrary{Tracks: []api.SavedTrack{
+		{AddedAt: now.AddDate(0, 0, -2), Track: track("new")},
+		{AddedAt: now.AddDate(0, 0, -29), Track: track("recent")},
+		{AddedAt: now.AddDate(0, 0, -31), Track: track("old")},
+	}}
+
+	definition := Definition{Name: "Fresh", Include: []Rule{{Source: SourceLiked, Days: 30}}}
+
+	tracks, err := NewEvaluator(library, nil, now).Tracks(definition)
+	if err != nil {
+		t.Fatalf("failed to evaluate tracks: %s", err)
+	}
+
+	if got := ids(tracks); !slices.Equal(got, []string{"new", "recent"}) {
+		t.Fatalf("invalid tracks. got=%v, expected=[new recent]", got)
+	}
+}
+
+func TestFollowedReleasesThisYear(t *testing.T) {
+	explicit := track("explicit")
+	explicit.Explicit = true
+
+	library := &apitest.FakeLibrary{
+		Artists: []api.Artist{{Id: "a1"}, {Id: "a2"}},
+		Releases: map[string][]api.Album{
+			"a1": {{Id: "old", ReleaseDate: "2019-03-01"}, {Id: "duet", ReleaseDate: "2026-02-01"}},
+			"a2": {{Id: "duet", ReleaseDate: "2026-02-01"}, {Id: "single", ReleaseDate: "2026-09-12"}},
+		},
+		Album: map[string][]api.Track{
+			"old":    {track("classic")},
+			"duet":   {track("together"), explicit},
+			"single": {track("hit")},
+		},
+	}
+
+	definition := Definition{
+		Name:       "New from followed",
+		Include:    []Rule{{Source: SourceReleases}},
+		Released:   "this-year",
+		NoExplicit: true,
+	}
+
+	tracks, err := NewEvaluator(library, nil, now).Tracks(definition)
+	if err != nil {
+		t.Fatalf("failed to evaluate tracks: %s", err)
+	}
+
+	if got := ids(tracks); !slices.Equal(got, []string{"hit", "together"}) {
+		t.Fatalf("invalid tracks. got=%v, expected=[hit together]", got)
+	}
+
+	if tracks[0].Track.Album.Id != "single" {
+		t.Fatalf("invalid album. got=%+v, expected=single", tracks[0].Track.Album)
+	}
+
+	if library.Count("GetAlbumTracks") != 2 {
+		t.Fatalf("invalid album fetches. got=%v, expected=this year's albums once", library.Calls())
+	}
+}
+
+func TestTopMinusRecentlyPlayed(t *testing.T) {
+	library := &apitest.FakeLibrary{
+		Top: map[api.TimeRange][]api.Track{
+			api.MediumTerm: {track("a"), track("b"), track("c"), track("d"), track("e")},
+		},
+		Recent: []api.PlayHistory{
+			{Track: track("b"), PlayedAt: now.AddDate(0, 0, -3)},
+			{Track: track("c"), PlayedAt: now.AddDate(0, 0, -10)},
+		},
+	}
+
+	definition := Definition{
+		Name:    "Rotation",
+		Include: []Rule{{Source: SourceTop, TimeRange: api.MediumTerm}},
+		Exclude: []Rule{{Source: SourcePlayed, Days: 7}},
+	}
+
+	tracks, err := NewEvaluator(library, playLog{"spotify:track:d"}, now).Tracks(definition)
+	if err != nil {
+		t.Fatalf("failed to evaluate tracks: %s", err)
+	}
+
+	if got := ids(tracks); !slices.Equal(got, []string{"a", "c", "e"}) {
+		t.Fatalf("invalid tracks. got=%v, expected=[a c e]", got)
+	}
+
+	definition.Sort = "artist"
+	definition.Descending = true
+	definition.Limit = 2
+
+	tracks, _ = NewEvaluator(library, playLog{"spotify:track:d"}, now).Tracks(definition)
+	if got := ids(tracks); !slices.Equal(got, []string{"e", "c"}) {
+		t.Fatalf("invalid sorted tracks. got=%v, expected=[e c]", got)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	library := &apitest.FakeLibrary{
+		Profile: api.UserProfile{Id: "me"},
+		Top:     map[api.TimeRange][]api.Track{api.ShortTerm: {track("a"), track("b")}},
+	}
+
+	state := NewState(filepath.Join(t.TempDir(), "smart.json"))
+	definition := Definition{Name: "Now", Include: []Rule{{Source: SourceTop, TimeRange: api.ShortTerm}}}
+
+	result, err := NewEvaluator(library, nil, now).Refresh(definition, state)
+	if err != nil {
+		t.Fatalf("failed to refresh: %s", err)
+	}
+
+	if !result.Created || !slices.Equal(library.Uris(result.Playlist.Id), []string{"spotify:track:a", "spotify:track:b"}) {
+		t.Fatalf("invalid result. got=%+v, expected=a new playlist with a and b", result)
+	}
+
+	library.Top[api.ShortTerm] = []api.Track{track("c")}
+
+	again, err := NewEvaluator(library, nil, now.Add(time.Hour)).Refresh(definition, state)
+	if err != nil {
+		t.Fatalf("failed to refresh again: %s", err)
+	}
+
+	if again.Created || again.Playlist.Id != result.Playlist.Id || library.Count("CreatePlaylist") != 1 {
+		t.Fatalf("invalid result. got=%+v after %v, expected=the playlist reused", again, library.Calls())
+	}
+
+	if got := library.Uris(result.Playlist.Id); !slices.Equal(got, []string{"spotify:track:c"}) {
+		t.Fatalf("invalid tracks. got=%v, expected=[spotify:track:c]", got)
+	}
+
+	entries, _ := state.Entries()
+	if entry := entries["Now"]; entry.Tracks != 1 || !entry.RefreshedAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("invalid entry. got=%+v, expected=1 track refreshed at %s", entry, now.Add(time.Hour))
+	}
+
+	lost := NewState(filepath.Join(t.TempDir(), "smart.json"))
+	if _, err := NewEvaluator(library, nil, now).Refresh(definition, lost); err != nil {
+		t.Fatalf("failed to refresh without state: %s", err)
+	}
+
+	if count := library.Count("CreatePlaylist"); count != 1 {
+		t.Fatalf("invalid playlists created. got=%d, expected=1 found by name", count)
+	}
+}
+
+func TestDue(t *testing.T) {
+	definition := Definition{Refresh: time.Hour}
+
+	if !definition.Due(Entry{}, now) || definition.Due(Entry{RefreshedAt: now.Add(-time.Minute)}, now) {
+		t.Fatal("invalid due state. expected=due only once the refresh interval elapsed")
+	}
+
+	if (Definition{}).Due(Entry{}, now) {
+		t.Fatal("invalid due state. expected=never due without a schedule")
+	}
+}
+
+func TestDueAfterFailure(t *testing.T) {
+	definition := Definition{Refresh: time.Hour}
+
+	state := NewState(filepath.Join(t.TempDir(), "smart.json"))
+	if err := state.Update("Now", Entry{PlaylistId: "p1", RefreshedAt: now.Add(-2 * time.Hour)}); err != nil {
+		t.Fatalf("failed to update state: %s", err)
+	}
+
+	delays := []time.Duration{retryDelay, 2 * retryDelay, 4 * retryDelay}
+	for i, delay := range delays {
+		if err := state.Fail("Now", now); err != nil {
+			t.Fatalf("failed to record failure: %s", err)
+		}
+
+		entries, _ := state.Entries()
+		entry := entries["Now"]
+
+		if entry.Failures != i+1 || entry.PlaylistId != "p1" {
+			t.Fatalf("invalid entry. got=%+v, expected=%d failures of p1", entry, i+1)
+		}
+
+		if definition.Due(entry, now.Add(delay-time.Second)) || !definition.Due(entry, now.Add(delay)) {
+			t.Fatalf("invalid retry delay after %d failures. expected=%s", i+1, delay)
+		}
+	}
+
+	for range 10 {
+		state.Fail("Now", now)
+	}
+
+	entries, _ := state.Entries()
+	if definition.Due(entries["Now"], now.Add(definition.Refresh-time.Second)) || !definition.Due(entries["Now"], now.Add(definition.Refresh)) {
+		t.Fatalf("invalid retry delay. expected=%s at most", definition.Refresh)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[error]Definition{
+		ErrNoName:        {Include: []Rule{{Source: SourceLiked}}},
+		ErrUnknownSource: {Name: "x", Include: []Rule{{Source: "saved"}}},
+		ErrInvalidRule:   {Name: "x", Include: []Rule{{Source: SourceTop, TimeRange: "forever"}}},
+	}
+
+	for expected, definition := range cases {
+		if err := definition.Validate(); !errors.Is(err, expected) {
+			t.Fatalf("invalid error. got=%v, expected=%v", err, expected)
+		}
+	}
+
+	valid := Definition{Name: "x", Include: []Rule{{Source: SourcePlaylist, Playlist: "spotify:playlist:p1"}}, Released: "2001-2003"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("failed to validate: %s", err)
+	}
+}
@@ -0,0 +1,246 @@
+package snapshots
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const historyLimit = 50
+
+var (
+	ErrCorruptHistory = errors.New("corrupt snapshot history")
+	ErrInvalidId      = errors.New("invalid playlist id")
+)
+
+type Track struct {
+	Uri     string    `json:"uri"`
+	Name    string    `json:"name"`
+	Artist  string    `json:"artist,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+	AddedBy string    `json:"added_by,omitempty"`
+}
+
+func (t Track) String() string {
+	if t.Artist == "" {
+		return t.Name
+	}
+
+	return t.Name + " - " + t.Artist
+}
+
+type Snapshot struct {
+	Id     string    `json:"snapshot_id"`
+	At     time.Time `json:"at"`
+	Name   string    `json:"name"`
+	Tracks []Track   `json:"tracks"`
+}
+
+func (s Snapshot) Uris() []string {
+	uris := make([]string, 0, len(s.Tracks))
+	for _, track := range s.Tracks {
+		uris = append(uris, track.Uri)
+	}
+
+	return uris
+}
+
+func FromItems(playlist api.Playlist, items []api.PlaylistItem, at time.Time) Snapshot {
+	snapshot := Snapshot{
+		Id:     playlist.SnapshotId,
+		At:     at.UTC(),
+		Name:   playlist.Name,
+		Tracks: make([]Track, 0, len(items)),
+	}
+
+	for _, item := range items {
+		track := Track{
+			Uri:     item.Track.Uri,
+			Name:    item.Track.Name,
+			AddedAt: item.AddedAt,
+			AddedBy: item.AddedBy.Id,
+		}
+		if len(item.Track.Artists) > 0 {
+			track.Artist = item.Track.Artists[0].Name
+		}
+		snapshot.Tracks = append(snapshot.Tracks, track)
+	}
+
+	return snapshot
+}
+
+type Change struct {
+	Track    Track
+	Added    bool
+	Position int
+}
+
+func Diff(from, to Snapshot) []Change {
+	remaining := map[string]int{}
+	for _, track := range to.Tracks {
+		remaining[track.Uri]++
+	}
+
+	changes := []Change{}
+	for i, track := range from.Tracks {
+		if remaining[track.Uri] > 0 {
+			remaining[track.Uri]--
+			continue
+		}
+		changes = append(changes, Change{Track: track, Position: i})
+	}
+
+	kept := map[string]int{}
+	for _, track := range from.Tracks {
+		kept[track.Uri]++
+	}
+
+	for i, track := range to.Tracks {
+		if kept[track.Uri] > 0 {
+			kept[track.Uri]--
+			continue
+		}
+		changes = append(changes, Change{Track: track, Added: true, Position: i})
+	}
+
+	return changes
+}
+
+type Store struct {
+	dir string
+
+	mu sync.Mutex
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) path(playlistId string) (string, error) {
+	if playlistId == "" || strings.ContainsAny(playlistId, `/\.`) {
+		return "", ErrInvalidId
+	}
+
+	return filepath.Join(s.dir, playlistId+".json"), nil
+}
+
+func (s *Store) load(path string) ([]Snapshot, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	history := []Snapshot{}
+	if err := json.Unmarshal(raw, &history); err != nil {
+		return nil, ErrCorruptHistory
+	}
+
+	return history, nil
+}
+
+func (s *Store) Record(playlistId string, snapshot Snapshot) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(playlistId)
+	if err != nil {
+		return false, err
+	}
+
+	history, err := s.load(path)
+	if err != nil {
+		return false, err
+	}
+
+	if len(history) > 0 && history[len(history)-1].Id == snapshot.Id {
+		return false, nil
+	}
+
+	history = append(history, snapshot)
+	if len(history) > historyLimit {
+		history = history[len(history)-historyLimit:]
+	}
+
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return false, err
+	}
+
+	raw, _ := json.Marshal(history)
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return false, err
+	}
+
+	return true, os.Rename(tmp, path)
+}
+
+func (s *Store) History(playlistId string) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(playlistId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.load(path)
+}
+
+type Source interface {
+	GetPlaylistItems(playlistId string) ([]api.PlaylistItem, error)
+}
+
+func (s *Store) latest(playlistId string) (string, error) {
+	history, err := s.History(playlistId)
+	if err != nil || len(history) == 0 {
+		return "", err
+	}
+
+	return history[len(history)-1].Id, nil
+}
+
+func (s *Store) RecordAll(source Source, playlists []api.Playlist, at time.Time) (int, error) {
+	recorded := 0
+	errs := []error{}
+
+	for _, playlist := range playlists {
+		latest, err := s.latest(playlist.Id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s", err, playlist.Name))
+			continue
+		}
+
+		if playlist.SnapshotId != "" && latest == playlist.SnapshotId {
+			continue
+		}
+
+		items, err := source.GetPlaylistItems(playlist.Id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s", err, playlist.Name))
+			continue
+		}
+
+		added, err := s.Record(playlist.Id, FromItems(playlist, items, at))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w: %s", err, playlist.Name))
+			continue
+		}
+
+		if added {
+			recorded++
+		}
+	}
+
+	return recorded, errors.Join(errs...)
+}
@@ -0,0 +1,150 @@
+package snapshots
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/apitest"
+)
+
+func snapshot(id string, uris ...string) Snapshot {
+	tracks := []Track{}
+	for _, uri := range uris {
+		tracks = append(tracks, Track{Uri: uri, Name: uri, AddedBy: "bob"})
+	}
+
+	return Snapshot{Id: id, Tracks: tracks}
+}
+
+func TestFromItems(t *testing.T) {
+	playlist := api.Playlist{Id: "p1", Name: "Team", SnapshotId: "s1"}
+	items := []api.PlaylistItem{{
+		AddedBy: api.UserProfile{Id: "bob"},
+		Track:   api.Track{Uri: "spotify:track:a", Name: "A", Artists: []api.Artist{{Name: "X"}, {Name: "Y"}}},
+	}}
+
+	got := FromItems(playlist, items, time.Now())
+
+	if got.Id != "s1" || got.Name != "Team" || len(got.Tracks) != 1 {
+		t.Fatalf("invalid snapshot. got=%+v, expected=s1 of Team with one track", got)
+	}
+
+	if track := got.Tracks[0]; track.AddedBy != "bob" || track.String() != "A - X" {
+		t.Fatalf("invalid track. got=%+v, expected=A - X added by bob", track)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := snapshot("s1", "a", "b", "a", "c")
+	to := snapshot("s2", "b", "a", "d", "c", "d")
+
+	changes := Diff(from, to)
+
+	expected := []Change{
+		{Track: from.Tracks[2], Position: 2},
+		{Track: to.Tracks[2], Added: true, Position: 2},
+		{Track: to.Tracks[4], Added: true, Position: 4},
+	}
+
+	if fmt.Sprint(changes) != fmt.Sprint(expected) {
+		t.Fatalf("invalid changes. got=%v, expected=%v", changes, expected)
+	}
+
+	if len(Diff(to, to)) != 0 {
+		t.Fatalf("invalid changes between equal snapshots. got=%v, expected=[]", Diff(to, to))
+	}
+}
+
+func TestRecord(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	for _, id := range []string{"s1", "s1", "s2"} {
+		if _, err := store.Record("p1", snapshot(id, "a")); err != nil {
+			t.Fatalf("failed to record snapshot: %s", err)
+		}
+	}
+
+	history, err := store.History("p1")
+	if err != nil {
+		t.Fatalf("failed to read history: %s", err)
+	}
+
+	if len(history) != 2 || history[0].Id != "s1" || history[1].Id != "s2" {
+		t.Fatalf("invalid history. got=%+v, expected=[s1 s2]", history)
+	}
+
+	if history, _ := store.History("p2"); len(history) != 0 {
+		t.Fatalf("invalid history. got=%+v, expected=[]", history)
+	}
+}
+
+func TestRecordLimit(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	for i := range historyLimit + 5 {
+		store.Record("p1", snapshot(fmt.Sprintf("s%d", i)))
+	}
+
+	history, _ := store.History("p1")
+	if len(history) != historyLimit || history[0].Id != "s5" {
+		t.Fatalf("invalid history. got=%d starting at %s, expected=%d starting at s5", len(history), history[0].Id, historyLimit)
+	}
+}
+
+func TestInvalidId(t *testing.T) {
+	if _, err := NewStore(t.TempDir()).Record("../p1", snapshot("s1")); !errors.Is(err, ErrInvalidId) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, ErrInvalidId)
+	}
+}
+
+func TestRecordAll(t *testing.T) {
+	library := &apitest.FakeLibrary{
+		Items: map[string][]api.PlaylistItem{
+			"mine":     {{Track: api.Track{Uri: "spotify:track:a"}}},
+			"followed": {{Track: api.Track{Uri: "spotify:track:b"}}},
+		},
+		Fail: func(call string) error {
+			if call == "GetPlaylistItems broken" {
+				return api.ErrUnauthorized
+			}
+
+			return nil
+		},
+	}
+
+	playlists := []api.Playlist{
+		{Id: "mine", Name: "Mine", SnapshotId: "s1"},
+		{Id: "followed", Name: "Followed", SnapshotId: "s1", Owner: api.UserProfile{Id: "someone"}},
+		{Id: "broken", Name: "Broken", SnapshotId: "s1"},
+	}
+
+	store := NewStore(t.TempDir())
+
+	recorded, err := store.RecordAll(library, playlists, time.Now())
+	if !errors.Is(err, api.ErrUnauthorized) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, api.ErrUnauthorized)
+	}
+
+	if recorded != 2 {
+		t.Fatalf("invalid recorded count. got=%d, expected=2", recorded)
+	}
+
+	history, _ := store.History("followed")
+	if len(history) != 1 || history[0].Tracks[0].Uri != "spotify:track:b" {
+		t.Fatalf("invalid history. got=%+v, expected=one snapshot of the followed playlist", history)
+	}
+
+	library.Fail = nil
+	playlists[1].SnapshotId = "s2"
+
+	if recorded, err := store.RecordAll(library, playlists, time.Now()); err != nil || recorded != 2 {
+		t.Fatalf("invalid second record. got=%d (%v), expected=2", recorded, err)
+	}
+
+	if count := library.Count("GetPlaylistItems mine"); count != 1 {
+		t.Fatalf("invalid fetches of an unchanged playlist. got=%d, expected=1", count)
+	}
+}
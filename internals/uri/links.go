@@ -1,7 +1,7 @@
 package uri
 
 const (
-	REDIRECT = "http://localhost:6747/callback"
 	ACCOUNTS = "https://accounts.spotify.com"
 	API      = "https://api.spotify.com"
+	APPS     = "https://www.spotify.com/account/apps/"
 )
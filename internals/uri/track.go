@@ -0,0 +1,37 @@
+package uri
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidUri = errors.New("expected a Spotify track link or uri")
+
+var trackIdPattern = regexp.MustCompile(`^[A-Za-z0-9]{22}$`)
+
+func TrackUri(input string) (string, error) {
+	input = strings.TrimSpace(input)
+
+	if id, found := strings.CutPrefix(input, "spotify:track:"); found {
+		if trackIdPattern.MatchString(id) {
+			return input, nil
+		}
+
+		return "", ErrInvalidUri
+	}
+
+	link, err := url.Parse(input)
+	if err != nil || link.Host != "open.spotify.com" {
+		return "", ErrInvalidUri
+	}
+
+	segments := strings.Split(strings.Trim(link.Path, "/"), "/")
+	if len(segments) < 2 || segments[len(segments)-2] != "track" ||
+		!trackIdPattern.MatchString(segments[len(segments)-1]) {
+		return "", ErrInvalidUri
+	}
+
+	return "spotify:track:" + segments[len(segments)-1], nil
+}
@@ -0,0 +1,28 @@
+package uri
+
+import "testing"
+
+func TestTrackUri(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"spotify:track:4uLU6hMCjMI75M1A2tKUQC", "spotify:track:4uLU6hMCjMI75M1A2tKUQC", true},
+		{" https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC ", "spotify:track:4uLU6hMCjMI75M1A2tKUQC", true},
+		{"https://open.spotify.com/intl-pt/track/4uLU6hMCjMI75M1A2tKUQC?si=x", "spotify:track:4uLU6hMCjMI75M1A2tKUQC", true},
+		{"https://open.spotify.com/album/4uLU6hMCjMI75M1A2tKUQC", "", false},
+		{"https://example.com/track/4uLU6hMCjMI75M1A2tKUQC", "", false},
+		{"spotify:track:short", "", false},
+	}
+
+	for _, tt := range tests {
+		uri, err := TrackUri(tt.input)
+		if tt.valid && (err != nil || uri != tt.expected) {
+			t.Fatalf("%q: invalid uri. got=%s, expected=%s (%v)", tt.input, uri, tt.expected, err)
+		}
+		if !tt.valid && err == nil {
+			t.Fatalf("%q: invalid uri. got=%s, expected an error", tt.input, uri)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/franciscosbf/spotify-tui/pkg/cli"
+	"github.com/franciscosbf/spotify-tui/pkg/config"
 	"github.com/franciscosbf/spotify-tui/pkg/ui"
 )
 
@@ -14,16 +16,29 @@ func die(err any) {
 }
 
 func Run() {
-	var config string
-
-	flag.StringVar(&config, "config", "<path>", "configuration file")
+	var location, profile string
+
+	flag.StringVar(&location, "config", "./configs/config.json", "configuration file")
+	flag.StringVar(&profile, "profile", "", "profile to use instead of the one selected in config")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [command]\n\nFlags:\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output())
+		cli.Usage(flag.CommandLine.Output())
+	}
 	flag.Parse()
 
-	if config == "" {
+	if location == "" {
 		die("config parameter missing")
 	}
 
-	tui := ui.New("./configs/config.json")
+	conf := config.NewConfig(location, profile)
+
+	if flag.NArg() > 0 {
+		os.Exit(cli.Run(conf, flag.Args()))
+	}
+
+	tui := ui.New(conf)
 
 	if err := tui.Start(); err != nil {
 		die(err)
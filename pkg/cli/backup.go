@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/backup"
+)
+
+func printProgress(stage string, done, total int) {
+	fmt.Fprintf(os.Stderr, "\r\033[K%s %d/%d", stage, done, total)
+	if done == total {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+func (e env) reportRateLimits() {
+	e.client.OnRateLimit(func(wait time.Duration) {
+		fmt.Fprintf(os.Stderr, "\r\033[KRate limited by Spotify, retrying in %s", wait)
+	})
+}
+
+func backupLibrary(e env, args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	output := flags.String("output", "", "file to write")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() != 0 {
+		return usageErr("unexpected arguments")
+	}
+
+	now := time.Now()
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("spotify-backup-%s.json", now.Format(dateLayout))
+	}
+
+	e.reportRateLimits()
+
+	archive, err := backup.Create(e.client, now, printProgress)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer file.Close()
+
+	if err := backup.Write(file, archive); err != nil {
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	tracks := 0
+	for _, playlist := range archive.Playlists {
+		tracks += len(playlist.Tracks)
+	}
+
+	fmt.Fprintf(e.out, "Saved %d liked songs, %d albums, %d artists and %d playlists with %d tracks to %s\n",
+		len(archive.Tracks), len(archive.Albums), len(archive.Artists), len(archive.Playlists), tracks, path)
+
+	return nil
+}
+
+func restoreLibrary(e env, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	journalPath := flags.String("journal", "", "file tracking the progress, the archive path with .journal by default")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() != 1 {
+		return usageErr("expected a single backup file")
+	}
+
+	path := flags.Arg(0)
+	if *journalPath == "" {
+		*journalPath = path + ".journal"
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	archive, err := backup.Read(file)
+	if err != nil {
+		return err
+	}
+
+	journal := backup.NewJournal(*journalPath)
+	if journal.Exists() {
+		fmt.Fprintf(os.Stderr, "Resuming from %s\n", journal.Path())
+	}
+
+	e.reportRateLimits()
+
+	report, err := backup.Restore(e.client, archive, journal, printProgress)
+	if err != nil {
+		fmt.Fprintln(os.Stderr)
+		return fmt.Errorf("%w (run restore again to resume)", err)
+	}
+
+	for _, skipped := range report.Skipped {
+		fmt.Fprintf(e.out, "Skipped %s\n", skipped)
+	}
+
+	fmt.Fprintf(e.out, "Restored %d liked songs, %d albums, %d artists, %d new playlists and %d playlist tracks\n",
+		report.Tracks, report.Albums, report.Artists, report.Playlists, report.Added)
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/pkg/config"
+	"github.com/franciscosbf/spotify-tui/pkg/daemon"
+)
+
+const (
+	ExitOk = iota
+	ExitFailure
+	ExitUsage
+	ExitAuth
+	ExitNoDevice
+)
+
+var ErrUsage = errors.New("invalid usage")
+
+type env struct {
+	conf   *config.Config
+	player api.Player
+	client *api.Client
+	daemon *daemon.Client
+	in     io.Reader
+	out    io.Writer
+}
+
+type command struct {
+	usage     string
+	anonymous bool
+	web       bool
+	scopes    []string
+	run       func(e env, args []string) error
+}
+
+var commands map[string]command
+
+var (
+	readScopes = []string{
+		auth.ScopePlaylistReadPrivate, auth.ScopePlaylistReadCollaborative,
+	}
+	modifyScopes = []string{
+		auth.ScopePlaylistModifyPublic, auth.ScopePlaylistModifyPrivate,
+	}
+	editScopes   = auth.MergeScopes(readScopes, modifyScopes)
+	backupScopes = auth.MergeScopes(readScopes, []string{
+		auth.ScopeLibraryRead, auth.ScopeFollowRead,
+	})
+	restoreScopes = auth.MergeScopes(backupScopes, modifyScopes, []string{
+		auth.ScopeLibraryModify, auth.ScopeFollowModify,
+	})
+	smartScopes = auth.MergeScopes(backupScopes, modifyScopes, []string{
+		auth.ScopeTopRead, auth.ScopeReadRecentlyPlayed,
+	})
+)
+
+func init() {
+	commands = map[string]command{
+		"play":         {usage: "play", run: play},
+		"pause":        {usage: "pause", run: pause},
+		"toggle":       {usage: "toggle", run: toggle},
+		"next":         {usage: "next", run: next},
+		"prev":         {usage: "prev", run: prev},
+		"shuffle":      {usage: "shuffle on|off", run: shuffle},
+		"repeat":       {usage: "repeat track|context|off", run: repeat},
+		"volume":       {usage: "volume N|+N|-N", run: volume},
+		"seek":         {usage: "seek [+|-]SECONDS|M:SS", run: seek},
+		"device":       {usage: "device <name>", run: device},
+		"devices":      {usage: "devices", run: devices},
+		"status":       {usage: "status [--format json|TEMPLATE] [--follow] [--interval DURATION]", run: status},
+		"logout":       {usage: "logout", anonymous: true, run: logout},
+		"daemon":       {usage: "daemon [--interval DURATION] [--mpris=false] [--history=false] [--smart=false]", anonymous: true, run: runDaemon},
+		"history":      {usage: "history [--format csv|json] [--since YYYY-MM-DD] [--until YYYY-MM-DD]", anonymous: true, run: exportHistory},
+		"lastfm-login": {usage: "lastfm-login", anonymous: true, run: lastFmLogin},
+		"export":       {usage: "export <playlist> [--format m3u8|csv|json|xspf] [--output FILE]", web: true, scopes: readScopes, run: exportPlaylist},
+		"import":       {usage: "import FILE [--format m3u8|csv|json|xspf] [--name NAME] [--public] [--yes]", web: true, scopes: editScopes, run: importPlaylist},
+		"backup":       {usage: "backup [--output FILE]", web: true, scopes: backupScopes, run: backupLibrary},
+		"restore":      {usage: "restore FILE [--journal FILE]", web: true, scopes: restoreScopes, run: restoreLibrary},
+		"dedupe":       {usage: "dedupe [--isrc] [--names] [--yes] <playlist>", web: true, scopes: editScopes, run: dedupePlaylist},
+		"sort":         {usage: "sort --by artist|album|added|duration|release [--desc] [--yes] <playlist>", web: true, scopes: editScopes, run: sortPlaylist},
+		"merge":        {usage: "merge --into NAME [--keep-duplicates] [--public] [--yes] <playlist> <playlist>...", web: true, scopes: editScopes, run: mergePlaylists},
+		"filter":       {usage: "filter --into NAME [--years FROM-TO] [--exclude-artist NAME]... [--no-explicit] [--public] [--yes] <playlist>", web: true, scopes: editScopes, run: filterPlaylist},
+		"smart":        {usage: "smart [--dry-run] [name...]", web: true, scopes: smartScopes, run: refreshSmartPlaylists},
+	}
+}
+
+func (e env) refreshToken() error {
+	if e.client == nil {
+		return nil
+	}
+
+	if client, err := daemon.Attach(daemon.SocketPath(), e.conf.Profile()); err == nil {
+		defer client.Close()
+
+		token, err := client.Token()
+		if err != nil {
+			return err
+		}
+
+		e.client.SetToken(token.Access)
+
+		return e.conf.Read()
+	}
+
+	token, err := e.conf.ValidToken()
+	if err != nil {
+		return err
+	}
+
+	e.client.SetToken(token.Access)
+
+	return nil
+}
+
+func usageErr(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrUsage, fmt.Sprintf(format, args...))
+}
+
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOk
+	case errors.Is(err, ErrUsage):
+		return ExitUsage
+	case errors.Is(err, config.ErrNotLoggedIn),
+		errors.Is(err, auth.ErrTokenRejected),
+		errors.Is(err, auth.ErrTokenInvalidResponse),
+		errors.Is(err, api.ErrUnauthorized):
+		return ExitAuth
+	case errors.Is(err, api.ErrNoActiveDevice):
+		return ExitNoDevice
+	}
+
+	return ExitFailure
+}
+
+func report(err error) int {
+	code := exitCode(err)
+
+	switch code {
+	case ExitOk:
+	case ExitAuth:
+		fmt.Fprintf(os.Stderr, "app error: %s (run without a command to log in)\n", err)
+	default:
+		fmt.Fprintf(os.Stderr, "app error: %s\n", err)
+	}
+
+	return code
+}
+
+func Usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+}
+
+func Run(conf *config.Config, args []string) int {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		code := report(usageErr("unknown command %s", args[0]))
+		Usage(os.Stderr)
+
+		return code
+	}
+
+	if err := conf.Read(); err != nil {
+		return report(err)
+	}
+
+	e := env{conf: conf, in: os.Stdin, out: os.Stdout}
+
+	if cmd.web {
+		e.client = api.NewClient()
+		e.player = e.client
+
+		if err := e.refreshToken(); err != nil {
+			return report(err)
+		}
+
+		if err := e.grantScopes(cmd.scopes); err != nil {
+			return report(err)
+		}
+	} else if !cmd.anonymous {
+		if client, err := daemon.Attach(daemon.SocketPath(), conf.Profile()); err == nil {
+			defer client.Close()
+
+			e.player, e.daemon = client, client
+		} else {
+			e.client = api.NewClient()
+			e.player = e.client
+
+			if err := e.refreshToken(); err != nil {
+				return report(err)
+			}
+		}
+	}
+
+	err := cmd.run(e, args[1:])
+	if errors.Is(err, ErrUsage) {
+		fmt.Fprintf(os.Stderr, "usage: %s\n", cmd.usage)
+	}
+
+	return report(err)
+}
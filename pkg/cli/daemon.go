@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/skip2/go-qrcode"
+
+	"github.com/franciscosbf/spotify-tui/internals/remote"
+	"github.com/franciscosbf/spotify-tui/internals/smart"
+	"github.com/franciscosbf/spotify-tui/pkg/daemon"
+)
+
+func runDaemon(e env, args []string) error {
+	flags := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	interval := flags.Duration("interval", daemon.DefaultInterval, "playback polling interval")
+	mpris := flags.Bool("mpris", true, "expose the player over MPRIS")
+	recordHistory := flags.Bool("history", true, "record the listening history")
+	refreshSmart := flags.Bool("smart", true, "refresh scheduled smart playlists")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() != 0 {
+		return usageErr("unexpected arguments")
+	}
+
+	if *interval <= 0 {
+		return usageErr("interval must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	path := daemon.SocketPath()
+
+	listener, err := daemon.Listen(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+
+	if _, err := e.conf.ValidToken(); err != nil {
+		fmt.Fprintf(os.Stderr, "app error: %s (requests will fail until you log in)\n", err)
+	}
+
+	d := daemon.New(e.conf, *interval)
+
+	if *mpris {
+		if err := d.EnableMpris(); err != nil {
+			fmt.Fprintf(os.Stderr, "app error: mpris disabled: %s\n", err)
+		}
+	}
+
+	if *recordHistory {
+		d.EnableHistory(e.conf.DataPath("history.db"))
+	}
+
+	if address := e.conf.RemoteAddress(); address != "" {
+		if err := enableRemote(e, d, address); err != nil {
+			fmt.Fprintf(os.Stderr, "app error: remote control disabled: %s\n", err)
+		}
+	}
+
+	if conf := e.conf.Hooks(); !conf.Empty() {
+		if err := d.EnableHooks(conf); err != nil {
+			fmt.Fprintf(os.Stderr, "app error: hooks disabled: %s\n", err)
+		}
+	}
+
+	if backends := e.conf.Scrobblers(); len(backends) > 0 {
+		if err := d.EnableScrobbling(backends, e.conf.DataPath("scrobbles.json")); err != nil {
+			fmt.Fprintf(os.Stderr, "app error: scrobbling disabled: %s\n", err)
+		}
+	}
+
+	if *refreshSmart {
+		enableSmartPlaylists(e, d)
+	}
+
+	fmt.Fprintf(e.out, "Listening on %s for profile %s\n", path, e.conf.Profile())
+
+	return d.Run(ctx, listener)
+}
+
+func enableSmartPlaylists(e env, d *daemon.Daemon) {
+	scheduled := []smart.Definition{}
+	for _, definition := range e.conf.SmartPlaylists() {
+		if definition.Refresh <= 0 {
+			continue
+		}
+
+		if err := definition.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "app error: smart playlist skipped: %s\n", err)
+			continue
+		}
+
+		scheduled = append(scheduled, definition)
+	}
+
+	if len(scheduled) > 0 {
+		d.EnableSmartPlaylists(scheduled, e.conf.DataPath("smart.json"), e.conf.DataPath("history.db"))
+	}
+}
+
+func enableRemote(e env, d *daemon.Daemon, address string) error {
+	token := e.conf.RemoteToken()
+	if token == "" {
+		token = remote.GenerateToken()
+
+		if err := e.conf.UpdateRemoteToken(token); err != nil {
+			return err
+		}
+	}
+
+	url, err := d.EnableRemote(address, token)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s#token=%s", url, token)
+
+	fmt.Fprintf(e.out, "Remote control at %s\n", link)
+
+	if code, err := qrcode.New(link, qrcode.Low); err == nil {
+		fmt.Fprint(e.out, code.ToSmallString(false))
+	}
+
+	return nil
+}
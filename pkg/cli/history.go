@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"flag"
+	"io"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/history"
+)
+
+const dateLayout = "2006-01-02"
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.ParseInLocation(dateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, usageErr("invalid date %s, expected YYYY-MM-DD", value)
+	}
+
+	return date, nil
+}
+
+func exportHistory(e env, args []string) error {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	format := flags.String("format", history.FormatCsv, "csv or json")
+	since := flags.String("since", "", "first day to export")
+	until := flags.String("until", "", "last day to export")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() != 0 {
+		return usageErr("unexpected arguments")
+	}
+
+	if *format != history.FormatCsv && *format != history.FormatJson {
+		return usageErr("unknown format %s", *format)
+	}
+
+	from, err := parseDate(*since)
+	if err != nil {
+		return err
+	}
+
+	to, err := parseDate(*until)
+	if err != nil {
+		return err
+	}
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	plays, err := history.NewStore(e.conf.DataPath("history.db")).Plays(from, to)
+	if err != nil {
+		return err
+	}
+
+	return history.Export(e.out, *format, plays)
+}
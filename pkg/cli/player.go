@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/uri"
+)
+
+func noArgs(args []string, op func() error) error {
+	if len(args) != 0 {
+		return usageErr("unexpected arguments")
+	}
+
+	return op()
+}
+
+func oneArg(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", usageErr("expected exactly one argument")
+	}
+
+	return args[0], nil
+}
+
+func play(e env, args []string) error {
+	return noArgs(args, e.player.Resume)
+}
+
+func pause(e env, args []string) error {
+	return noArgs(args, e.player.Pause)
+}
+
+func next(e env, args []string) error {
+	return noArgs(args, e.player.SkipToNext)
+}
+
+func prev(e env, args []string) error {
+	return noArgs(args, e.player.SkipToPrevious)
+}
+
+func toggle(e env, args []string) error {
+	return noArgs(args, func() error {
+		state, err := e.player.GetPlaybackState()
+		if err != nil {
+			return err
+		}
+
+		if state.IsPlaying {
+			return e.player.Pause()
+		}
+
+		return e.player.Resume()
+	})
+}
+
+func shuffle(e env, args []string) error {
+	arg, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+
+	switch arg {
+	case "on":
+		return e.player.EnableShuffle()
+	case "off":
+		return e.player.DisableShuffle()
+	}
+
+	return usageErr("unknown shuffle state %s", arg)
+}
+
+func repeat(e env, args []string) error {
+	arg, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+
+	switch arg {
+	case "track":
+		return e.player.SetRepeatTrack()
+	case "context":
+		return e.player.SetRepeatContext()
+	case "off":
+		return e.player.DisableRepeat()
+	}
+
+	return usageErr("unknown repeat state %s", arg)
+}
+
+func relative(arg string) bool {
+	return strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-")
+}
+
+func volume(e env, args []string) error {
+	arg, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+
+	percent, err := strconv.Atoi(arg)
+	if err != nil {
+		return usageErr("invalid volume %s", arg)
+	}
+
+	if relative(arg) {
+		state, err := e.player.GetPlaybackState()
+		if err != nil {
+			return err
+		}
+
+		percent += state.Device.VolumePercent
+	}
+
+	return e.player.SetVolume(percent)
+}
+
+func parsePosition(arg string) (time.Duration, error) {
+	arg = strings.TrimLeft(arg, "+-")
+
+	minutes, seconds, found := strings.Cut(arg, ":")
+	if !found {
+		minutes, seconds = "0", arg
+	}
+
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 {
+		return 0, usageErr("invalid position %s", arg)
+	}
+
+	s, err := strconv.Atoi(seconds)
+	if err != nil || s < 0 || (found && s >= 60) {
+		return 0, usageErr("invalid position %s", arg)
+	}
+
+	return time.Duration(m)*time.Minute + time.Duration(s)*time.Second, nil
+}
+
+func seek(e env, args []string) error {
+	arg, err := oneArg(args)
+	if err != nil {
+		return err
+	}
+
+	position, err := parsePosition(arg)
+	if err != nil {
+		return err
+	}
+
+	if relative(arg) {
+		state, err := e.player.GetPlaybackState()
+		if err != nil {
+			return err
+		}
+
+		progress := time.Duration(state.ProgressMs) * time.Millisecond
+		if strings.HasPrefix(arg, "-") {
+			position = progress - position
+		} else {
+			position = progress + position
+		}
+	}
+
+	return e.player.Seek(int(position.Milliseconds()))
+}
+
+func matchDevice(devices []api.Device, name string) (api.Device, error) {
+	lowered := strings.ToLower(name)
+
+	var matches []api.Device
+	for _, d := range devices {
+		deviceName := strings.ToLower(d.Name)
+
+		if deviceName == lowered {
+			return d, nil
+		}
+
+		if strings.HasPrefix(deviceName, lowered) {
+			matches = append(matches, d)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return api.Device{}, fmt.Errorf("no device matching %s", name)
+	case 1:
+		return matches[0], nil
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, d := range matches {
+		names = append(names, d.Name)
+	}
+
+	return api.Device{}, fmt.Errorf("%s matches several devices: %s", name, strings.Join(names, ", "))
+}
+
+func device(e env, args []string) error {
+	if len(args) == 0 {
+		return usageErr("missing device name")
+	}
+
+	available, err := e.player.GetDevices()
+	if err != nil {
+		return err
+	}
+
+	d, err := matchDevice(available, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	return e.player.TransferPlayback(d.Id, false)
+}
+
+func devices(e env, args []string) error {
+	return noArgs(args, func() error {
+		available, err := e.player.GetDevices()
+		if err != nil {
+			return err
+		}
+
+		for _, d := range available {
+			marker := " "
+			if d.IsActive {
+				marker = "*"
+			}
+
+			fmt.Fprintf(e.out, "%s %s (%s)\n", marker, d.Name, strings.ToLower(d.Type))
+		}
+
+		return nil
+	})
+}
+
+func logout(e env, args []string) error {
+	return noArgs(args, func() error {
+		if err := e.conf.ClearToken(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(e.out, "Logged out from %s.\n", e.conf.Profile())
+		fmt.Fprintf(e.out, "To fully revoke access, remove the app at %s\n", uri.APPS)
+
+		return nil
+	})
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+	"github.com/franciscosbf/spotify-tui/internals/snapshots"
+)
+
+func findPlaylist(client *api.Client, name string) (api.Playlist, error) {
+	if id, found := strings.CutPrefix(name, "spotify:playlist:"); found {
+		return client.GetPlaylist(id)
+	}
+
+	if rest, found := strings.CutPrefix(name, "https://open.spotify.com/playlist/"); found {
+		id, _, _ := strings.Cut(rest, "?")
+		return client.GetPlaylist(id)
+	}
+
+	owned, err := client.GetMyPlaylists()
+	if err != nil {
+		return api.Playlist{}, err
+	}
+
+	lowered := strings.ToLower(name)
+
+	var matches []api.Playlist
+	for _, playlist := range owned {
+		playlistName := strings.ToLower(playlist.Name)
+
+		if playlistName == lowered || playlist.Id == name {
+			return playlist, nil
+		}
+
+		if strings.HasPrefix(playlistName, lowered) {
+			matches = append(matches, playlist)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return api.Playlist{}, fmt.Errorf("no playlist matching %s", name)
+	case 1:
+		return matches[0], nil
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, playlist := range matches {
+		names = append(names, playlist.Name)
+	}
+
+	return api.Playlist{}, fmt.Errorf("%s matches several playlists: %s", name, strings.Join(names, ", "))
+}
+
+func playlistFormat(format, path string) (string, error) {
+	switch {
+	case format != "":
+		for _, known := range playlists.Formats {
+			if format == known {
+				return format, nil
+			}
+		}
+		return "", usageErr("unknown format %s", format)
+	case path == "":
+		return playlists.FormatM3u, nil
+	}
+
+	format, err := playlists.FormatFromPath(path)
+	if err != nil {
+		return "", usageErr("%s, set --format", err)
+	}
+
+	return format, nil
+}
+
+func exportPlaylist(e env, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	format := flags.String("format", "", "m3u8, csv, json or xspf")
+	output := flags.String("output", "", "file to write")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() == 0 {
+		return usageErr("missing playlist")
+	}
+
+	chosen, err := playlistFormat(*format, *output)
+	if err != nil {
+		return err
+	}
+
+	playlist, err := findPlaylist(e.client, strings.Join(flags.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	items, err := e.client.GetPlaylistItems(playlist.Id)
+	if err != nil {
+		return err
+	}
+
+	store := snapshots.NewStore(e.conf.DataPath("snapshots"))
+	if _, err := store.Record(playlist.Id, snapshots.FromItems(playlist, items, time.Now())); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to record the snapshot: %s\n", err)
+	}
+
+	entries := make([]playlists.Entry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, playlists.FromTrack(item.Track))
+	}
+
+	if *output == "" {
+		return playlists.Write(e.out, chosen, playlist.Name, entries)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := playlists.Write(file, chosen, playlist.Name, entries); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.out, "Exported %d tracks of %s to %s\n", len(entries), playlist.Name, *output)
+
+	return file.Close()
+}
+
+func confirm(in *bufio.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+
+	answer, _ := in.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+}
+
+func importPlaylist(e env, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	format := flags.String("format", "", "m3u8, csv, json or xspf")
+	name := flags.String("name", "", "name of the new playlist")
+	public := flags.Bool("public", false, "make the playlist public")
+	yes := flags.Bool("yes", false, "create the playlist without asking, leaving out uncertain matches")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() != 1 {
+		return usageErr("expected a single file")
+	}
+
+	path := flags.Arg(0)
+
+	chosen, err := playlistFormat(*format, path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	title, entries, err := playlists.Read(file, chosen)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *name != "":
+		title = *name
+	case title == "":
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	matches, err := playlists.Resolve(e.client, entries, func(done int) {
+		fmt.Fprintf(os.Stderr, "\rMatching %d/%d", done, len(entries))
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	for _, match := range matches {
+		switch match.Status {
+		case playlists.Ambiguous:
+			fmt.Fprintf(e.out, "? %s → %s\n", match.Entry, playlists.FromTrack(match.Track))
+		case playlists.Unmatched:
+			fmt.Fprintf(e.out, "✗ %s\n", match.Entry)
+		}
+	}
+
+	uris := playlists.Uris(matches, false)
+	guesses := len(playlists.Uris(matches, true)) - len(uris)
+
+	fmt.Fprintf(e.out, "%d matched, %d uncertain, %d not found\n",
+		len(uris), guesses, len(matches)-len(uris)-guesses)
+
+	if !*yes {
+		in := bufio.NewReader(e.in)
+
+		if guesses > 0 && confirm(in, e.out, "Include the uncertain matches (?) with their best guess?") {
+			uris = playlists.Uris(matches, true)
+		}
+
+		if !confirm(in, e.out, fmt.Sprintf("Create %s with %d tracks?", title, len(uris))) {
+			return nil
+		}
+	}
+
+	profile, err := e.client.GetUserProfile()
+	if err != nil {
+		return err
+	}
+
+	playlist, err := e.client.CreatePlaylist(profile.Id, api.PlaylistDetails{Name: title, Public: *public})
+	if err != nil {
+		return err
+	}
+
+	if _, err := e.client.AddPlaylistTracks(playlist.Id, uris); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.out, "Created %s with %d tracks\n", playlist.Name, len(uris))
+
+	return nil
+}
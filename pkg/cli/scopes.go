@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/internals/browser"
+)
+
+func (e env) authorizeInBrowser(scopes []string) (auth.Token, bool, error) {
+	if !browser.Available() {
+		return auth.Token{}, false, nil
+	}
+
+	authorization, err := api.NewAuthorization(e.conf.ClientId(), e.conf.Redirect(), scopes)
+	if err != nil {
+		return auth.Token{}, false, nil
+	}
+
+	if err := authorization.OpenBrowser(); err != nil {
+		authorization.Close()
+		return auth.Token{}, false, nil
+	}
+
+	fmt.Fprintln(os.Stderr, "Authorize them in the browser window that just opened...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.conf.AuthTimeout())
+	defer cancel()
+
+	token, err := authorization.WaitForToken(ctx)
+
+	return token, true, err
+}
+
+func (e env) grantScopes(needed []string) error {
+	granted := e.conf.Token().Scopes
+
+	missing := auth.MissingScopes(granted, needed)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "This command needs more permissions: %s\n", strings.Join(missing, ", "))
+
+	scopes := auth.MergeScopes(granted, missing)
+
+	token, done, err := e.authorizeInBrowser(scopes)
+	if !done {
+		authorization := api.NewManualAuthorization(e.conf.ClientId(), e.conf.Redirect(), scopes)
+
+		fmt.Fprintf(os.Stderr, "Authorize them at %s\nthen paste the URL you were redirected to: ", authorization.Url())
+
+		redirectUrl, _ := bufio.NewReader(e.in).ReadString('\n')
+		token, err = authorization.TokenFromRedirect(strings.TrimSpace(redirectUrl))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := e.conf.UpdateToken(token); err != nil {
+		return err
+	}
+
+	e.client.SetToken(token.Access)
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+
+	"github.com/franciscosbf/spotify-tui/internals/browser"
+)
+
+var ErrLastFmNotConfigured = errors.New("scrobble.lastfm.api_key and secret must be set in the config")
+
+func lastFmLogin(e env, args []string) error {
+	return noArgs(args, func() error {
+		lastFm := e.conf.LastFm()
+		if lastFm == nil {
+			return ErrLastFmNotConfigured
+		}
+
+		token, err := lastFm.Token()
+		if err != nil {
+			return err
+		}
+
+		link := lastFm.AuthUrl(token)
+
+		fmt.Fprintf(e.out, "Allow access at %s\n", link)
+		if browser.Available() {
+			browser.OpenAuthLink(link)
+		}
+
+		fmt.Fprint(e.out, "Press enter once done...")
+		bufio.NewReader(e.in).ReadString('\n')
+
+		session, err := lastFm.Session(token)
+		if err != nil {
+			return err
+		}
+
+		if err := e.conf.UpdateLastFmSession(session); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(e.out, "Last.fm connected, restart the daemon to start scrobbling.")
+
+		return nil
+	})
+}
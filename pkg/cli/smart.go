@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/history"
+	"github.com/franciscosbf/spotify-tui/internals/smart"
+)
+
+func refreshSmartPlaylists(e env, args []string) error {
+	flags := flag.NewFlagSet("smart", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	dryRun := flags.Bool("dry-run", false, "list the tracks without changing any playlist")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	definitions := e.conf.SmartPlaylists()
+	if len(definitions) == 0 {
+		return usageErr("no smart_playlists in config")
+	}
+
+	if names := flags.Args(); len(names) > 0 {
+		for _, name := range names {
+			if !slices.ContainsFunc(definitions, func(d smart.Definition) bool { return d.Name == name }) {
+				return usageErr("unknown smart playlist %s", name)
+			}
+		}
+
+		definitions = slices.DeleteFunc(definitions, func(d smart.Definition) bool {
+			return !slices.Contains(names, d.Name)
+		})
+	}
+
+	for _, definition := range definitions {
+		if err := definition.Validate(); err != nil {
+			return usageErr("%s", err)
+		}
+	}
+
+	e.reportRateLimits()
+
+	plays := smart.NewHistoryLog(history.NewStore(e.conf.DataPath("history.db")))
+	evaluator := smart.NewEvaluator(e.client, plays, time.Now())
+	state := smart.NewState(e.conf.DataPath("smart.json"))
+
+	for _, definition := range definitions {
+		if *dryRun {
+			tracks, err := evaluator.Tracks(definition)
+			if err != nil {
+				return fmt.Errorf("%s: %w", definition.Name, err)
+			}
+
+			fmt.Fprintf(e.out, "%s (%d tracks)\n", definition.Name, len(tracks))
+			for i, item := range tracks {
+				fmt.Fprintf(e.out, "  %d. %s\n", i+1, trackLabel(item.Track))
+			}
+
+			continue
+		}
+
+		result, err := evaluator.Refresh(definition, state)
+		if err != nil {
+			return fmt.Errorf("%s: %w", definition.Name, err)
+		}
+
+		verb := "Refreshed"
+		if result.Created {
+			verb = "Created"
+		}
+
+		fmt.Fprintf(e.out, "%s %s with %d tracks\n", verb, result.Playlist.Name, len(result.Tracks))
+	}
+
+	return nil
+}
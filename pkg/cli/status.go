@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+)
+
+const defaultStatusFormat = "{{if .Title}}{{.Artist}} - {{.Title}}{{end}}"
+
+type statusRenderer func(status playback.Status) (string, error)
+
+func newStatusRenderer(format string) (statusRenderer, error) {
+	if format == "json" {
+		return func(status playback.Status) (string, error) {
+			encoded, err := json.Marshal(status)
+
+			return string(encoded), err
+		}, nil
+	}
+
+	tmpl, err := template.New("status").Parse(format)
+	if err != nil {
+		return nil, usageErr("invalid format: %s", err)
+	}
+
+	return func(status playback.Status) (string, error) {
+		var line strings.Builder
+
+		err := tmpl.Execute(&line, status)
+
+		return line.String(), err
+	}, nil
+}
+
+func fetchStatus(e env) (playback.Status, error) {
+	state, err := e.player.GetPlaybackState()
+	if errors.Is(err, api.ErrNoActiveDevice) {
+		return playback.Stopped(), nil
+	}
+	if err != nil {
+		return playback.Status{}, err
+	}
+
+	return playback.FromState(state), nil
+}
+
+func fatal(err error) bool {
+	code := exitCode(err)
+
+	return code == ExitAuth || code == ExitUsage
+}
+
+func followDaemon(ctx context.Context, e env, render statusRenderer) error {
+	initial, statuses, err := e.daemon.Subscribe()
+	if err != nil {
+		return err
+	}
+
+	var last string
+
+	for status, ok := initial, true; ok; {
+		line, err := render(status)
+		if err != nil {
+			return err
+		}
+
+		if line != last {
+			fmt.Fprintln(e.out, line)
+			last = line
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case status, ok = <-statuses:
+		}
+	}
+
+	return errors.New("daemon connection lost")
+}
+
+func follow(e env, interval time.Duration, render statusRenderer) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if e.daemon != nil {
+		return followDaemon(ctx, e, render)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	printed := false
+	var last string
+
+	for {
+		status, err := func() (playback.Status, error) {
+			if err := e.refreshToken(); err != nil {
+				return playback.Status{}, err
+			}
+
+			return fetchStatus(e)
+		}()
+
+		if err == nil {
+			var line string
+
+			if line, err = render(status); err != nil {
+				return err
+			}
+
+			if !printed || line != last {
+				fmt.Fprintln(e.out, line)
+				printed, last = true, line
+			}
+		} else if fatal(err) {
+			return err
+		} else {
+			fmt.Fprintf(os.Stderr, "app error: %s\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func status(e env, args []string) error {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	format := flags.String("format", defaultStatusFormat, "json or a Go template")
+	followMode := flags.Bool("follow", false, "keep printing on every change")
+	interval := flags.Duration("interval", time.Second, "polling interval when following")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() != 0 {
+		return usageErr("unexpected arguments")
+	}
+
+	if *interval <= 0 {
+		return usageErr("interval must be positive")
+	}
+
+	render, err := newStatusRenderer(*format)
+	if err != nil {
+		return err
+	}
+
+	if *followMode {
+		return follow(e, *interval, render)
+	}
+
+	status, err := fetchStatus(e)
+	if err != nil {
+		return err
+	}
+
+	line, err := render(status)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.out, line)
+
+	return nil
+}
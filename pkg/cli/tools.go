@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+)
+
+type artistsFlag []string
+
+func (a *artistsFlag) String() string {
+	return strings.Join(*a, ", ")
+}
+
+func (a *artistsFlag) Set(value string) error {
+	*a = append(*a, value)
+
+	return nil
+}
+
+func trackLabel(track api.Track) string {
+	return playlists.FromTrack(track).String()
+}
+
+func printPreview(out io.Writer, changes []playlists.Change) int {
+	removed, added, moved := 0, 0, 0
+
+	for _, change := range changes {
+		switch {
+		case change.From < 0:
+			added++
+			fmt.Fprintf(out, "+ %s\n", trackLabel(change.Item.Track))
+		case change.To < 0:
+			removed++
+			fmt.Fprintf(out, "- #%d %s\n", change.From+1, trackLabel(change.Item.Track))
+		case change.Moved():
+			moved++
+			fmt.Fprintf(out, "  #%d → #%d %s\n", change.From+1, change.To+1, trackLabel(change.Item.Track))
+		}
+	}
+
+	fmt.Fprintf(out, "%d added, %d removed, %d moved\n", added, removed, moved)
+
+	return added + removed + moved
+}
+
+func editablePlaylist(e env, name string) (api.Playlist, []api.PlaylistItem, error) {
+	playlist, err := findPlaylist(e.client, name)
+	if err != nil {
+		return api.Playlist{}, nil, err
+	}
+
+	profile, err := e.client.GetUserProfile()
+	if err != nil {
+		return api.Playlist{}, nil, err
+	}
+
+	if playlist.Owner.Id != profile.Id && !playlist.Collaborative {
+		return api.Playlist{}, nil, fmt.Errorf("%s belongs to %s and can't be changed", playlist.Name, playlist.Owner.Id)
+	}
+
+	items, err := e.client.GetPlaylistItems(playlist.Id)
+	if err != nil {
+		return api.Playlist{}, nil, err
+	}
+
+	return playlist, items, nil
+}
+
+func rewritePlaylist(e env, playlist api.Playlist, before, after []api.PlaylistItem, yes bool) error {
+	if printPreview(e.out, playlists.Compare(before, after)) == 0 {
+		fmt.Fprintf(e.out, "Nothing to change in %s\n", playlist.Name)
+		return nil
+	}
+
+	if !yes && !confirm(bufio.NewReader(e.in), e.out, fmt.Sprintf("Apply the changes to %s?", playlist.Name)) {
+		return nil
+	}
+
+	if _, err := playlists.Rewrite(e.client, playlist.Id, playlist.SnapshotId, before, after); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.out, "Updated %s\n", playlist.Name)
+
+	return nil
+}
+
+func createFrom(e env, name string, public bool, before, after []api.PlaylistItem, yes bool) error {
+	printPreview(e.out, playlists.Compare(before, after))
+
+	if len(after) == 0 {
+		return fmt.Errorf("no tracks left for %s", name)
+	}
+
+	if !yes && !confirm(bufio.NewReader(e.in), e.out, fmt.Sprintf("Create %s with %d tracks?", name, len(after))) {
+		return nil
+	}
+
+	profile, err := e.client.GetUserProfile()
+	if err != nil {
+		return err
+	}
+
+	playlist, err := e.client.CreatePlaylist(profile.Id, api.PlaylistDetails{Name: name, Public: public})
+	if err != nil {
+		return err
+	}
+
+	uris := []string{}
+	for _, item := range after {
+		uris = append(uris, item.Track.Uri)
+	}
+
+	if _, err := e.client.AddPlaylistTracks(playlist.Id, uris); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.out, "Created %s with %d tracks\n", playlist.Name, len(uris))
+
+	return nil
+}
+
+func dedupePlaylist(e env, args []string) error {
+	flags := flag.NewFlagSet("dedupe", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	isrc := flags.Bool("isrc", false, "treat tracks with the same ISRC as duplicates")
+	names := flags.Bool("names", false, "treat tracks with the same title and artist as duplicates")
+	yes := flags.Bool("yes", false, "apply without asking")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() == 0 {
+		return usageErr("missing playlist")
+	}
+
+	playlist, items, err := editablePlaylist(e, strings.Join(flags.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	after := playlists.Dedupe(items, playlists.DedupeOptions{Isrc: *isrc, Names: *names})
+
+	return rewritePlaylist(e, playlist, items, after, *yes)
+}
+
+func sortPlaylist(e env, args []string) error {
+	flags := flag.NewFlagSet("sort", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	by := flags.String("by", "", "artist, album, added, duration or release")
+	descending := flags.Bool("desc", false, "sort in descending order")
+	yes := flags.Bool("yes", false, "apply without asking")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() == 0 {
+		return usageErr("missing playlist")
+	}
+
+	sortKey, err := playlists.ParseSortKey(*by)
+	if err != nil {
+		return usageErr("%s", err)
+	}
+
+	playlist, items, err := editablePlaylist(e, strings.Join(flags.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	return rewritePlaylist(e, playlist, items, playlists.Sort(items, sortKey, *descending), *yes)
+}
+
+func mergePlaylists(e env, args []string) error {
+	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	into := flags.String("into", "", "name of the new playlist")
+	keep := flags.Bool("keep-duplicates", false, "keep tracks present in several playlists")
+	public := flags.Bool("public", false, "make the playlist public")
+	yes := flags.Bool("yes", false, "create the playlist without asking")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() < 2 || *into == "" {
+		return usageErr("expected at least two playlists and --into")
+	}
+
+	lists := [][]api.PlaylistItem{}
+	for _, name := range flags.Args() {
+		playlist, err := findPlaylist(e.client, name)
+		if err != nil {
+			return err
+		}
+
+		items, err := e.client.GetPlaylistItems(playlist.Id)
+		if err != nil {
+			return err
+		}
+
+		lists = append(lists, items)
+	}
+
+	before := playlists.Merge(lists...)
+
+	after := before
+	if !*keep {
+		after = playlists.Dedupe(before, playlists.DedupeOptions{})
+	}
+
+	return createFrom(e, *into, *public, before, after, *yes)
+}
+
+func filterPlaylist(e env, args []string) error {
+	flags := flag.NewFlagSet("filter", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	var excluded artistsFlag
+
+	into := flags.String("into", "", "name of the new playlist")
+	years := flags.String("years", "", "release years to keep, as FROM-TO")
+	noExplicit := flags.Bool("no-explicit", false, "leave out explicit tracks")
+	public := flags.Bool("public", false, "make the playlist public")
+	yes := flags.Bool("yes", false, "create the playlist without asking")
+	flags.Var(&excluded, "exclude-artist", "artist to leave out, can be repeated")
+
+	if err := flags.Parse(args); err != nil {
+		return usageErr("%s", err)
+	}
+
+	if flags.NArg() == 0 || *into == "" {
+		return usageErr("expected a playlist and --into")
+	}
+
+	from, to, err := playlists.ParseYears(*years)
+	if err != nil {
+		return usageErr("%s", err)
+	}
+
+	playlist, err := findPlaylist(e.client, strings.Join(flags.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	items, err := e.client.GetPlaylistItems(playlist.Id)
+	if err != nil {
+		return err
+	}
+
+	filter := playlists.Filter{FromYear: from, ToYear: to, ExcludeArtists: excluded, ExcludeExplicit: *noExplicit}
+
+	return createFrom(e, *into, *public, items, playlists.FilterItems(items, filter), *yes)
+}
@@ -2,46 +2,338 @@ package config
 
 import (
 	"errors"
+	"path/filepath"
+	"slices"
+	"time"
 
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
 	"github.com/franciscosbf/spotify-tui/internals/config"
+	"github.com/franciscosbf/spotify-tui/internals/hooks"
+	"github.com/franciscosbf/spotify-tui/internals/party"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+	"github.com/franciscosbf/spotify-tui/internals/scrobble"
+	"github.com/franciscosbf/spotify-tui/internals/smart"
 )
 
-var ErrMissingClientId = errors.New("missing client_id field in config")
+var (
+	ErrMissingClientId = errors.New("missing client_id field in config")
+	ErrMissingProfiles = errors.New("no profiles in config")
+	ErrUnknownProfile  = errors.New("unknown profile")
+	ErrNotLoggedIn     = errors.New("not logged in")
+)
 
 type Config struct {
 	location string
+	profile  string
 	conf     config.Config
 }
 
+func findProfile(conf config.Config, name string) (int, error) {
+	idx := slices.IndexFunc(conf.Profiles, func(p config.Profile) bool {
+		return p.Name == name
+	})
+	if idx == -1 {
+		return -1, ErrUnknownProfile
+	}
+
+	if conf.Profiles[idx].ClientId == "" {
+		return -1, ErrMissingClientId
+	}
+
+	return idx, nil
+}
+
+func (a *Config) find(name string) (int, error) {
+	return findProfile(a.conf, name)
+}
+
+func (a *Config) active() (*config.Profile, error) {
+	idx, err := a.find(a.profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a.conf.Profiles[idx], nil
+}
+
 func (a *Config) Read() error {
 	auth, err := config.Parse(a.location)
 	if err != nil {
 		return err
 	}
 
-	if auth.ClientId == "" {
-		return ErrMissingClientId
+	if len(auth.Profiles) == 0 {
+		return ErrMissingProfiles
+	}
+
+	profile := a.profile
+	if profile == "" {
+		profile = auth.Profile
+	}
+	if profile == "" {
+		profile = auth.Profiles[0].Name
+	}
+
+	if _, err := findProfile(auth, profile); err != nil {
+		return err
 	}
 
 	a.conf = auth
+	a.profile = profile
 
 	return nil
 }
 
-func (a *Config) UpdateRefreshToken(refreshToken string) error {
-	a.conf.RefreshToken = refreshToken
+func (a *Config) UseProfile(name string) error {
+	if _, err := a.find(name); err != nil {
+		return err
+	}
+
+	a.profile = name
+	a.conf.Profile = name
+
+	return config.Write(a.location, a.conf)
+}
+
+func (a *Config) UpdateToken(token auth.Token) error {
+	profile, err := a.active()
+	if err != nil {
+		return err
+	}
+
+	profile.RefreshToken = token.Refresh
+	profile.AccessToken = token.Access
+	profile.ExpiresAt = token.ExpiresAt
+	profile.Scopes = token.Scopes
 
 	return config.Write(a.location, a.conf)
 }
 
+func (a *Config) ClearToken() error {
+	return a.UpdateToken(auth.Token{})
+}
+
+func (a *Config) Profile() string {
+	return a.profile
+}
+
+func (a *Config) Profiles() []string {
+	names := make([]string, 0, len(a.conf.Profiles))
+	for _, profile := range a.conf.Profiles {
+		names = append(names, profile.Name)
+	}
+
+	return names
+}
+
 func (a *Config) ClientId() string {
-	return a.conf.ClientId
+	profile, err := a.active()
+	if err != nil {
+		return ""
+	}
+
+	return profile.ClientId
 }
 
 func (a *Config) RefreshToken() string {
-	return a.conf.RefreshToken
+	profile, err := a.active()
+	if err != nil {
+		return ""
+	}
+
+	return profile.RefreshToken
+}
+
+func (a *Config) Redirect() auth.Redirect {
+	redirect := a.conf.Redirect
+
+	host := redirect.Host
+	if host == "" {
+		host = auth.DefaultRedirect.Host
+	}
+
+	ports := slices.Concat(auth.DefaultRedirect.Ports, redirect.FallbackPorts)
+	if redirect.Port != 0 {
+		ports = append([]int{redirect.Port}, redirect.FallbackPorts...)
+	}
+
+	return auth.Redirect{Host: host, Ports: ports}
+}
+
+func (a *Config) AuthTimeout() time.Duration {
+	if a.conf.AuthTimeout <= 0 {
+		return api.DefaultVerificationTimeout
+	}
+
+	return time.Second * time.Duration(a.conf.AuthTimeout)
+}
+
+func (a *Config) RemoteAddress() string {
+	return a.conf.Remote.Address
+}
+
+func (a *Config) RemoteToken() string {
+	return a.conf.Remote.Token
+}
+
+func (a *Config) UpdateRemoteToken(token string) error {
+	a.conf.Remote.Token = token
+
+	return config.Write(a.location, a.conf)
+}
+
+func (a *Config) PartyAddress() string {
+	if a.conf.Party.Address == "" {
+		return party.DefaultAddress
+	}
+
+	return a.conf.Party.Address
+}
+
+func (a *Config) PartySettings() party.Settings {
+	return party.Settings{
+		MaxRequests:   a.conf.Party.MaxRequests,
+		Window:        time.Second * time.Duration(a.conf.Party.Window),
+		AllowExplicit: a.conf.Party.AllowExplicit,
+	}
+}
+
+func (a *Config) Hooks() hooks.Config {
+	conf := hooks.Config{MaxConcurrent: a.conf.Hooks.MaxConcurrent}
+
+	for _, webhook := range a.conf.Hooks.Webhooks {
+		conf.Webhooks = append(conf.Webhooks, hooks.Webhook{
+			Url:     webhook.Url,
+			Events:  webhook.Events,
+			Headers: webhook.Headers,
+			Retries: webhook.Retries,
+			Timeout: time.Second * time.Duration(webhook.Timeout),
+		})
+	}
+
+	for _, exec := range a.conf.Hooks.Exec {
+		conf.Exec = append(conf.Exec, hooks.Exec{
+			Command: exec.Command,
+			Events:  exec.Events,
+			Timeout: time.Second * time.Duration(exec.Timeout),
+		})
+	}
+
+	return conf
+}
+
+func smartRules(rules []config.SmartRule) []smart.Rule {
+	converted := []smart.Rule{}
+	for _, rule := range rules {
+		converted = append(converted, smart.Rule{
+			Source:    smart.Source(rule.Source),
+			Days:      rule.Days,
+			TimeRange: api.TimeRange(rule.TimeRange),
+			Playlist:  rule.Playlist,
+		})
+	}
+
+	return converted
+}
+
+func (a *Config) SmartPlaylists() []smart.Definition {
+	definitions := []smart.Definition{}
+
+	for _, playlist := range a.conf.SmartPlaylists {
+		definitions = append(definitions, smart.Definition{
+			Name:           playlist.Name,
+			Description:    playlist.Description,
+			Public:         playlist.Public,
+			Include:        smartRules(playlist.Include),
+			Exclude:        smartRules(playlist.Exclude),
+			Released:       playlist.Released,
+			NoExplicit:     playlist.NoExplicit,
+			ExcludeArtists: playlist.ExcludeArtists,
+			Sort:           playlists.SortKey(playlist.Sort),
+			Descending:     playlist.Descending,
+			Limit:          playlist.Limit,
+			Refresh:        time.Second * time.Duration(playlist.Refresh),
+		})
+	}
+
+	return definitions
+}
+
+func (a *Config) DataPath(name string) string {
+	return filepath.Join(filepath.Dir(a.location), "data", a.Profile(), name)
+}
+
+func (a *Config) LastFm() *scrobble.LastFm {
+	lastFm := a.conf.Scrobble.LastFm
+	if lastFm.ApiKey == "" || lastFm.Secret == "" {
+		return nil
+	}
+
+	return scrobble.NewLastFm(lastFm.ApiKey, lastFm.Secret, lastFm.SessionKey)
+}
+
+func (a *Config) UpdateLastFmSession(session string) error {
+	a.conf.Scrobble.LastFm.SessionKey = session
+
+	return config.Write(a.location, a.conf)
+}
+
+func (a *Config) Scrobblers() []scrobble.Scrobbler {
+	scrobblers := []scrobble.Scrobbler{}
+
+	if lastFm := a.LastFm(); lastFm != nil && a.conf.Scrobble.LastFm.SessionKey != "" {
+		scrobblers = append(scrobblers, lastFm)
+	}
+
+	if token := a.conf.Scrobble.ListenBrainz.Token; token != "" {
+		scrobblers = append(scrobblers, scrobble.NewListenBrainz(token))
+	}
+
+	return scrobblers
+}
+
+func (a *Config) Token() auth.Token {
+	profile, err := a.active()
+	if err != nil {
+		return auth.Token{}
+	}
+
+	return auth.Token{
+		Access:    profile.AccessToken,
+		Refresh:   profile.RefreshToken,
+		ExpiresAt: profile.ExpiresAt,
+		Scopes:    profile.Scopes,
+	}
+}
+
+func (a *Config) ValidToken() (auth.Token, error) {
+	if _, err := a.active(); err != nil {
+		return auth.Token{}, err
+	}
+
+	token := a.Token()
+
+	if token.Refresh == "" {
+		return auth.Token{}, ErrNotLoggedIn
+	}
+
+	if !token.Expired() {
+		return token, nil
+	}
+
+	token, err := api.RegenerateToken(a.ClientId(), token)
+	if err != nil {
+		return auth.Token{}, err
+	}
+
+	if err := a.UpdateToken(token); err != nil {
+		return auth.Token{}, err
+	}
+
+	return token, nil
 }
 
-func NewConfig(location string) *Config {
-	return &Config{location: location, conf: config.Config{}}
+func NewConfig(location, profile string) *Config {
+	return &Config{location: location, profile: profile, conf: config.Config{}}
 }
@@ -0,0 +1,181 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+	"github.com/franciscosbf/spotify-tui/internals/rpc"
+)
+
+const dialTimeout = 200 * time.Millisecond
+
+var ErrProfileMismatch = errors.New("daemon serves another profile")
+
+type Client struct {
+	rpc  *rpc.Client
+	info Info
+}
+
+func Attach(path, profile string) (*Client, error) {
+	conn, err := rpc.Dial(path, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{rpc: conn}
+
+	if err := c.call(MethodInfo, nil, &c.info); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if c.info.Profile != profile {
+		conn.Close()
+		return nil, ErrProfileMismatch
+	}
+
+	return c, nil
+}
+
+func (c *Client) call(method string, params, result any) error {
+	return decodeError(c.rpc.Call(method, params, result))
+}
+
+func (c *Client) Info() Info {
+	return c.info
+}
+
+func (c *Client) Close() error {
+	return c.rpc.Close()
+}
+
+func (c *Client) Token() (auth.Token, error) {
+	var token Token
+	if err := c.call(MethodToken, nil, &token); err != nil {
+		return auth.Token{}, err
+	}
+
+	return auth.Token{Access: token.Access, ExpiresAt: token.ExpiresAt, Scopes: token.Scopes}, nil
+}
+
+func (c *Client) Status() (status playback.Status, err error) {
+	err = c.call(MethodStatus, nil, &status)
+
+	return status, err
+}
+
+func (c *Client) Subscribe() (playback.Status, <-chan playback.Status, error) {
+	var initial playback.Status
+	if err := c.call(MethodSubscribe, nil, &initial); err != nil {
+		return playback.Status{}, nil, err
+	}
+
+	statuses := make(chan playback.Status)
+
+	go func() {
+		defer close(statuses)
+
+		for notification := range c.rpc.Notifications() {
+			if notification.Method != NotificationChanged {
+				continue
+			}
+
+			var status playback.Status
+			if err := json.Unmarshal(notification.Params, &status); err != nil {
+				continue
+			}
+
+			statuses <- status
+		}
+	}()
+
+	return initial, statuses, nil
+}
+
+func (c *Client) GetUserProfile() (profile api.UserProfile, err error) {
+	err = c.call(MethodProfile, nil, &profile)
+
+	return profile, err
+}
+
+func (c *Client) GetPlaybackState() (state api.PlaybackState, err error) {
+	err = c.call(MethodState, nil, &state)
+
+	return state, err
+}
+
+func (c *Client) GetDevices() (devices []api.Device, err error) {
+	err = c.call(MethodDevices, nil, &devices)
+
+	return devices, err
+}
+
+func (c *Client) TransferPlayback(deviceId string, play bool) error {
+	return c.call(MethodTransfer, transferParams{deviceId, play}, nil)
+}
+
+func (c *Client) Resume() error {
+	return c.call(MethodPlay, nil, nil)
+}
+
+func (c *Client) Pause() error {
+	return c.call(MethodPause, nil, nil)
+}
+
+func (c *Client) Toggle() error {
+	return c.call(MethodToggle, nil, nil)
+}
+
+func (c *Client) SkipToPrevious() error {
+	return c.call(MethodPrevious, nil, nil)
+}
+
+func (c *Client) SkipToNext() error {
+	return c.call(MethodNext, nil, nil)
+}
+
+func (c *Client) EnableShuffle() error {
+	return c.call(MethodShuffle, shuffleParams{true}, nil)
+}
+
+func (c *Client) DisableShuffle() error {
+	return c.call(MethodShuffle, shuffleParams{false}, nil)
+}
+
+func (c *Client) SetRepeatTrack() error {
+	return c.call(MethodRepeat, repeatParams{"track"}, nil)
+}
+
+func (c *Client) SetRepeatContext() error {
+	return c.call(MethodRepeat, repeatParams{"context"}, nil)
+}
+
+func (c *Client) DisableRepeat() error {
+	return c.call(MethodRepeat, repeatParams{"off"}, nil)
+}
+
+func (c *Client) SetVolume(percent int) error {
+	return c.call(MethodVolume, volumeParams{percent}, nil)
+}
+
+func (c *Client) Seek(positionMs int) error {
+	return c.call(MethodSeek, seekParams{positionMs}, nil)
+}
+
+func (c *Client) GetQueue() (queue api.Queue, err error) {
+	err = c.call(MethodQueue, nil, &queue)
+
+	return queue, err
+}
+
+func (c *Client) StartPlayback(options api.PlayOptions) error {
+	return c.call(MethodStart, options, nil)
+}
+
+func (c *Client) AddToQueue(uri string) error {
+	return c.call(MethodEnqueue, enqueueParams{uri}, nil)
+}
@@ -0,0 +1,401 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+	"github.com/franciscosbf/spotify-tui/internals/rpc"
+	"github.com/franciscosbf/spotify-tui/pkg/config"
+)
+
+const DefaultInterval = time.Second
+
+var ErrAlreadyRunning = errors.New("daemon already running")
+
+type service struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+type Daemon struct {
+	conf       *config.Config
+	validToken func() (auth.Token, error)
+	client     *api.Client
+	poller     *playback.Poller
+	server     *rpc.Server
+	services   []service
+
+	authMu sync.Mutex
+
+	subsMu sync.Mutex
+	subs   map[*rpc.Conn]func()
+}
+
+func New(conf *config.Config, interval time.Duration) *Daemon {
+	d := &Daemon{
+		conf:       conf,
+		validToken: conf.ValidToken,
+		client:     api.NewClient(),
+		server:     rpc.NewServer(),
+		subs:       map[*rpc.Conn]func(){},
+	}
+
+	d.poller = playback.NewPoller(func() (api.PlaybackState, error) {
+		var state api.PlaybackState
+
+		err := d.call(func() (err error) {
+			state, err = d.client.GetPlaybackState()
+			return err
+		})
+
+		return state, err
+	}, interval)
+
+	d.register()
+
+	return d
+}
+
+func (d *Daemon) authorize(reload bool) (auth.Token, error) {
+	d.authMu.Lock()
+	defer d.authMu.Unlock()
+
+	if reload {
+		if err := d.conf.Read(); err != nil {
+			return auth.Token{}, err
+		}
+	}
+
+	token, err := d.validToken()
+	if !reload && (errors.Is(err, auth.ErrTokenRejected) || errors.Is(err, config.ErrNotLoggedIn)) {
+		if err := d.conf.Read(); err != nil {
+			return auth.Token{}, err
+		}
+
+		token, err = d.validToken()
+	}
+	if err != nil {
+		return auth.Token{}, err
+	}
+
+	d.client.SetToken(token.Access)
+
+	return token, nil
+}
+
+func (d *Daemon) call(op func() error) error {
+	if _, err := d.authorize(false); err != nil {
+		return err
+	}
+
+	err := op()
+	if !errors.Is(err, api.ErrUnauthorized) {
+		return err
+	}
+
+	if _, err := d.authorize(true); err != nil {
+		return err
+	}
+
+	return op()
+}
+
+func (d *Daemon) Poller() *playback.Poller {
+	return d.poller
+}
+
+func (d *Daemon) Player() api.Player {
+	return player{d}
+}
+
+func (d *Daemon) Go(name string, run func(ctx context.Context) error) {
+	d.services = append(d.services, service{name, run})
+}
+
+func Listen(path string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, ErrAlreadyRunning
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	return listener, nil
+}
+
+func (d *Daemon) Run(ctx context.Context, listener net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for _, service := range d.services {
+		go func() {
+			if err := service.run(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("%s stopped: %s", service.name, err)
+			}
+		}()
+	}
+
+	go d.poller.Run(ctx)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- d.server.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		d.server.Close()
+		return nil
+	case err := <-served:
+		d.server.Close()
+		return err
+	}
+}
+
+func (d *Daemon) handle(method string, handler func(params json.RawMessage) (any, error)) {
+	d.server.Handle(method, func(_ *rpc.Conn, params json.RawMessage) (any, error) {
+		result, err := handler(params)
+		if err != nil {
+			return nil, encodeError(err)
+		}
+
+		return result, nil
+	})
+}
+
+func (d *Daemon) command(method string, op func(params json.RawMessage) error) {
+	d.handle(method, func(params json.RawMessage) (any, error) {
+		if err := op(params); err != nil {
+			return nil, err
+		}
+
+		d.poller.Refresh()
+
+		return true, nil
+	})
+}
+
+func (d *Daemon) register() {
+	p := player{d}
+
+	d.handle(MethodInfo, func(_ json.RawMessage) (any, error) {
+		return Info{Profile: d.conf.Profile(), Pid: os.Getpid()}, nil
+	})
+
+	d.handle(MethodToken, func(_ json.RawMessage) (any, error) {
+		token, err := d.authorize(true)
+		if err != nil {
+			return nil, err
+		}
+
+		return Token{Access: token.Access, ExpiresAt: token.ExpiresAt, Scopes: token.Scopes}, nil
+	})
+
+	d.handle(MethodProfile, func(_ json.RawMessage) (any, error) {
+		return p.GetUserProfile()
+	})
+
+	d.handle(MethodState, func(_ json.RawMessage) (any, error) {
+		snapshot, err := d.poller.Current()
+		if err != nil {
+			return nil, err
+		}
+
+		if !snapshot.Active {
+			return nil, api.ErrNoActiveDevice
+		}
+
+		return snapshot.State, nil
+	})
+
+	d.handle(MethodStatus, func(_ json.RawMessage) (any, error) {
+		return d.status()
+	})
+
+	d.handle(MethodDevices, func(_ json.RawMessage) (any, error) {
+		return p.GetDevices()
+	})
+
+	d.command(MethodTransfer, func(params json.RawMessage) error {
+		var transfer transferParams
+		if err := rpc.DecodeParams(params, &transfer); err != nil {
+			return err
+		}
+
+		return p.TransferPlayback(transfer.DeviceId, transfer.Play)
+	})
+
+	d.command(MethodPlay, func(_ json.RawMessage) error {
+		return p.Resume()
+	})
+
+	d.command(MethodPause, func(_ json.RawMessage) error {
+		return p.Pause()
+	})
+
+	d.command(MethodToggle, func(_ json.RawMessage) error {
+		snapshot, err := d.poller.Current()
+		if err != nil {
+			return err
+		}
+
+		if snapshot.State.IsPlaying {
+			return p.Pause()
+		}
+
+		return p.Resume()
+	})
+
+	d.command(MethodNext, func(_ json.RawMessage) error {
+		return p.SkipToNext()
+	})
+
+	d.command(MethodPrevious, func(_ json.RawMessage) error {
+		return p.SkipToPrevious()
+	})
+
+	d.command(MethodShuffle, func(params json.RawMessage) error {
+		var shuffle shuffleParams
+		if err := rpc.DecodeParams(params, &shuffle); err != nil {
+			return err
+		}
+
+		if shuffle.State {
+			return p.EnableShuffle()
+		}
+
+		return p.DisableShuffle()
+	})
+
+	d.command(MethodRepeat, func(params json.RawMessage) error {
+		var repeat repeatParams
+		if err := rpc.DecodeParams(params, &repeat); err != nil {
+			return err
+		}
+
+		switch repeat.State {
+		case "track":
+			return p.SetRepeatTrack()
+		case "context":
+			return p.SetRepeatContext()
+		case "off":
+			return p.DisableRepeat()
+		}
+
+		return rpc.NewError(rpc.CodeInvalidParams, "unknown repeat state")
+	})
+
+	d.command(MethodVolume, func(params json.RawMessage) error {
+		var volume volumeParams
+		if err := rpc.DecodeParams(params, &volume); err != nil {
+			return err
+		}
+
+		return p.SetVolume(volume.Percent)
+	})
+
+	d.command(MethodSeek, func(params json.RawMessage) error {
+		var seek seekParams
+		if err := rpc.DecodeParams(params, &seek); err != nil {
+			return err
+		}
+
+		return p.Seek(seek.PositionMs)
+	})
+
+	d.handle(MethodQueue, func(_ json.RawMessage) (any, error) {
+		return p.GetQueue()
+	})
+
+	d.command(MethodEnqueue, func(params json.RawMessage) error {
+		var enqueue enqueueParams
+		if err := rpc.DecodeParams(params, &enqueue); err != nil {
+			return err
+		}
+
+		return p.AddToQueue(enqueue.Uri)
+	})
+
+	d.command(MethodStart, func(params json.RawMessage) error {
+		var options api.PlayOptions
+		if err := rpc.DecodeParams(params, &options); err != nil {
+			return err
+		}
+
+		return p.StartPlayback(options)
+	})
+
+	d.server.Handle(MethodSubscribe, d.subscribe)
+	d.server.Handle(MethodUnsubscribe, d.unsubscribe)
+}
+
+func (d *Daemon) subscribe(conn *rpc.Conn, _ json.RawMessage) (any, error) {
+	snapshot, err := d.poller.Current()
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	d.subsMu.Lock()
+	defer d.subsMu.Unlock()
+
+	if _, ok := d.subs[conn]; ok {
+		return snapshot.Status(), nil
+	}
+
+	updates, cancel := d.poller.Subscribe()
+	d.subs[conn] = cancel
+
+	go func() {
+		defer d.unsubscribe(conn, nil)
+
+		for {
+			select {
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+
+				if err := conn.Notify(NotificationChanged, update.Current.Status()); err != nil {
+					return
+				}
+			case <-conn.Done():
+				return
+			}
+		}
+	}()
+
+	return snapshot.Status(), nil
+}
+
+func (d *Daemon) unsubscribe(conn *rpc.Conn, _ json.RawMessage) (any, error) {
+	d.subsMu.Lock()
+	defer d.subsMu.Unlock()
+
+	if cancel, ok := d.subs[conn]; ok {
+		cancel()
+		delete(d.subs, conn)
+	}
+
+	return true, nil
+}
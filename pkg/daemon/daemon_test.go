@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
+	internal "github.com/franciscosbf/spotify-tui/internals/config"
+	"github.com/franciscosbf/spotify-tui/pkg/config"
+)
+
+func writeToken(path, access, refresh string, t *testing.T) {
+	conf := internal.Config{
+		Profile: internal.DefaultProfile,
+		Profiles: []internal.Profile{{
+			Name:         internal.DefaultProfile,
+			ClientId:     "b3j4b5j3",
+			RefreshToken: refresh,
+			AccessToken:  access,
+			ExpiresAt:    time.Now().Add(time.Hour),
+			Scopes:       []string{auth.ScopeTopRead},
+		}},
+	}
+
+	if err := internal.Write(path, conf); err != nil {
+		t.Fatalf("failed to write config: %s", err)
+	}
+}
+
+// newTestDaemon rejects the "rotated" refresh token, as Spotify does once
+// another process has refreshed it.
+func newTestDaemon(t *testing.T) (*Daemon, string) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeToken(path, "stale", "rotated", t)
+
+	conf := config.NewConfig(path, "")
+	if err := conf.Read(); err != nil {
+		t.Fatalf("failed to read config: %s", err)
+	}
+
+	d := New(conf, time.Hour)
+	d.validToken = func() (auth.Token, error) {
+		token := conf.Token()
+		if token.Refresh == "rotated" {
+			return auth.Token{}, auth.ErrTokenRejected
+		}
+
+		return token, nil
+	}
+
+	return d, path
+}
+
+func TestAuthorizeRereadsRotatedToken(t *testing.T) {
+	d, path := newTestDaemon(t)
+
+	writeToken(path, "fresh", "current", t)
+
+	token, err := d.authorize(false)
+	if err != nil {
+		t.Fatalf("failed to authorize: %s", err)
+	}
+
+	if token.Access != "fresh" {
+		t.Fatalf("invalid access token. got=%s, expected=fresh", token.Access)
+	}
+}
+
+func TestTokenMethod(t *testing.T) {
+	d, path := newTestDaemon(t)
+
+	socket := filepath.Join(t.TempDir(), "d.sock")
+
+	listener, err := Listen(socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	go d.server.Serve(listener)
+	defer d.server.Close()
+
+	client, err := Attach(socket, internal.DefaultProfile)
+	if err != nil {
+		t.Fatalf("failed to attach: %s", err)
+	}
+	defer client.Close()
+
+	if _, err := client.Token(); !errors.Is(err, api.ErrUnauthorized) {
+		t.Fatalf("invalid error. got=%v, expected=%v", err, api.ErrUnauthorized)
+	}
+
+	writeToken(path, "fresh", "current", t)
+
+	token, err := client.Token()
+	if err != nil {
+		t.Fatalf("failed to get token: %s", err)
+	}
+
+	if token.Access != "fresh" || token.Refresh != "" {
+		t.Fatalf("invalid token. got=%+v, expected fresh access token only", token)
+	}
+
+	if len(token.Scopes) != 1 || token.Scopes[0] != auth.ScopeTopRead {
+		t.Fatalf("invalid scopes. got=%v, expected=[%s]", token.Scopes, auth.ScopeTopRead)
+	}
+}
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/history"
+)
+
+func (d *Daemon) EnableHistory(path string) {
+	store := history.NewStore(path)
+
+	record := func(play *history.Play) {
+		if play == nil {
+			return
+		}
+
+		if err := store.Record(*play); err != nil {
+			log.Printf("failed to record play: %s", err)
+		}
+	}
+
+	updates, unsubscribe := d.poller.Subscribe()
+
+	d.Go("history", func(ctx context.Context) error {
+		defer unsubscribe()
+
+		var recorder history.Recorder
+		defer func() {
+			record(recorder.Close(time.Now()))
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case update, ok := <-updates:
+				if !ok {
+					return nil
+				}
+
+				record(recorder.Observe(update.Current, time.Now()))
+			}
+		}
+	})
+}
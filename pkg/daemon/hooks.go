@@ -0,0 +1,29 @@
+package daemon
+
+import (
+	"context"
+	"log"
+
+	"github.com/franciscosbf/spotify-tui/internals/hooks"
+)
+
+func (d *Daemon) EnableHooks(config hooks.Config) error {
+	dispatcher, err := hooks.New(config, func(err error) {
+		log.Print(err)
+	})
+	if err != nil {
+		return err
+	}
+
+	updates, unsubscribe := d.poller.Subscribe()
+
+	d.Go("hooks", func(ctx context.Context) error {
+		defer unsubscribe()
+
+		dispatcher.Run(ctx, updates)
+
+		return nil
+	})
+
+	return nil
+}
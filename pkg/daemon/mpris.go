@@ -0,0 +1,45 @@
+package daemon
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/franciscosbf/spotify-tui/internals/mpris"
+)
+
+func (d *Daemon) EnableMpris() error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return err
+	}
+
+	service, err := mpris.Export(conn, d.Player())
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	updates, unsubscribe := d.poller.Subscribe()
+
+	d.Go("mpris", func(ctx context.Context) error {
+		defer conn.Close()
+		defer service.Close()
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case update, ok := <-updates:
+				if !ok {
+					return nil
+				}
+
+				service.Update(update.Current)
+			}
+		}
+	})
+
+	return nil
+}
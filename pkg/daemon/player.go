@@ -0,0 +1,113 @@
+package daemon
+
+import "github.com/franciscosbf/spotify-tui/internals/api"
+
+type player struct {
+	d *Daemon
+}
+
+func (p player) GetUserProfile() (profile api.UserProfile, err error) {
+	err = p.d.call(func() (err error) {
+		profile, err = p.d.client.GetUserProfile()
+		return err
+	})
+
+	return profile, err
+}
+
+func (p player) GetPlaybackState() (api.PlaybackState, error) {
+	snapshot, err := p.d.poller.Current()
+	if err != nil {
+		return api.PlaybackState{}, err
+	}
+
+	if !snapshot.Active {
+		return api.PlaybackState{}, api.ErrNoActiveDevice
+	}
+
+	return snapshot.State, nil
+}
+
+func (p player) GetDevices() (devices []api.Device, err error) {
+	err = p.d.call(func() (err error) {
+		devices, err = p.d.client.GetDevices()
+		return err
+	})
+
+	return devices, err
+}
+
+func (p player) TransferPlayback(deviceId string, play bool) error {
+	return p.d.call(func() error {
+		return p.d.client.TransferPlayback(deviceId, play)
+	})
+}
+
+func (p player) Resume() error {
+	return p.d.call(p.d.client.Resume)
+}
+
+func (p player) Pause() error {
+	return p.d.call(p.d.client.Pause)
+}
+
+func (p player) SkipToPrevious() error {
+	return p.d.call(p.d.client.SkipToPrevious)
+}
+
+func (p player) SkipToNext() error {
+	return p.d.call(p.d.client.SkipToNext)
+}
+
+func (p player) EnableShuffle() error {
+	return p.d.call(p.d.client.EnableShuffle)
+}
+
+func (p player) DisableShuffle() error {
+	return p.d.call(p.d.client.DisableShuffle)
+}
+
+func (p player) SetRepeatTrack() error {
+	return p.d.call(p.d.client.SetRepeatTrack)
+}
+
+func (p player) SetRepeatContext() error {
+	return p.d.call(p.d.client.SetRepeatContext)
+}
+
+func (p player) DisableRepeat() error {
+	return p.d.call(p.d.client.DisableRepeat)
+}
+
+func (p player) SetVolume(percent int) error {
+	return p.d.call(func() error {
+		return p.d.client.SetVolume(percent)
+	})
+}
+
+func (p player) Seek(positionMs int) error {
+	return p.d.call(func() error {
+		return p.d.client.Seek(positionMs)
+	})
+}
+
+func (p player) GetQueue() (queue api.Queue, err error) {
+	err = p.d.call(func() (err error) {
+		queue, err = p.d.client.GetQueue()
+		return err
+	})
+
+	return queue, err
+}
+
+func (p player) StartPlayback(options api.PlayOptions) error {
+	return p.d.call(func() error {
+		return p.d.client.StartPlayback(options)
+	})
+}
+
+func (p player) AddToQueue(uri string) error {
+	return p.d.call(func() error {
+		return p.d.client.AddToQueue(uri)
+	})
+}
@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/internals/rpc"
+	"github.com/franciscosbf/spotify-tui/pkg/config"
+)
+
+const socketName = "spotify-tui.sock"
+
+const (
+	CodeFailure        = -32000
+	CodeUnauthorized   = -32001
+	CodeNoActiveDevice = -32002
+)
+
+const (
+	MethodInfo        = "daemon.info"
+	MethodToken       = "auth.token"
+	MethodProfile     = "user.profile"
+	MethodState       = "player.state"
+	MethodStatus      = "player.status"
+	MethodDevices     = "player.devices"
+	MethodTransfer    = "player.transfer"
+	MethodPlay        = "player.play"
+	MethodPause       = "player.pause"
+	MethodToggle      = "player.toggle"
+	MethodNext        = "player.next"
+	MethodPrevious    = "player.previous"
+	MethodShuffle     = "player.shuffle"
+	MethodRepeat      = "player.repeat"
+	MethodVolume      = "player.volume"
+	MethodSeek        = "player.seek"
+	MethodQueue       = "player.queue"
+	MethodEnqueue     = "player.enqueue"
+	MethodStart       = "player.start"
+	MethodSubscribe   = "events.subscribe"
+	MethodUnsubscribe = "events.unsubscribe"
+
+	NotificationChanged = "player.changed"
+)
+
+type Info struct {
+	Profile string `json:"profile"`
+	Pid     int    `json:"pid"`
+}
+
+type Token struct {
+	Access    string    `json:"access_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Scopes    []string  `json:"scopes"`
+}
+
+type shuffleParams struct {
+	State bool `json:"state"`
+}
+
+type repeatParams struct {
+	State string `json:"state"`
+}
+
+type volumeParams struct {
+	Percent int `json:"percent"`
+}
+
+type seekParams struct {
+	PositionMs int `json:"position_ms"`
+}
+
+type enqueueParams struct {
+	Uri string `json:"uri"`
+}
+
+type transferParams struct {
+	DeviceId string `json:"device_id"`
+	Play     bool   `json:"play"`
+}
+
+func SocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, socketName)
+	}
+
+	return filepath.Join(os.TempDir(), fmt.Sprintf("spotify-tui-%d.sock", os.Getuid()))
+}
+
+func encodeError(err error) error {
+	var rpcErr *rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	switch {
+	case errors.Is(err, api.ErrNoActiveDevice):
+		return rpc.NewError(CodeNoActiveDevice, err.Error())
+	case errors.Is(err, api.ErrUnauthorized),
+		errors.Is(err, config.ErrNotLoggedIn),
+		errors.Is(err, auth.ErrTokenRejected),
+		errors.Is(err, auth.ErrTokenInvalidResponse):
+		return rpc.NewError(CodeUnauthorized, err.Error())
+	}
+
+	var errResponse api.ErrResponse
+	if errors.As(err, &errResponse) {
+		return rpc.NewErrorWithData(CodeFailure, err.Error(), errResponse)
+	}
+
+	return rpc.NewError(CodeFailure, err.Error())
+}
+
+func decodeError(err error) error {
+	var rpcErr *rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+
+	switch rpcErr.Code {
+	case CodeNoActiveDevice:
+		return api.ErrNoActiveDevice
+	case CodeUnauthorized:
+		return fmt.Errorf("%w: %s", api.ErrUnauthorized, rpcErr.Message)
+	case CodeFailure:
+		var errResponse api.ErrResponse
+		if err := json.Unmarshal(rpcErr.Data, &errResponse); err == nil && errResponse.Status != 0 {
+			return errResponse
+		}
+
+		return errors.New(rpcErr.Message)
+	}
+
+	return rpcErr
+}
@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"context"
+
+	"github.com/franciscosbf/spotify-tui/internals/playback"
+	"github.com/franciscosbf/spotify-tui/internals/remote"
+)
+
+func (d *Daemon) status() (playback.Status, error) {
+	snapshot, err := d.poller.Current()
+	if err != nil {
+		return playback.Status{}, err
+	}
+
+	return snapshot.Status(), nil
+}
+
+func (d *Daemon) EnableRemote(address, token string) (string, error) {
+	if token == "" {
+		return "", remote.ErrMissingToken
+	}
+
+	server, err := remote.Listen(address)
+	if err != nil {
+		return "", err
+	}
+
+	handler := remote.Handler(d.Player(), d.status, token)
+
+	d.Go("remote control", func(ctx context.Context) error {
+		go func() {
+			<-ctx.Done()
+			server.Close()
+		}()
+
+		return server.Serve(handler)
+	})
+
+	return server.Url(), nil
+}
@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"context"
+	"log"
+
+	"github.com/franciscosbf/spotify-tui/internals/scrobble"
+)
+
+func (d *Daemon) EnableScrobbling(backends []scrobble.Scrobbler, queuePath string) error {
+	queue, err := scrobble.OpenQueue(queuePath)
+	if err != nil {
+		return err
+	}
+
+	service := scrobble.NewService(backends, queue, func(err error) {
+		log.Printf("scrobble failed: %s", err)
+	})
+
+	updates, unsubscribe := d.poller.Subscribe()
+
+	d.Go("scrobbler", func(ctx context.Context) error {
+		defer unsubscribe()
+
+		service.Run(ctx, updates, scrobble.DefaultRetryInterval)
+
+		return nil
+	})
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/franciscosbf/spotify-tui/internals/history"
+	"github.com/franciscosbf/spotify-tui/internals/smart"
+)
+
+const smartCheckInterval = time.Minute
+
+func (d *Daemon) refreshSmartPlaylists(definitions []smart.Definition, state *smart.State, plays smart.PlayLog) {
+	entries, err := state.Entries()
+	if err != nil {
+		log.Printf("failed to read smart playlists: %s", err)
+		return
+	}
+
+	now := time.Now()
+
+	var evaluator *smart.Evaluator
+	for _, definition := range definitions {
+		if !definition.Due(entries[definition.Name], now) {
+			continue
+		}
+
+		if evaluator == nil {
+			evaluator = smart.NewEvaluator(d.client, plays, now)
+		}
+
+		err := d.call(func() error {
+			_, err := evaluator.Refresh(definition, state)
+			return err
+		})
+		if err == nil {
+			continue
+		}
+
+		log.Printf("failed to refresh smart playlist %s: %s", definition.Name, err)
+
+		if err := state.Fail(definition.Name, now); err != nil {
+			log.Printf("failed to record smart playlist failure: %s", err)
+		}
+	}
+}
+
+func (d *Daemon) EnableSmartPlaylists(definitions []smart.Definition, statePath, historyPath string) {
+	state := smart.NewState(statePath)
+	plays := smart.NewHistoryLog(history.NewStore(historyPath))
+
+	d.Go("smart playlists", func(ctx context.Context) error {
+		ticker := time.NewTicker(smartCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			d.refreshSmartPlaylists(definitions, state, plays)
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m model) openAccounts() model {
+	profiles := m.conf.Profiles()
+	m.accounts.reset(0, len(profiles))
+
+	for i, profile := range profiles {
+		if profile == m.conf.Profile() {
+			m.accounts.reset(i, len(profiles))
+		}
+	}
+
+	m.view = accounts
+
+	return m
+}
+
+func (m model) updateAccounts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	profiles := m.conf.Profiles()
+
+	switch {
+	case key.Matches(msg, listKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, listKm.back):
+		m.view = player
+	case key.Matches(msg, listKm.up):
+		m.accounts.move(-1, len(profiles))
+	case key.Matches(msg, listKm.down):
+		m.accounts.move(1, len(profiles))
+	case key.Matches(msg, listKm.enter):
+		m.view = player
+		if profile := profiles[m.accounts.cursor]; profile != m.conf.Profile() {
+			return m, switchProfile(profile, m.conf)
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) viewAccounts() string {
+	rows := []string{}
+	for _, profile := range m.conf.Profiles() {
+		if profile == m.conf.Profile() {
+			profile = fmt.Sprintf("%s %s", profile, activeProfileStyle.Render("●"))
+		}
+		rows = append(rows, profile)
+	}
+
+	return fmt.Sprintf("%s\n\n%s",
+		titleStyle.Render("Switch account"),
+		m.accounts.render(rows))
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m model) startAuthorization() (model, tea.Cmd) {
+	if m.authCancel != nil {
+		m.authCancel()
+		m.authCancel = nil
+	}
+
+	m.authorization.Close()
+
+	m.authAttempt++
+	m.authErr = nil
+	m.copied = false
+
+	return m, startAuthorization(m.conf, m.authScopes, m.authAttempt)
+}
+
+func (m model) waitAuthorization() (model, tea.Cmd) {
+	timeout := m.conf.AuthTimeout()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	m.authCancel = cancel
+	m.authDeadline = time.Now().Add(timeout)
+
+	return m, waitForToken(ctx, m.authorization, m.conf, m.authAttempt)
+}
+
+func (m model) enterAuthConfirmation() (model, tea.Cmd) {
+	if m.view == authConfirmation {
+		return m, nil
+	}
+
+	m.view = authConfirmation
+
+	return m, incrementAwaitDots(m.awaitDots)
+}
+
+func (m model) updateAuthConfirmation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	waiting := m.authErr == nil
+	hasUrl := m.authorization.Url() != ""
+
+	loggedIn := m.token.Access != ""
+
+	switch {
+	case key.Matches(msg, authKm.back) && loggedIn:
+		if m.authCancel != nil {
+			m.authCancel()
+			m.authCancel = nil
+		}
+		m.authAttempt++
+		m.pendingScopes = nil
+		m.view = player
+	case key.Matches(msg, authKm.quit):
+		if m.authCancel != nil {
+			m.authCancel()
+		}
+		return m, tea.Quit
+	case key.Matches(msg, authKm.open) && waiting && hasUrl:
+		return m, openBrowser(m.authorization)
+	case key.Matches(msg, authKm.copy) && waiting && hasUrl:
+		return m, copyToClipboard(m.authorization.Url())
+	case key.Matches(msg, authKm.cancel) && waiting:
+		if m.authCancel != nil {
+			m.authCancel()
+		}
+	case key.Matches(msg, authKm.retry):
+		return m.startAuthorization()
+	}
+
+	return m, nil
+}
+
+func (m model) authKeyMap() authKeyMap {
+	waiting := m.authErr == nil
+	hasUrl := m.authorization.Url() != ""
+
+	km := authKm
+	if m.token.Access != "" {
+		km.quit = listKm.quit
+	} else {
+		km.back.SetEnabled(false)
+	}
+	km.open.SetEnabled(waiting && hasUrl)
+	km.copy.SetEnabled(waiting && hasUrl)
+	km.cancel.SetEnabled(waiting)
+
+	return km
+}
+
+func (m model) viewAuthConfirmation() string {
+	if m.authErr != nil {
+		return fmt.Sprintf("%s %s\n%s",
+			errorStyle.Render("Authorization failed:"),
+			errorMsgStyle.Render(m.authErr.Error()),
+			awaitStyle.Render("Press r to try again"))
+	}
+
+	dots := ""
+	for _, style := range dotColorsStyle[:m.awaitDots] {
+		dots += style.Render(".")
+	}
+
+	remaining := max(time.Until(m.authDeadline).Round(time.Second), 0)
+	minutes := int(remaining.Minutes())
+	seconds := int(remaining.Seconds()) % 60
+
+	copied := ""
+	if m.copied {
+		copied = copiedStyle.Render(" (copied)")
+	}
+
+	return fmt.Sprintf(
+		"%s\n%s%s\n%s%s",
+		awaitStyle.Render("I sent an authorization request!"),
+		awaitStyle.Render("Check your browser, I'm waiting for your"),
+		dots,
+		countdownStyle.Render(fmt.Sprintf("%d:%02d left", minutes, seconds)),
+		copied)
+}
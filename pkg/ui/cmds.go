@@ -1,12 +1,25 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/franciscosbf/spotify-tui/internals/api"
 	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/internals/browser"
+	"github.com/franciscosbf/spotify-tui/internals/history"
+	"github.com/franciscosbf/spotify-tui/internals/party"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+	"github.com/franciscosbf/spotify-tui/internals/ranks"
+	"github.com/franciscosbf/spotify-tui/internals/remote"
+	"github.com/franciscosbf/spotify-tui/internals/snapshots"
 	"github.com/franciscosbf/spotify-tui/pkg/config"
+	"github.com/franciscosbf/spotify-tui/pkg/daemon"
 )
 
 func welcomeMsg() tea.Cmd {
@@ -35,10 +48,8 @@ func incrementAwaitDots(dots int) tea.Cmd {
 	})
 }
 
-func expirationAlert(expiresIn time.Duration) tea.Cmd {
-	d := expiresIn - (expiresIn / 6)
-
-	return tea.Tick(d, func(_ time.Time) tea.Msg {
+func expirationAlert(token auth.Token) tea.Cmd {
+	return tea.Tick(token.RefreshIn(), func(_ time.Time) tea.Msg {
 		return expiredTokenMsg(struct{}{})
 	})
 }
@@ -53,14 +64,64 @@ func readConfig(authConf *config.Config) tea.Cmd {
 	}
 }
 
-func genToken(clientId string, authConf *config.Config) tea.Cmd {
+func startAuthorization(authConf *config.Config, scopes []string, attempt int) tea.Cmd {
 	return func() tea.Msg {
-		token, err := api.GenerateToken(clientId)
+		clientId := authConf.ClientId()
+		redirect := authConf.Redirect()
+
+		if !browser.Available() {
+			authorization := api.NewManualAuthorization(clientId, redirect, scopes)
+			return authStartedMsg{authorization: authorization, attempt: attempt, manual: true}
+		}
+
+		authorization, err := api.NewAuthorization(clientId, redirect, scopes)
 		if err != nil {
+			return authFailedMsg{err: err, attempt: attempt}
+		}
+
+		if err := authorization.OpenBrowser(); err != nil {
+			authorization.Close()
+			authorization = api.NewManualAuthorization(clientId, redirect, scopes)
+			return authStartedMsg{authorization: authorization, attempt: attempt, manual: true}
+		}
+
+		return authStartedMsg{authorization: authorization, attempt: attempt}
+	}
+}
+
+func openBrowser(authorization api.Authorization) tea.Cmd {
+	return func() tea.Msg {
+		if err := authorization.OpenBrowser(); err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		return nil
+	}
+}
+
+func waitForToken(ctx context.Context, authorization api.Authorization, authConf *config.Config, attempt int) tea.Cmd {
+	return func() tea.Msg {
+		token, err := authorization.WaitForToken(ctx)
+		if err != nil {
+			return authFailedMsg{err: err, attempt: attempt}
+		}
+
+		if err = authConf.UpdateToken(token); err != nil {
 			return errMsg(err)
 		}
 
-		if err = authConf.UpdateRefreshToken(token.Refresh); err != nil {
+		return newTokenMsg{token: token}
+	}
+}
+
+func tokenFromRedirect(authorization api.Authorization, redirectUrl string, authConf *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		token, err := authorization.TokenFromRedirect(redirectUrl)
+		if err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		if err = authConf.UpdateToken(token); err != nil {
 			return errMsg(err)
 		}
 
@@ -75,14 +136,30 @@ func regenToken(authConf *config.Config) tea.Cmd {
 			err   error
 		)
 
+		if client, err := daemon.Attach(daemon.SocketPath(), authConf.Profile()); err == nil {
+			defer client.Close()
+
+			if token, err = client.Token(); err != nil {
+				return failedRegenTokenMsg{}
+			}
+
+			if err = authConf.Read(); err != nil {
+				return errMsg(err)
+			}
+
+			token.Refresh = authConf.RefreshToken()
+
+			return newTokenMsg{token: token, refreshed: true}
+		}
+
 		clientId := authConf.ClientId()
-		refreshToken := authConf.RefreshToken()
+		previous := authConf.Token()
 
-		if token, err = api.RegenerateToken(clientId, refreshToken); err != nil {
+		if token, err = api.RegenerateToken(clientId, previous); err != nil {
 			return failedRegenTokenMsg{}
 		}
 
-		if err = authConf.UpdateRefreshToken(token.Refresh); err != nil {
+		if err = authConf.UpdateToken(token); err != nil {
 			return errMsg(err)
 		}
 
@@ -90,6 +167,32 @@ func regenToken(authConf *config.Config) tea.Cmd {
 	}
 }
 
+func reuseToken(token auth.Token) tea.Cmd {
+	return func() tea.Msg {
+		return newTokenMsg{token: token, refreshed: true}
+	}
+}
+
+func switchProfile(profile string, authConf *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		if err := authConf.UseProfile(profile); err != nil {
+			return errMsg(err)
+		}
+
+		return profileSwitchedMsg(struct{}{})
+	}
+}
+
+func logout(authConf *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		if err := authConf.ClearToken(); err != nil {
+			return errMsg(err)
+		}
+
+		return loggedOutMsg(struct{}{})
+	}
+}
+
 func requestGenToken() tea.Cmd {
 	return func() tea.Msg {
 		return genTokenMsg(struct{}{})
@@ -108,8 +211,94 @@ func dismissWarnErr(warnErrId int) tea.Cmd {
 	})
 }
 
+func attachDaemon(profile string) tea.Cmd {
+	return func() tea.Msg {
+		client, err := daemon.Attach(daemon.SocketPath(), profile)
+		if err != nil {
+			return daemonAttachedMsg{}
+		}
+
+		return daemonAttachedMsg{client}
+	}
+}
+
+func startParty(authConf *config.Config, actions clientActions) tea.Cmd {
+	return func() tea.Msg {
+		server, err := remote.Listen(authConf.PartyAddress())
+		if err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		p := party.New(authConf.PartySettings())
+		code := party.NewCode()
+
+		go server.Serve(party.Handler(p, actions.client, code))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go p.Run(ctx, actions.player, party.DefaultQueueInterval)
+
+		return partyStartedMsg{&partySession{
+			requests: p,
+			server:   server,
+			ctx:      ctx,
+			cancel:   cancel,
+			link:     server.Url() + code + "/",
+		}}
+	}
+}
+
+func waitPartyChange(session *partySession) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-session.requests.Changes():
+			return partyChangedMsg{session}
+		case <-session.ctx.Done():
+			return nil
+		}
+	}
+}
+
+func loadStats(path string, period history.Period) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+
+		plays, err := history.NewStore(path).Plays(period.Since(now), time.Time{})
+		if err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		return statsLoadedMsg{history.Summarize(plays, period.Buckets(now)), period}
+	}
+}
+
 type clientActions struct {
 	client *api.Client
+	player api.Player
+	daemon *daemon.Client
+}
+
+func newClientActions() clientActions {
+	client := api.NewClient()
+
+	return clientActions{client: client, player: client}
+}
+
+func (c clientActions) attach(client *daemon.Client) clientActions {
+	if c.daemon != nil {
+		c.daemon.Close()
+	}
+
+	c.daemon = client
+	c.player = c.client
+	if client != nil {
+		c.player = client
+	}
+
+	return c
+}
+
+func (c clientActions) attached() bool {
+	return c.daemon != nil
 }
 
 func (c clientActions) directOperation(op func() error) tea.Cmd {
@@ -132,7 +321,7 @@ func (c clientActions) setToken(token string) {
 
 func (c clientActions) getUserProfile() tea.Cmd {
 	return func() tea.Msg {
-		profile, err := c.client.GetUserProfile()
+		profile, err := c.player.GetUserProfile()
 		if err != nil {
 			return newWarnErrMsg(err)
 		}
@@ -141,38 +330,299 @@ func (c clientActions) getUserProfile() tea.Cmd {
 	}
 }
 
+func (c clientActions) loadRecent(before string) tea.Cmd {
+	return func() tea.Msg {
+		page, err := c.client.GetRecentlyPlayed(api.Cursors{Before: before}, 50)
+
+		return recentLoadedMsg{page, before, err}
+	}
+}
+
+func (c clientActions) loadTop(store *ranks.Store, timeRange api.TimeRange) tea.Cmd {
+	return func() tea.Msg {
+		tracks, err := c.client.GetTopTracks(timeRange, topLimit)
+		if err != nil {
+			return topLoadedMsg{err: err}
+		}
+
+		artists, err := c.client.GetTopArtists(timeRange, topLimit)
+		if err != nil {
+			return topLoadedMsg{err: err}
+		}
+
+		trackIds := make([]string, 0, len(tracks))
+		for _, track := range tracks {
+			trackIds = append(trackIds, track.Id)
+		}
+
+		artistIds := make([]string, 0, len(artists))
+		for _, artist := range artists {
+			artistIds = append(artistIds, artist.Id)
+		}
+
+		now := time.Now()
+
+		previousTracks, err := store.Swap(string(timeRange)+"/tracks", trackIds, now)
+		if err != nil {
+			return topLoadedMsg{err: err}
+		}
+
+		previousArtists, err := store.Swap(string(timeRange)+"/artists", artistIds, now)
+		if err != nil {
+			return topLoadedMsg{err: err}
+		}
+
+		return topLoadedMsg{timeRange: timeRange, list: topList{
+			tracks:        tracks,
+			artists:       artists,
+			trackChanges:  ranks.Compare(previousTracks, trackIds),
+			artistChanges: ranks.Compare(previousArtists, artistIds),
+			trackSince:    previousTracks.At,
+			artistSince:   previousArtists.At,
+		}}
+	}
+}
+
+func (c clientActions) createPlaylist(userId string, details api.PlaylistDetails, uris func() ([]string, error)) tea.Cmd {
+	return func() tea.Msg {
+		tracks, err := uris()
+		if err != nil {
+			return playlistCreatedMsg{err: err}
+		}
+
+		playlist, err := c.client.CreatePlaylist(userId, details)
+		if err != nil {
+			return playlistCreatedMsg{err: err}
+		}
+
+		if _, err := c.client.AddPlaylistTracks(playlist.Id, tracks); err != nil {
+			return playlistCreatedMsg{err: err}
+		}
+
+		return playlistCreatedMsg{playlist: playlist}
+	}
+}
+
+func (c clientActions) createTracksPlaylist(userId string, details api.PlaylistDetails, tracks []api.Track) tea.Cmd {
+	return c.createPlaylist(userId, details, func() ([]string, error) {
+		uris := make([]string, 0, len(tracks))
+		for _, track := range tracks {
+			uris = append(uris, track.Uri)
+		}
+
+		return uris, nil
+	})
+}
+
+func (c clientActions) createArtistsPlaylist(userId string, details api.PlaylistDetails, artists []api.Artist) tea.Cmd {
+	return c.createPlaylist(userId, details, func() ([]string, error) {
+		uris := make([]string, 0, len(artists))
+		for _, artist := range artists {
+			tracks, err := c.client.GetArtistTopTracks(artist.Id)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(tracks) > 0 {
+				uris = append(uris, tracks[0].Uri)
+			}
+		}
+
+		return uris, nil
+	})
+}
+
+func (c clientActions) loadLibrary() tea.Cmd {
+	return func() tea.Msg {
+		playlists, err := c.client.GetMyPlaylists()
+
+		return libraryLoadedMsg{playlists, err}
+	}
+}
+
+func (c clientActions) loadPlaylist(id string, store *snapshots.Store) tea.Cmd {
+	return func() tea.Msg {
+		playlist, err := c.client.GetPlaylist(id)
+		if err != nil {
+			return playlistLoadedMsg{err: err}
+		}
+
+		items, err := c.client.GetPlaylistItems(id)
+		if err != nil {
+			return playlistLoadedMsg{err: err}
+		}
+
+		_, err = store.Record(id, snapshots.FromItems(playlist, items, time.Now()))
+
+		return playlistLoadedMsg{playlist: playlist, items: items, snapshotErr: err}
+	}
+}
+
+func (c clientActions) recordSnapshots(playlists []api.Playlist, store *snapshots.Store) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := store.RecordAll(c.client, playlists, time.Now()); err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		return nil
+	}
+}
+
+func loadSnapshots(store *snapshots.Store, id string) tea.Cmd {
+	return func() tea.Msg {
+		history, err := store.History(id)
+
+		return snapshotsLoadedMsg{playlistId: id, snapshots: history, err: err}
+	}
+}
+
+func (c clientActions) editPlaylist(playlist api.Playlist, snapshotId string, edit playlists.Edit, undo bool) tea.Cmd {
+	return func() tea.Msg {
+		_, err := playlists.Apply(c.client, playlist.Id, snapshotId, edit)
+
+		return playlistEditedMsg{playlist, edit, undo, err}
+	}
+}
+
+func (c clientActions) addToPlaylist(playlist api.Playlist, uri string) tea.Cmd {
+	return func() tea.Msg {
+		items, err := c.client.GetPlaylistItems(playlist.Id)
+		if err != nil {
+			return playlistEditedMsg{playlist: playlist, err: err}
+		}
+
+		edit := playlists.NewAdd(items, uri, len(items))
+		_, err = playlists.Apply(c.client, playlist.Id, playlist.SnapshotId, edit)
+
+		return playlistEditedMsg{playlist, edit, false, err}
+	}
+}
+
+func (c clientActions) rewritePlaylist(playlist api.Playlist, before, after []api.PlaylistItem) tea.Cmd {
+	return func() tea.Msg {
+		_, err := playlists.Rewrite(c.client, playlist.Id, playlist.SnapshotId, before, after)
+
+		return playlistRewrittenMsg{playlist, err}
+	}
+}
+
+func (c clientActions) loadMerge(sources []api.Playlist) tea.Cmd {
+	return func() tea.Msg {
+		lists := make([][]api.PlaylistItem, 0, len(sources))
+		for _, playlist := range sources {
+			items, err := c.client.GetPlaylistItems(playlist.Id)
+			if err != nil {
+				return mergeLoadedMsg{err: err}
+			}
+
+			lists = append(lists, items)
+		}
+
+		return mergeLoadedMsg{items: lists}
+	}
+}
+
+func (c clientActions) exportPlaylist(playlist api.Playlist, path string) tea.Cmd {
+	return func() tea.Msg {
+		format, err := playlists.FormatFromPath(path)
+		if err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		items, err := c.client.GetPlaylistItems(playlist.Id)
+		if err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		entries := make([]playlists.Entry, 0, len(items))
+		for _, item := range items {
+			entries = append(entries, playlists.FromTrack(item.Track))
+		}
+
+		file, err := os.Create(path)
+		if err != nil {
+			return newWarnErrMsg(err)
+		}
+		defer file.Close()
+
+		if err := playlists.Write(file, format, playlist.Name, entries); err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		if err := file.Close(); err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		return newNoticeMsg(fmt.Sprintf("Exported %d tracks to %s", len(entries), filepath.Base(path)))
+	}
+}
+
+func (c clientActions) importPlaylist(path string) tea.Cmd {
+	return func() tea.Msg {
+		format, err := playlists.FormatFromPath(path)
+		if err != nil {
+			return importResolvedMsg{err: err}
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return importResolvedMsg{err: err}
+		}
+		defer file.Close()
+
+		name, entries, err := playlists.Read(file, format)
+		if err != nil {
+			return importResolvedMsg{err: err}
+		}
+
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+
+		matches, err := playlists.Resolve(c.client, entries, nil)
+
+		return importResolvedMsg{name: name, matches: matches, err: err}
+	}
+}
+
+func (c clientActions) startPlayback(options api.PlayOptions) tea.Cmd {
+	return c.directOperation(func() error {
+		return c.player.StartPlayback(options)
+	})
+}
+
 func (c clientActions) resume() tea.Cmd {
-	return c.directOperation(c.client.Resume)
+	return c.directOperation(c.player.Resume)
 }
 
 func (c clientActions) pause() tea.Cmd {
-	return c.directOperation(c.client.Pause)
+	return c.directOperation(c.player.Pause)
 }
 
 func (c clientActions) skipToPrevious() tea.Cmd {
-	return c.directOperation(c.client.SkipToPrevious)
+	return c.directOperation(c.player.SkipToPrevious)
 }
 
 func (c clientActions) skipToNext() tea.Cmd {
-	return c.directOperation(c.client.SkipToNext)
+	return c.directOperation(c.player.SkipToNext)
 }
 
 func (c clientActions) enableShuffle() tea.Cmd {
-	return c.directOperation(c.client.EnableShuffle)
+	return c.directOperation(c.player.EnableShuffle)
 }
 
 func (c clientActions) disableShuffle() tea.Cmd {
-	return c.directOperation(c.client.DisableShuffle)
+	return c.directOperation(c.player.DisableShuffle)
 }
 
 func (c clientActions) setRepeatTrack() tea.Cmd {
-	return c.directOperation(c.client.SetRepeatTrack)
+	return c.directOperation(c.player.SetRepeatTrack)
 }
 
 func (c clientActions) setRepeatContext() tea.Cmd {
-	return c.directOperation(c.client.SetRepeatContext)
+	return c.directOperation(c.player.SetRepeatContext)
 }
 
 func (c clientActions) disableRepeat() tea.Cmd {
-	return c.directOperation(c.client.DisableRepeat)
+	return c.directOperation(c.player.DisableRepeat)
 }
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
+)
+
+var viewScopes = map[view][]string{
+	player:     {auth.ScopeModifyPlaybackState},
+	moderation: {auth.ScopeReadPlaybackState, auth.ScopeModifyPlaybackState},
+	recent:     {auth.ScopeReadRecentlyPlayed},
+	top:        {auth.ScopeTopRead},
+	library: {
+		auth.ScopePlaylistReadPrivate, auth.ScopePlaylistReadCollaborative,
+		auth.ScopePlaylistModifyPublic, auth.ScopePlaylistModifyPrivate,
+	},
+}
+
+var scopeDescriptions = map[string]string{
+	auth.ScopeReadPlaybackState:         "read your playback state",
+	auth.ScopeModifyPlaybackState:       "control your playback",
+	auth.ScopeReadCurrentlyPlaying:      "read what you're playing",
+	auth.ScopeReadPrivate:               "read your profile",
+	auth.ScopePlaylistReadPrivate:       "read your private playlists",
+	auth.ScopeReadRecentlyPlayed:        "see what you've recently played",
+	auth.ScopeTopRead:                   "read your top artists and tracks",
+	auth.ScopePlaylistReadCollaborative: "read collaborative playlists",
+	auth.ScopePlaylistModifyPublic:      "edit your public playlists",
+	auth.ScopePlaylistModifyPrivate:     "edit your private playlists",
+	auth.ScopeLibraryRead:               "read your liked songs and albums",
+	auth.ScopeLibraryModify:             "save songs and albums to your library",
+	auth.ScopeFollowRead:                "see the artists you follow",
+	auth.ScopeFollowModify:              "follow artists",
+}
+
+func (m model) navigate(v view) (model, tea.Cmd) {
+	if m, ok := m.requireScopes(v, viewScopes[v]); !ok {
+		return m, nil
+	}
+
+	return m.show(v)
+}
+
+func (m model) requireScopes(v view, scopes []string) (model, bool) {
+	missing := auth.MissingScopes(m.token.Scopes, scopes)
+	if len(missing) == 0 {
+		return m, true
+	}
+
+	m.pendingView = v
+	m.pendingScopes = missing
+	m.view = consent
+
+	return m, false
+}
+
+func (m model) show(v view) (model, tea.Cmd) {
+	switch v {
+	case accounts:
+		return m.openAccounts(), nil
+	case moderation:
+		return m.openParty()
+	case stats:
+		return m.openStats()
+	case recent:
+		return m.openRecent()
+	case top:
+		return m.openTop()
+	case library:
+		return m.openLibrary()
+	}
+
+	m.view = v
+
+	return m, nil
+}
+
+func (m model) updateConsent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, consentKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, consentKm.back):
+		m.pendingScopes = nil
+		m.view = player
+	case key.Matches(msg, consentKm.enter):
+		m.authScopes = auth.MergeScopes(m.token.Scopes, m.pendingScopes)
+		return m.startAuthorization()
+	}
+
+	return m, nil
+}
+
+func (m model) viewConsent() string {
+	descriptions := []string{}
+	for _, scope := range m.pendingScopes {
+		description, ok := scopeDescriptions[scope]
+		if !ok {
+			description = scope
+		}
+		descriptions = append(descriptions, description)
+	}
+
+	return fmt.Sprintf("%s\n%s\n\n%s",
+		awaitStyle.Render("I need a few more permissions to go there:"),
+		scopeStyle.Render(strings.Join(descriptions, ", ")),
+		awaitStyle.Render("Press enter to authorize them"))
+}
@@ -25,17 +25,30 @@ var defaultKm = defaultKeyMap{
 
 type playerKeyMap struct {
 	defaultKeyMap
-	left  key.Binding
-	right key.Binding
-	enter key.Binding
+	left      key.Binding
+	right     key.Binding
+	enter     key.Binding
+	accounts  key.Binding
+	logout    key.Binding
+	party     key.Binding
+	stats     key.Binding
+	recent    key.Binding
+	top       key.Binding
+	playlists key.Binding
+	more      key.Binding
 }
 
 func (k playerKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.quit, k.left, k.right, k.enter}
+	return []key.Binding{k.quit, k.left, k.right, k.enter, k.more}
 }
 
 func (k playerKeyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{}
+	return [][]key.Binding{
+		{k.quit, k.left, k.right},
+		{k.enter, k.accounts, k.logout},
+		{k.party, k.stats, k.recent},
+		{k.top, k.playlists, k.more},
+	}
 }
 
 var playerKm = playerKeyMap{
@@ -52,6 +65,38 @@ var playerKm = playerKeyMap{
 		key.WithKeys("enter"),
 		key.WithHelp("↵", "press"),
 	),
+	accounts: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "accounts"),
+	),
+	logout: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "logout"),
+	),
+	party: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "party"),
+	),
+	stats: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "stats"),
+	),
+	recent: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "recently played"),
+	),
+	top: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "your top"),
+	),
+	playlists: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "playlists"),
+	),
+	more: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "more"),
+	),
 }
 
 type ackKeyMap struct {
@@ -74,3 +119,696 @@ func (k ackKeyMap) ShortHelp() []key.Binding {
 func (k ackKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{}
 }
+
+type listKeyMap struct {
+	quit  key.Binding
+	back  key.Binding
+	up    key.Binding
+	down  key.Binding
+	enter key.Binding
+}
+
+var listKm = listKeyMap{
+	quit: key.NewBinding(
+		key.WithKeys("q"),
+		key.WithHelp("q", "quit"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+	up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑", "up"),
+	),
+	down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓", "down"),
+	),
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "select"),
+	),
+}
+
+func (k listKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.quit, k.back, k.up, k.down, k.enter}
+}
+
+func (k listKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type manualAuthKeyMap struct {
+	quit  key.Binding
+	enter key.Binding
+	link  key.Binding
+	copy  key.Binding
+}
+
+var manualAuthKm = manualAuthKeyMap{
+	quit: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "quit"),
+	),
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "submit"),
+	),
+	link: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "link/qr code"),
+	),
+	copy: key.NewBinding(
+		key.WithKeys("ctrl+y"),
+		key.WithHelp("ctrl+y", "copy link"),
+	),
+}
+
+func (k manualAuthKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.quit, k.enter, k.link, k.copy}
+}
+
+func (k manualAuthKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type authKeyMap struct {
+	defaultKeyMap
+	back   key.Binding
+	open   key.Binding
+	copy   key.Binding
+	cancel key.Binding
+	retry  key.Binding
+}
+
+var authKm = authKeyMap{
+	defaultKeyMap: defaultKm,
+	back:          listKm.back,
+	open: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open browser"),
+	),
+	copy: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy link"),
+	),
+	cancel: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "cancel"),
+	),
+	retry: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "retry"),
+	),
+}
+
+func (k authKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.quit, k.back, k.open, k.copy, k.cancel, k.retry}
+}
+
+func (k authKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type consentKeyMap struct {
+	quit  key.Binding
+	back  key.Binding
+	enter key.Binding
+}
+
+var consentKm = consentKeyMap{
+	quit:  listKm.quit,
+	back:  listKm.back,
+	enter: ackKm.enter,
+}
+
+func (k consentKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.quit, k.back, k.enter}
+}
+
+func (k consentKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+var loginKm = ackKeyMap{
+	defaultKeyMap: defaultKm,
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "log in"),
+	),
+}
+
+type partyKeyMap struct {
+	quit    key.Binding
+	back    key.Binding
+	up      key.Binding
+	down    key.Binding
+	approve key.Binding
+	reject  key.Binding
+	link    key.Binding
+	stop    key.Binding
+}
+
+var partyKm = partyKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/↓", "move"),
+	),
+	down: listKm.down,
+	approve: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y/x", "approve/reject"),
+	),
+	reject: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "reject"),
+	),
+	link: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "qr code"),
+	),
+	stop: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "stop"),
+	),
+}
+
+func (k partyKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.up, k.approve, k.link, k.stop}
+}
+
+func (k partyKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type statsKeyMap struct {
+	quit   key.Binding
+	back   key.Binding
+	up     key.Binding
+	down   key.Binding
+	page   key.Binding
+	period key.Binding
+}
+
+var statsKm = statsKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	page: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "page"),
+	),
+	period: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "range"),
+	),
+}
+
+func (k statsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.quit, k.back, k.up, k.page, k.period}
+}
+
+func (k statsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type recentKeyMap struct {
+	quit    key.Binding
+	back    key.Binding
+	up      key.Binding
+	down    key.Binding
+	replay  key.Binding
+	context key.Binding
+	reload  key.Binding
+	add     key.Binding
+}
+
+var recentKm = recentKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	replay: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "replay"),
+	),
+	context: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "context"),
+	),
+	reload: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "reload"),
+	),
+	add: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "add"),
+	),
+}
+
+func (k recentKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.up, k.replay, k.context, k.reload, k.add}
+}
+
+func (k recentKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type topKeyMap struct {
+	quit     key.Binding
+	back     key.Binding
+	up       key.Binding
+	down     key.Binding
+	page     key.Binding
+	period   key.Binding
+	playlist key.Binding
+	add      key.Binding
+}
+
+var topKm = topKeyMap{
+	quit:   listKm.quit,
+	back:   listKm.back,
+	up:     partyKm.up,
+	down:   listKm.down,
+	page:   statsKm.page,
+	period: statsKm.period,
+	playlist: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "save"),
+	),
+	add: recentKm.add,
+}
+
+func (k topKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.up, k.page, k.period, k.playlist, k.add}
+}
+
+func (k topKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type playlistsKeyMap struct {
+	quit     key.Binding
+	back     key.Binding
+	up       key.Binding
+	down     key.Binding
+	enter    key.Binding
+	create   key.Binding
+	details  key.Binding
+	undo     key.Binding
+	export   key.Binding
+	load     key.Binding
+	mark     key.Binding
+	merge    key.Binding
+	followed key.Binding
+}
+
+var playlistsKm = playlistsKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "open"),
+	),
+	create: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "new"),
+	),
+	details: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit"),
+	),
+	undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	),
+	export: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "export"),
+	),
+	load: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "import"),
+	),
+	mark: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "mark"),
+	),
+	merge: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "merge"),
+	),
+	followed: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "followed"),
+	),
+}
+
+func (k playlistsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.enter, k.create, k.export, k.load, k.merge, k.followed}
+}
+
+func (k playlistsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+var pickKm = listKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "add here"),
+	),
+}
+
+var followedKm = listKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "history"),
+	),
+}
+
+type playlistKeyMap struct {
+	quit      key.Binding
+	back      key.Binding
+	up        key.Binding
+	down      key.Binding
+	play      key.Binding
+	move      key.Binding
+	remove    key.Binding
+	details   key.Binding
+	undo      key.Binding
+	revisions key.Binding
+	tools     key.Binding
+}
+
+var playlistKm = playlistKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	play: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "play"),
+	),
+	move: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "reorder"),
+	),
+	remove: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "remove"),
+	),
+	details: playlistsKm.details,
+	undo:    playlistsKm.undo,
+	revisions: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "history"),
+	),
+	tools: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "tidy"),
+	),
+}
+
+func (k playlistKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.play, k.move, k.remove, k.tools, k.revisions}
+}
+
+func (k playlistKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type movingKeyMap struct {
+	up    key.Binding
+	down  key.Binding
+	back  key.Binding
+	enter key.Binding
+}
+
+var movingKm = movingKeyMap{
+	up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/↓", "move row"),
+	),
+	down: listKm.down,
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+	enter: key.NewBinding(
+		key.WithKeys("enter", "m"),
+		key.WithHelp("↵", "save order"),
+	),
+}
+
+func (k movingKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.up, k.back, k.enter}
+}
+
+func (k movingKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+var confirmKm = consentKeyMap{
+	quit: listKm.quit,
+	back: key.NewBinding(
+		key.WithKeys("n", "esc"),
+		key.WithHelp("n", "cancel"),
+	),
+	enter: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "confirm"),
+	),
+}
+
+type formKeyMap struct {
+	back   key.Binding
+	next   key.Binding
+	toggle key.Binding
+	enter  key.Binding
+}
+
+var formKm = formKeyMap{
+	back: movingKm.back,
+	next: key.NewBinding(
+		key.WithKeys("tab", "shift+tab"),
+		key.WithHelp("tab", "next field"),
+	),
+	toggle: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "toggle"),
+	),
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "save"),
+	),
+}
+
+func (k formKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.next, k.toggle, k.enter}
+}
+
+func (k formKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type promptKeyMap struct {
+	back  key.Binding
+	enter key.Binding
+}
+
+var promptKm = promptKeyMap{
+	back: movingKm.back,
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "confirm"),
+	),
+}
+
+func (k promptKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.enter}
+}
+
+func (k promptKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type reviewKeyMap struct {
+	back      key.Binding
+	up        key.Binding
+	down      key.Binding
+	include   key.Binding
+	candidate key.Binding
+	enter     key.Binding
+}
+
+var reviewKm = reviewKeyMap{
+	back: movingKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	include: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "include"),
+	),
+	candidate: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "next guess"),
+	),
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "create"),
+	),
+}
+
+func (k reviewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.include, k.candidate, k.enter}
+}
+
+func (k reviewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type revisionsKeyMap struct {
+	quit  key.Binding
+	back  key.Binding
+	up    key.Binding
+	down  key.Binding
+	base  key.Binding
+	enter key.Binding
+}
+
+var revisionsKm = revisionsKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	base: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "pin base"),
+	),
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "compare"),
+	),
+}
+
+func (k revisionsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.up, k.down, k.base, k.enter}
+}
+
+func (k revisionsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type diffKeyMap struct {
+	quit    key.Binding
+	back    key.Binding
+	up      key.Binding
+	down    key.Binding
+	restore key.Binding
+}
+
+var diffKm = diffKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	restore: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "restore removed"),
+	),
+}
+
+var readOnlyDiffKm = diffKeyMap{
+	quit: diffKm.quit,
+	back: diffKm.back,
+	up:   diffKm.up,
+	down: diffKm.down,
+}
+
+func (k diffKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.up, k.down, k.restore}
+}
+
+func (k diffKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+type toolsKeyMap struct {
+	quit  key.Binding
+	back  key.Binding
+	up    key.Binding
+	down  key.Binding
+	order key.Binding
+	enter key.Binding
+}
+
+var toolsKm = toolsKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	order: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "flip order"),
+	),
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "preview"),
+	),
+}
+
+func (k toolsKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.up, k.down, k.order, k.enter}
+}
+
+func (k toolsKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
+
+var filterKm = formKeyMap{
+	back:   formKm.back,
+	next:   formKm.next,
+	toggle: formKm.toggle,
+	enter:  toolsKm.enter,
+}
+
+type previewKeyMap struct {
+	quit  key.Binding
+	back  key.Binding
+	up    key.Binding
+	down  key.Binding
+	enter key.Binding
+}
+
+var previewKm = previewKeyMap{
+	quit: listKm.quit,
+	back: listKm.back,
+	up:   partyKm.up,
+	down: listKm.down,
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↵", "apply"),
+	),
+}
+
+func (k previewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.back, k.up, k.down, k.enter}
+}
+
+func (k previewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{}
+}
@@ -0,0 +1,594 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+)
+
+const libraryRowWidth = 44
+
+type undoEntry struct {
+	playlist api.Playlist
+	edit     playlists.Edit
+}
+
+type detailsForm struct {
+	name          textinput.Model
+	description   textinput.Model
+	public        bool
+	collaborative bool
+	focus         int
+	playlist      api.Playlist
+	returnTo      view
+}
+
+type libraryState struct {
+	playlists   []api.Playlist
+	loaded      bool
+	rows        listing
+	followed    listing
+	current     api.Playlist
+	items       []api.PlaylistItem
+	itemsLoaded bool
+	tracks      listing
+	moveFrom    int
+	moving      bool
+	confirming  bool
+	busy        bool
+	undo        []undoEntry
+	pickUri     string
+	pickReturn  view
+	form        detailsForm
+	path        textinput.Model
+	exporting   bool
+	transferred api.Playlist
+	resolving   bool
+	importName  string
+	matches     []playlists.Match
+	included    []bool
+	reviewRows  listing
+}
+
+func newDetailsInput(placeholder string, limit int) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.CharLimit = limit
+	input.Width = 40
+
+	return input
+}
+
+func (m model) openLibrary() (model, tea.Cmd) {
+	m.view = library
+	m.library.loaded = false
+
+	return m, m.actions.loadLibrary()
+}
+
+func (m model) editablePlaylists() []api.Playlist {
+	editable := []api.Playlist{}
+	for _, playlist := range m.library.playlists {
+		if playlist.Owner.Id == m.profile.Id || playlist.Collaborative {
+			editable = append(editable, playlist)
+		}
+	}
+
+	return editable
+}
+
+func (m model) followedPlaylists() []api.Playlist {
+	followed := []api.Playlist{}
+	for _, playlist := range m.library.playlists {
+		if playlist.Owner.Id != m.profile.Id && !playlist.Collaborative {
+			followed = append(followed, playlist)
+		}
+	}
+
+	return followed
+}
+
+func (m model) libraryLoaded(msg libraryLoadedMsg) (model, tea.Cmd) {
+	if msg.err != nil {
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	m.library.playlists = msg.playlists
+	m.library.loaded = true
+	m.library.rows.reset(m.library.rows.cursor, len(m.editablePlaylists()))
+	m.library.followed.reset(m.library.followed.cursor, len(m.followedPlaylists()))
+
+	return m, m.actions.recordSnapshots(msg.playlists, m.snapshotStore())
+}
+
+func (m model) openEditor(playlist api.Playlist) (model, tea.Cmd) {
+	m.view = editor
+	m.library.current = playlist
+	m.library.items = nil
+	m.library.itemsLoaded = false
+	m.library.moving = false
+	m.library.confirming = false
+	m.library.tracks.reset(0, 0)
+
+	return m, m.actions.loadPlaylist(playlist.Id, m.snapshotStore())
+}
+
+func (m model) playlistLoaded(msg playlistLoadedMsg) (model, tea.Cmd) {
+	if msg.playlist.Id != "" && msg.playlist.Id != m.library.current.Id {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	m.library.current = msg.playlist
+	m.library.items = msg.items
+	m.library.itemsLoaded = true
+	m.library.moving = false
+	m.library.tracks.reset(m.library.tracks.cursor, len(m.library.items))
+
+	if msg.snapshotErr != nil {
+		return m, func() tea.Msg { return newWarnErrMsg(msg.snapshotErr) }
+	}
+
+	return m, nil
+}
+
+func (m model) applyEdit(playlist api.Playlist, snapshotId string, edit playlists.Edit, undo bool) (model, tea.Cmd) {
+	m.library.busy = true
+
+	return m, m.actions.editPlaylist(playlist, snapshotId, edit, undo)
+}
+
+func (m model) playlistEdited(msg playlistEditedMsg) (model, tea.Cmd) {
+	m.library.busy = false
+
+	var cmds []tea.Cmd
+
+	if msg.err != nil {
+		cmds = append(cmds, func() tea.Msg { return newWarnErrMsg(msg.err) })
+	} else if msg.undo {
+		cmds = append(cmds, func() tea.Msg {
+			return newNoticeMsg(fmt.Sprintf("Undid %s in %s", msg.edit.Describe(), msg.playlist.Name))
+		})
+	} else {
+		m.library.undo = append(m.library.undo, undoEntry{msg.playlist, msg.edit})
+		if msg.edit.Kind == playlists.Added {
+			cmds = append(cmds, func() tea.Msg {
+				return newNoticeMsg(fmt.Sprintf("Added to %s", msg.playlist.Name))
+			})
+		}
+	}
+
+	switch {
+	case (m.view == editor || m.view == revisions || m.view == diff) && m.library.current.Id == msg.playlist.Id:
+		cmds = append(cmds, m.actions.loadPlaylist(msg.playlist.Id, m.snapshotStore()))
+	case m.view == library:
+		cmds = append(cmds, m.actions.loadLibrary())
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) libraryCreated(msg playlistCreatedMsg) (model, tea.Cmd) {
+	m.library.busy = false
+
+	if msg.err != nil {
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	return m, tea.Batch(m.actions.loadLibrary(), func() tea.Msg {
+		return newNoticeMsg(fmt.Sprintf("Created %s", msg.playlist.Name))
+	})
+}
+
+func (m model) undoEdit() (model, tea.Cmd) {
+	if m.library.busy || len(m.library.undo) == 0 {
+		return m, nil
+	}
+
+	last := m.library.undo[len(m.library.undo)-1]
+	m.library.undo = m.library.undo[:len(m.library.undo)-1]
+
+	snapshotId := ""
+	if m.library.current.Id == last.playlist.Id {
+		snapshotId = m.library.current.SnapshotId
+	}
+
+	return m.applyEdit(last.playlist, snapshotId, last.edit.Undo(), true)
+}
+
+func (m model) pickPlaylist(uri string) (model, tea.Cmd) {
+	if uri == "" {
+		return m, nil
+	}
+
+	m.library.pickUri = uri
+	m.library.pickReturn = m.view
+
+	return m.navigate(library)
+}
+
+func (m model) openDetails(playlist api.Playlist) (model, tea.Cmd) {
+	form := detailsForm{
+		name:          newDetailsInput("Name", 100),
+		description:   newDetailsInput("Description", 300),
+		public:        playlist.Public,
+		collaborative: playlist.Collaborative,
+		playlist:      playlist,
+		returnTo:      m.view,
+	}
+	form.name.SetValue(playlist.Name)
+	form.description.SetValue(playlist.Description)
+
+	m.library.form = form
+	m.view = details
+
+	return m, m.library.form.name.Focus()
+}
+
+func (m model) updateLibrary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	editable := m.editablePlaylists()
+
+	switch {
+	case key.Matches(msg, playlistsKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, playlistsKm.back):
+		m.view = player
+		if m.library.pickUri != "" {
+			m.view = m.library.pickReturn
+			m.library.pickUri = ""
+		}
+	case key.Matches(msg, playlistsKm.up):
+		m.library.rows.move(-1, len(editable))
+	case key.Matches(msg, playlistsKm.down):
+		m.library.rows.move(1, len(editable))
+	case key.Matches(msg, playlistsKm.enter):
+		if m.library.rows.cursor >= len(editable) {
+			return m, nil
+		}
+
+		playlist := editable[m.library.rows.cursor]
+
+		if uri := m.library.pickUri; uri != "" {
+			m.view = m.library.pickReturn
+			m.library.pickUri = ""
+
+			m.library.busy = true
+
+			return m, m.actions.addToPlaylist(playlist, uri)
+		}
+
+		return m.openEditor(playlist)
+	case m.library.pickUri != "":
+	case key.Matches(msg, playlistsKm.create):
+		return m.openDetails(api.Playlist{})
+	case key.Matches(msg, playlistsKm.details):
+		if m.library.rows.cursor < len(editable) {
+			return m.openDetails(editable[m.library.rows.cursor])
+		}
+	case key.Matches(msg, playlistsKm.undo):
+		return m.undoEdit()
+	case key.Matches(msg, playlistsKm.export):
+		if m.library.rows.cursor < len(editable) {
+			return m.openTransfer(true, editable[m.library.rows.cursor])
+		}
+	case key.Matches(msg, playlistsKm.load):
+		return m.openTransfer(false, api.Playlist{})
+	case key.Matches(msg, playlistsKm.mark):
+		if m.library.rows.cursor < len(editable) {
+			m = m.toggleMark(editable[m.library.rows.cursor])
+		}
+	case key.Matches(msg, playlistsKm.merge):
+		return m.openMerge()
+	case key.Matches(msg, playlistsKm.followed):
+		m.view = followed
+	}
+
+	return m, nil
+}
+
+func (m model) updateEditor(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	items := m.library.items
+	cursor := m.library.tracks.cursor
+
+	if m.library.confirming {
+		switch {
+		case key.Matches(msg, confirmKm.quit):
+			return m, tea.Quit
+		case key.Matches(msg, confirmKm.back):
+			m.library.confirming = false
+		case key.Matches(msg, confirmKm.enter):
+			m.library.confirming = false
+			if cursor >= len(items) {
+				return m, nil
+			}
+
+			return m.applyEdit(m.library.current, m.library.current.SnapshotId, playlists.NewRemove(items, cursor), false)
+		}
+
+		return m, nil
+	}
+
+	if m.library.moving {
+		switch {
+		case key.Matches(msg, movingKm.up), key.Matches(msg, movingKm.down):
+			delta := 1
+			if key.Matches(msg, movingKm.up) {
+				delta = -1
+			}
+
+			to := cursor + delta
+			if to < 0 || to >= len(items) {
+				return m, nil
+			}
+
+			m.library.items = playlists.Reorder(items, playlists.MoveRow(cursor, to))
+			m.library.tracks.move(delta, len(items))
+		case key.Matches(msg, movingKm.back):
+			m.library.moving = false
+			if cursor != m.library.moveFrom {
+				m.library.items = playlists.Reorder(items, playlists.MoveRow(cursor, m.library.moveFrom))
+				m.library.tracks.reset(m.library.moveFrom, len(items))
+			}
+		case key.Matches(msg, movingKm.enter):
+			m.library.moving = false
+			if cursor == m.library.moveFrom {
+				return m, nil
+			}
+
+			return m.applyEdit(m.library.current, m.library.current.SnapshotId, playlists.Edit{
+				Kind: playlists.Moved,
+				Move: playlists.MoveRow(m.library.moveFrom, cursor),
+			}, false)
+		}
+
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, playlistKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, playlistKm.back):
+		return m.openLibrary()
+	case key.Matches(msg, playlistKm.up):
+		m.library.tracks.move(-1, len(items))
+	case key.Matches(msg, playlistKm.down):
+		m.library.tracks.move(1, len(items))
+	case key.Matches(msg, playlistKm.details):
+		return m.openDetails(m.library.current)
+	case key.Matches(msg, playlistKm.undo):
+		return m.undoEdit()
+	case key.Matches(msg, playlistKm.revisions):
+		return m.openRevisions()
+	case key.Matches(msg, playlistKm.tools):
+		return m.openTools()
+	case cursor >= len(items) || m.library.busy:
+	case key.Matches(msg, playlistKm.play):
+		return m, m.actions.startPlayback(api.PlayOptions{
+			ContextUri: m.library.current.Uri,
+			OffsetUri:  items[cursor].Track.Uri,
+		})
+	case key.Matches(msg, playlistKm.move):
+		m.library.moving = true
+		m.library.moveFrom = cursor
+	case key.Matches(msg, playlistKm.remove):
+		m.library.confirming = true
+	}
+
+	return m, nil
+}
+
+func (m model) updateDetails(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form := &m.library.form
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, formKm.back):
+			m.view = form.returnTo
+			return m, nil
+		case key.Matches(msg, formKm.next):
+			delta := 1
+			if msg.String() == "shift+tab" {
+				delta = 3
+			}
+			form.focus = (form.focus + delta) % 4
+			form.name.Blur()
+			form.description.Blur()
+			switch form.focus {
+			case 0:
+				return m, form.name.Focus()
+			case 1:
+				return m, form.description.Focus()
+			}
+			return m, nil
+		case key.Matches(msg, formKm.toggle) && form.focus >= 2:
+			if form.focus == 2 {
+				form.public = !form.public
+				form.collaborative = form.collaborative && !form.public
+			} else {
+				form.collaborative = !form.collaborative
+				form.public = form.public && !form.collaborative
+			}
+			return m, nil
+		case key.Matches(msg, formKm.enter):
+			return m.saveDetails()
+		}
+	}
+
+	var cmd tea.Cmd
+	switch form.focus {
+	case 0:
+		form.name, cmd = form.name.Update(msg)
+	case 1:
+		form.description, cmd = form.description.Update(msg)
+	}
+
+	return m, cmd
+}
+
+func (m model) saveDetails() (model, tea.Cmd) {
+	form := m.library.form
+
+	updated := api.PlaylistDetails{
+		Name:          strings.TrimSpace(form.name.Value()),
+		Description:   strings.TrimSpace(form.description.Value()),
+		Public:        form.public,
+		Collaborative: form.collaborative,
+	}
+	if updated.Name == "" || m.library.busy {
+		return m, nil
+	}
+
+	m.view = form.returnTo
+
+	if form.playlist.Id == "" {
+		if m.profile.Id == "" {
+			return m, func() tea.Msg { return newWarnErrMsg(errNoProfile) }
+		}
+
+		m.library.busy = true
+
+		return m, m.actions.createPlaylist(m.profile.Id, updated, func() ([]string, error) {
+			return nil, nil
+		})
+	}
+
+	previous := api.PlaylistDetails{
+		Name:          form.playlist.Name,
+		Description:   form.playlist.Description,
+		Public:        form.playlist.Public,
+		Collaborative: form.playlist.Collaborative,
+	}
+	if updated == previous {
+		return m, nil
+	}
+
+	return m.applyEdit(form.playlist, "", playlists.Edit{
+		Kind:     playlists.Described,
+		Details:  updated,
+		Previous: previous,
+	}, false)
+}
+
+func playlistFlags(playlist api.Playlist) string {
+	switch {
+	case playlist.Collaborative:
+		return "collaborative"
+	case playlist.Public:
+		return "public"
+	}
+
+	return "private"
+}
+
+func (m model) viewLibrary() string {
+	title := titleStyle.Render("Your playlists")
+	if m.library.pickUri != "" {
+		title = titleStyle.Render("Add to playlist")
+	}
+
+	if !m.library.loaded {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Loading..."))
+	}
+
+	editable := m.editablePlaylists()
+	if len(editable) == 0 {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("No playlists you can edit"))
+	}
+
+	rows := []string{}
+	for _, playlist := range editable {
+		name := playlist.Name
+		if m.tools.marked[playlist.Id] {
+			name = "◆ " + name
+		}
+
+		rows = append(rows, fmt.Sprintf("%-*s %s", libraryRowWidth-12,
+			truncate(name, libraryRowWidth-12),
+			countdownStyle.Render(fmt.Sprintf("%4d tracks", playlist.Tracks.Total))))
+	}
+
+	return fmt.Sprintf("%s\n\n%s", title, m.library.rows.render(rows))
+}
+
+func (m model) viewEditor() string {
+	current := m.library.current
+
+	title := titleStyle.Render(truncate(current.Name, libraryRowWidth))
+
+	summary := countdownStyle.Render(fmt.Sprintf("%s · %d tracks", playlistFlags(current), len(m.library.items)))
+
+	switch {
+	case m.library.busy:
+		summary = countdownStyle.Render("Saving...")
+	case m.library.moving:
+		summary = activeProfileStyle.Render("Moving, press enter to save the new order")
+	}
+
+	if !m.library.itemsLoaded {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Loading..."))
+	}
+
+	if len(m.library.items) == 0 {
+		return fmt.Sprintf("%s\n%s\n%s", title, summary, awaitStyle.Render("This playlist is empty"))
+	}
+
+	if m.library.confirming {
+		item := m.library.items[m.library.tracks.cursor]
+		summary = warnStyle.Render(truncate(fmt.Sprintf("Remove %s?", item.Track.Name), libraryRowWidth))
+	}
+
+	rows := []string{}
+	for i, item := range m.library.items {
+		name := item.Track.Name
+		if len(item.Track.Artists) > 0 {
+			name += " - " + item.Track.Artists[0].Name
+		}
+
+		prefix := ""
+		if m.library.moving && i == m.library.tracks.cursor {
+			prefix = "↕ "
+		}
+
+		rows = append(rows, truncate(prefix+name, libraryRowWidth))
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, summary, m.library.tracks.render(rows))
+}
+
+func (m model) viewDetails() string {
+	form := m.library.form
+
+	title := "New playlist"
+	if form.playlist.Id != "" {
+		title = "Edit playlist"
+	}
+
+	checkbox := func(label string, checked bool, focused bool) string {
+		box := "[ ]"
+		if checked {
+			box = "[x]"
+		}
+
+		line := fmt.Sprintf("%s %s", box, label)
+		if focused {
+			return selectedRowStyle.Render("› " + line)
+		}
+
+		return rowStyle.Render("  " + line)
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n%s\n%s\n%s",
+		titleStyle.Render(title),
+		form.name.View(),
+		form.description.View(),
+		checkbox("public", form.public, form.focus == 2),
+		checkbox("collaborative", form.collaborative, form.focus == 3))
+}
@@ -0,0 +1,49 @@
+package ui
+
+import "strings"
+
+const listingHeight = 6
+
+type listing struct {
+	cursor int
+	offset int
+}
+
+func (l *listing) move(delta, size int) {
+	if size == 0 {
+		l.cursor, l.offset = 0, 0
+		return
+	}
+
+	l.cursor = min(max(l.cursor+delta, 0), size-1)
+
+	if l.cursor < l.offset {
+		l.offset = l.cursor
+	} else if l.cursor >= l.offset+listingHeight {
+		l.offset = l.cursor - listingHeight + 1
+	}
+}
+
+func (l *listing) reset(cursor, size int) {
+	l.cursor, l.offset = 0, 0
+	l.move(cursor, size)
+}
+
+func (l listing) render(rows []string) string {
+	end := min(l.offset+listingHeight, len(rows))
+	lines := make([]string, 0, listingHeight)
+
+	for i := l.offset; i < end; i++ {
+		if i == l.cursor {
+			lines = append(lines, selectedRowStyle.Render("› "+rows[i]))
+		} else {
+			lines = append(lines, rowStyle.Render("  "+rows[i]))
+		}
+	}
+
+	for len(lines) < listingHeight {
+		lines = append(lines, "")
+	}
+
+	return strings.Join(lines, "\n")
+}
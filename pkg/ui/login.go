@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/skip2/go-qrcode"
+)
+
+func newRedirectInput() textinput.Model {
+	input := textinput.New()
+	input.CharLimit = 2048
+	input.Width = 48
+
+	return input
+}
+
+func copyToClipboard(content string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52.New(content)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+			seq = seq.Screen()
+		}
+
+		if _, err := seq.WriteTo(os.Stderr); err != nil {
+			return newWarnErrMsg(err)
+		}
+
+		return copiedMsg(struct{}{})
+	}
+}
+
+func renderQr(content string) string {
+	code, err := qrcode.New(content, qrcode.Low)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimSuffix(code.ToSmallString(false), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = qrStyle.Render(line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m model) updateAuthManual(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, manualAuthKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, manualAuthKm.link):
+		m.showLink = !m.showLink
+	case key.Matches(msg, manualAuthKm.copy):
+		return m, copyToClipboard(m.authorization.Url())
+	case key.Matches(msg, manualAuthKm.enter):
+		redirectUrl := m.redirectInput.Value()
+		if redirectUrl == "" {
+			return m, nil
+		}
+		m.redirectInput.Reset()
+		return m, tokenFromRedirect(m.authorization, redirectUrl, m.conf)
+	default:
+		var cmd tea.Cmd
+		m.redirectInput, cmd = m.redirectInput.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m model) viewAuthManual() string {
+	copied := ""
+	if m.copied {
+		copied = copiedStyle.Render(" (copied)")
+	}
+
+	return fmt.Sprintf("%s\n%s%s\n\n%s",
+		awaitStyle.Render("No browser here! Authorize on another device"),
+		awaitStyle.Render("and paste the URL you were redirected to"),
+		copied,
+		m.redirectInput.View())
+}
+
+func breakLines(content string, width int) string {
+	if width <= 0 {
+		return content
+	}
+
+	lines := []string{}
+	for len(content) > width {
+		lines = append(lines, content[:width])
+		content = content[width:]
+	}
+
+	return strings.Join(append(lines, content), "\n")
+}
+
+func (m model) viewAuthLink() string {
+	return fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s",
+		titleStyle.Render("Open this link to authorize:"),
+		breakLines(m.authorization.Url(), m.width),
+		renderQr(m.authorization.Url()),
+		m.help.View(manualAuthKm))
+}
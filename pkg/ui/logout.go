@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/internals/uri"
+)
+
+func (m model) loggedOut() model {
+	m = m.stopParty()
+	m.token = auth.Token{}
+	m.profile = api.UserProfile{}
+	m.topLists = nil
+	m.library = libraryState{}
+	m.authScopes = nil
+	m.pendingScopes = nil
+	m.actions.setToken("")
+	m.view = loggedOut
+
+	return m
+}
+
+func (m model) updateLogout(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, consentKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, consentKm.back):
+		m.view = player
+	case key.Matches(msg, consentKm.enter):
+		return m, logout(m.conf)
+	}
+
+	return m, nil
+}
+
+func (m model) updateLoggedOut(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, defaultKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, ackKm.enter):
+		return m.startAuthorization()
+	}
+
+	return m, nil
+}
+
+func (m model) viewLogout() string {
+	return fmt.Sprintf("%s\n%s",
+		awaitStyle.Render(fmt.Sprintf("Log out from %s?", m.conf.Profile())),
+		awaitStyle.Render("Stored tokens will be wiped from your config"))
+}
+
+func (m model) viewLoggedOut() string {
+	return fmt.Sprintf("%s\n\n%s\n%s",
+		ackStyle.Render("You're logged out!"),
+		awaitStyle.Render("To fully revoke access, remove the app at"),
+		linkStyle.Render(uri.APPS))
+}
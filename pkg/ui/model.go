@@ -1,15 +1,19 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/franciscosbf/spotify-tui/internals/api"
 	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/internals/history"
 	"github.com/franciscosbf/spotify-tui/pkg/config"
 )
 
@@ -18,17 +22,37 @@ type view int
 const (
 	initialization view = iota
 	authConfirmation
+	authManual
 	authAck
 	player
+	accounts
+	consent
+	logoutConfirmation
+	loggedOut
+	moderation
+	stats
+	recent
+	top
+	library
+	followed
+	editor
+	details
+	transfer
+	review
+	revisions
+	diff
+	tools
+	filtering
+	preview
 	err
 )
 
 type repeatState int
 
 const (
-	track repeatState = iota
-	context
-	off
+	repeatTrack repeatState = iota
+	repeatContext
+	repeatOff
 	nRepeatStates
 )
 
@@ -52,20 +76,56 @@ var buttonSymbols = []string{
 
 type model struct {
 	help           help.Model
+	redirectInput  textinput.Model
 	actions        clientActions
+	authorization  api.Authorization
 	profile        api.UserProfile
 	currentWarnErr warnErrMsg
 	err            error
 	conf           *config.Config
 	token          auth.Token
+	authCancel     context.CancelFunc
+	authErr        error
+	authDeadline   time.Time
+	authScopes     []string
+	pendingScopes  []string
+	accounts       listing
+	partyRequests  listing
+	party          *partySession
+	statsRows      listing
+	stats          history.Stats
+	statsPeriod    history.Period
+	statsPage      statsPage
+	recentItems    []api.PlayHistory
+	recentRows     listing
+	recentBefore   string
+	recentCursor   string
+	topLists       map[api.TimeRange]topList
+	library        libraryState
+	revisions      revisionsState
+	tools          toolsState
+	topRows        listing
+	topSaved       string
+	topRange       int
 	view           view
+	pendingView    view
 	awaitDots      int
+	authAttempt    int
 	width          int
 	height         int
 	welcomeColor   int
 	selectedButton int
 	repeat         repeatState
 	clickedButton  bool
+	showLink       bool
+	partyLink      bool
+	fullHelp       bool
+	statsLoaded    bool
+	recentMore     bool
+	recentLoading  bool
+	topArtists     bool
+	topSaving      bool
+	copied         bool
 	resume         bool
 	shuffle        bool
 }
@@ -95,32 +155,118 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case initMsg:
 		return m, readConfig(m.conf)
 	case configReadMsg:
-		if m.conf.RefreshToken() != "" {
-			return m, regenToken(m.conf)
+		return m, tea.Batch(m.authenticate(), attachDaemon(m.conf.Profile()))
+	case profileSwitchedMsg:
+		m.authScopes = nil
+		m.token = auth.Token{}
+		m.profile = api.UserProfile{}
+		m.topLists = nil
+		m.library = libraryState{}
+		m.actions.setToken("")
+		m.actions = m.actions.attach(nil)
+		m = m.stopParty()
+		return m, tea.Batch(m.authenticate(), attachDaemon(m.conf.Profile()))
+	case daemonAttachedMsg:
+		if msg.client != nil && msg.client.Info().Profile != m.conf.Profile() {
+			msg.client.Close()
+			return m, nil
 		}
-
-		return m, requestGenToken()
+		m.actions = m.actions.attach(msg.client)
+	case partyStartedMsg:
+		return m.partyStarted(msg.session)
+	case partyChangedMsg:
+		return m.partyChanged(msg.session)
+	case statsLoadedMsg:
+		m = m.statsLoadedFor(msg)
+	case recentLoadedMsg:
+		return m.recentLoaded(msg)
+	case topLoadedMsg:
+		return m.topLoaded(msg)
+	case playlistCreatedMsg:
+		if m.topSaving {
+			return m.playlistCreated(msg)
+		}
+		return m.libraryCreated(msg)
+	case libraryLoadedMsg:
+		return m.libraryLoaded(msg)
+	case playlistLoadedMsg:
+		return m.playlistLoaded(msg)
+	case playlistEditedMsg:
+		return m.playlistEdited(msg)
+	case importResolvedMsg:
+		return m.importResolved(msg)
+	case snapshotsLoadedMsg:
+		return m.snapshotsLoaded(msg)
+	case mergeLoadedMsg:
+		return m.mergeLoaded(msg)
+	case playlistRewrittenMsg:
+		return m.playlistRewritten(msg)
 	case genTokenMsg, failedRegenTokenMsg:
-		m.view = authConfirmation
-		clientId := m.conf.ClientId()
-		return m, tea.Batch(genToken(clientId, m.conf),
-			incrementAwaitDots(m.awaitDots))
+		var cmd tea.Cmd
+		m, cmd = m.startAuthorization()
+		return m, cmd
+	case authStartedMsg:
+		if msg.attempt != m.authAttempt {
+			msg.authorization.Close()
+			return m, nil
+		}
+		m.authorization = msg.authorization
+		if msg.manual {
+			m.view = authManual
+			m.redirectInput.Placeholder = m.authorization.RedirectUri() + "?code=..."
+			m.showLink = false
+			m.copied = false
+			return m, m.redirectInput.Focus()
+		}
+		var enterCmd, waitCmd tea.Cmd
+		m, enterCmd = m.enterAuthConfirmation()
+		m, waitCmd = m.waitAuthorization()
+		return m, tea.Batch(enterCmd, waitCmd)
+	case authFailedMsg:
+		if msg.attempt != m.authAttempt {
+			return m, nil
+		}
+		m.authCancel = nil
+		m.authErr = msg.err
+		return m.enterAuthConfirmation()
+	case copiedMsg:
+		m.copied = true
+	case loggedOutMsg:
+		m = m.loggedOut()
 	case awaitDotsMsg:
 		m.awaitDots = int(msg)
 		if m.view == authConfirmation {
 			return m, incrementAwaitDots(m.awaitDots)
 		}
 	case expiredTokenMsg:
+		if m.token.Access == "" || !m.token.Expired() {
+			return m, nil
+		}
 		return m, regenToken(m.conf)
 	case newTokenMsg:
+		if m.authCancel != nil {
+			m.authCancel()
+			m.authCancel = nil
+		}
 		m.token = msg.token
 		m.actions.setToken(msg.token.Access)
 		if msg.refreshed {
-			m.view = player
-			return m, expirationAlert(m.token.ExpiresIn)
+			if m.view == initialization {
+				m.view = player
+			}
+			if m.profile.Name == "" {
+				return m, tea.Batch(expirationAlert(m.token),
+					m.actions.getUserProfile())
+			}
+			return m, expirationAlert(m.token)
+		} else if m.pendingScopes != nil {
+			m.pendingScopes = nil
+			var cmd tea.Cmd
+			m, cmd = m.navigate(m.pendingView)
+			return m, tea.Batch(expirationAlert(m.token), cmd)
 		} else {
 			m.view = authAck
-			return m, tea.Batch(expirationAlert(m.token.ExpiresIn),
+			return m, tea.Batch(expirationAlert(m.token),
 				m.actions.getUserProfile())
 		}
 	case userInfoMsg:
@@ -130,6 +276,51 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case removeClickMsg:
 		m.clickedButton = false
 	case tea.KeyMsg:
+		switch m.view {
+		case accounts:
+			return m.updateAccounts(msg)
+		case authManual:
+			return m.updateAuthManual(msg)
+		case authConfirmation:
+			return m.updateAuthConfirmation(msg)
+		case consent:
+			return m.updateConsent(msg)
+		case logoutConfirmation:
+			return m.updateLogout(msg)
+		case loggedOut:
+			return m.updateLoggedOut(msg)
+		case moderation:
+			return m.updateParty(msg)
+		case stats:
+			return m.updateStats(msg)
+		case recent:
+			return m.updateRecent(msg)
+		case top:
+			return m.updateTop(msg)
+		case library:
+			return m.updateLibrary(msg)
+		case followed:
+			return m.updateFollowed(msg)
+		case editor:
+			return m.updateEditor(msg)
+		case details:
+			return m.updateDetails(msg)
+		case transfer:
+			return m.updateTransfer(msg)
+		case review:
+			return m.updateReview(msg)
+		case revisions:
+			return m.updateRevisions(msg)
+		case diff:
+			return m.updateDiff(msg)
+		case tools:
+			return m.updateTools(msg)
+		case filtering:
+			return m.updateFilter(msg)
+		case preview:
+			return m.updatePreview(msg)
+		}
+
 		switch {
 		case key.Matches(msg, defaultKm.quit):
 			return m, tea.Quit
@@ -139,10 +330,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case authAck:
 			switch {
 			case key.Matches(msg, playerKm.enter):
-				m.view = player
+				return m.navigate(player)
 			}
 		case player:
 			switch {
+			case key.Matches(msg, playerKm.accounts):
+				return m.navigate(accounts)
+			case key.Matches(msg, playerKm.logout):
+				return m.navigate(logoutConfirmation)
+			case key.Matches(msg, playerKm.party):
+				return m.navigate(moderation)
+			case key.Matches(msg, playerKm.stats):
+				return m.navigate(stats)
+			case key.Matches(msg, playerKm.recent):
+				return m.navigate(recent)
+			case key.Matches(msg, playerKm.top):
+				return m.navigate(top)
+			case key.Matches(msg, playerKm.playlists):
+				return m.navigate(library)
+			case key.Matches(msg, playerKm.more):
+				m.fullHelp = !m.fullHelp
 			case key.Matches(msg, playerKm.left):
 				m.clickedButton = false
 				if m.selectedButton--; m.selectedButton < 0 {
@@ -177,11 +384,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.shuffle = !m.shuffle
 				case repeat:
 					switch m.repeat {
-					case track:
+					case repeatTrack:
 						cmd = m.actions.setRepeatTrack()
-					case context:
+					case repeatContext:
 						cmd = m.actions.setRepeatContext()
-					case off:
+					case repeatOff:
 						cmd = m.actions.disableRepeat()
 					}
 					m.repeat = (m.repeat + 1) % nRepeatStates
@@ -193,22 +400,86 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+	default:
+		if m.view == authManual {
+			var cmd tea.Cmd
+			m.redirectInput, cmd = m.redirectInput.Update(msg)
+			return m, cmd
+		}
+		if m.view == details {
+			return m.updateDetails(msg)
+		}
+		if m.view == transfer {
+			return m.updateTransfer(msg)
+		}
+		if m.view == filtering {
+			return m.updateFilter(msg)
+		}
 	}
 
 	return m, nil
 }
 
+func (m model) authenticate() tea.Cmd {
+	if token := m.conf.Token(); !token.Expired() {
+		return reuseToken(token)
+	}
+
+	if m.conf.RefreshToken() != "" {
+		return regenToken(m.conf)
+	}
+
+	return requestGenToken()
+}
+
+func (m model) header() string {
+	profile := m.conf.Profile()
+	if m.view == initialization || profile == "" {
+		return ""
+	}
+
+	header := profileStyle.Render(profile)
+	if m.profile.Name != "" {
+		header += profileNameStyle.Render(fmt.Sprintf(" · %s", m.profile.Name))
+	}
+	if m.actions.attached() {
+		header += profileNameStyle.Render(" · daemon")
+	}
+	if m.party != nil {
+		header += profileNameStyle.Render(" · party")
+	}
+
+	return header
+}
+
 func (m model) View() string {
-	display := ""
+	if m.view == authManual && m.showLink {
+		return m.viewAuthLink()
+	}
+
+	if m.view == moderation && m.partyLink && m.party != nil {
+		return m.viewPartyLink()
+	}
 
-	if m.currentWarnErr.warn() {
+	display := fmt.Sprintf("%s\n", m.header())
+
+	if m.currentWarnErr.warn() && m.currentWarnErr.notice {
+		display += copiedStyle.Render(m.currentWarnErr.err.Error())
+	} else if m.currentWarnErr.warn() {
 		warn := fmt.Sprintf("%s %s",
 			warnStyle.Render("Alert:"),
 			warnMsgStyle.Render(m.currentWarnErr.err.Error()))
-		display = fmt.Sprintf("%s%s", warn, display)
+		display = fmt.Sprintf("%s%s", display, warn)
 	}
 
-	display += "\n\n\n\n\n"
+	if m.view == accounts || m.view == authManual || m.view == moderation || m.view == stats ||
+		m.view == recent || m.view == top || m.view == library || m.view == followed ||
+		m.view == editor || m.view == details || m.view == transfer || m.view == review ||
+		m.view == revisions || m.view == diff || m.view == tools || m.view == filtering || m.view == preview {
+		display += "\n\n"
+	} else {
+		display += "\n\n\n\n"
+	}
 
 	var keyHelp help.KeyMap = defaultKm
 
@@ -217,15 +488,11 @@ func (m model) View() string {
 		colored := welcomeColorsStyle[m.welcomeColor].Render("Welcome to Spotify TUI")
 		display += welcomeStyle.Render(colored)
 	case authConfirmation:
-		dots := ""
-		for _, style := range dotColorsStyle[:m.awaitDots] {
-			dots += style.Render(".")
-		}
-		display += fmt.Sprintf(
-			"%s\n%s%s",
-			awaitStyle.Render("I sent an authorization request!"),
-			awaitStyle.Render("Check your browser, I'm waiting for your"),
-			dots)
+		keyHelp = m.authKeyMap()
+		display += m.viewAuthConfirmation()
+	case authManual:
+		keyHelp = manualAuthKm
+		display += m.viewAuthManual()
 	case authAck:
 		keyHelp = ackKm
 		name := m.profile.Name
@@ -254,6 +521,74 @@ func (m model) View() string {
 			buttons[m.selectedButton] = selectedButtonStyle.Render(buttons[m.selectedButton])
 		}
 		display += strings.Join(buttons, "   ")
+	case accounts:
+		keyHelp = listKm
+		display += m.viewAccounts()
+	case consent:
+		keyHelp = consentKm
+		display += m.viewConsent()
+	case logoutConfirmation:
+		keyHelp = consentKm
+		display += m.viewLogout()
+	case moderation:
+		keyHelp = partyKm
+		display += m.viewParty()
+	case stats:
+		keyHelp = statsKm
+		display += m.viewStats()
+	case recent:
+		keyHelp = recentKm
+		display += m.viewRecent()
+	case top:
+		keyHelp = topKm
+		display += m.viewTop()
+	case library:
+		keyHelp = playlistsKm
+		if m.library.pickUri != "" {
+			keyHelp = pickKm
+		}
+		display += m.viewLibrary()
+	case followed:
+		keyHelp = followedKm
+		display += m.viewFollowed()
+	case editor:
+		keyHelp = playlistKm
+		if m.library.confirming {
+			keyHelp = confirmKm
+		} else if m.library.moving {
+			keyHelp = movingKm
+		}
+		display += m.viewEditor()
+	case details:
+		keyHelp = formKm
+		display += m.viewDetails()
+	case transfer:
+		keyHelp = promptKm
+		display += m.viewTransfer()
+	case review:
+		keyHelp = reviewKm
+		display += m.viewReview()
+	case revisions:
+		keyHelp = revisionsKm
+		display += m.viewRevisions()
+	case diff:
+		keyHelp = diffKm
+		if m.revisions.readOnly {
+			keyHelp = readOnlyDiffKm
+		}
+		display += m.viewDiff()
+	case tools:
+		keyHelp = toolsKm
+		display += m.viewTools()
+	case filtering:
+		keyHelp = filterKm
+		display += m.viewFilter()
+	case preview:
+		keyHelp = previewKm
+		display += m.viewPreview()
+	case loggedOut:
+		keyHelp = loginKm
+		display += m.viewLoggedOut()
 	case err:
 		display += fmt.Sprintf("%s %s",
 			errorStyle.Render("Error:"),
@@ -261,10 +596,17 @@ func (m model) View() string {
 	}
 
 	newLines := "\n\n\n\n"
-	if m.view != authConfirmation && m.view != authAck {
+	if m.view == accounts || m.view == authManual || m.view == moderation || m.view == stats ||
+		m.view == recent || m.view == top || m.view == library || m.view == followed ||
+		m.view == editor || m.view == details || m.view == transfer || m.view == review ||
+		m.view == revisions || m.view == diff || m.view == tools || m.view == filtering || m.view == preview {
+		newLines = "\n\n"
+	} else if m.view != authConfirmation && m.view != authAck {
 		newLines += "\n"
 	}
-	display += fmt.Sprintf("%s%s", newLines, m.help.View(keyHelp))
+	keyView := m.help
+	keyView.ShowAll = m.view == player && m.fullHelp
+	display += fmt.Sprintf("%s%s", newLines, keyView.View(keyHelp))
 
 	display = displayStyle.Render(display)
 
@@ -272,18 +614,17 @@ func (m model) View() string {
 		lipgloss.Center, lipgloss.Center, display)
 }
 
-func newModel(confLocation string) model {
-	client := api.NewClient()
-
+func newModel(conf *config.Config) model {
 	return model{
 		help:           help.New(),
-		actions:        clientActions{client},
-		conf:           config.NewConfig(confLocation),
+		redirectInput:  newRedirectInput(),
+		actions:        newClientActions(),
+		conf:           conf,
 		currentWarnErr: newNoWarnErrMsg(),
 		view:           initialization,
 		selectedButton: 1,
 		shuffle:        true,
 		resume:         true,
-		repeat:         track,
+		repeat:         repeatTrack,
 	}
 }
@@ -1,10 +1,15 @@
 package ui
 
 import (
+	"errors"
 	"math/rand/v2"
 
 	"github.com/franciscosbf/spotify-tui/internals/api"
 	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/internals/history"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+	"github.com/franciscosbf/spotify-tui/internals/snapshots"
+	"github.com/franciscosbf/spotify-tui/pkg/daemon"
 )
 
 type (
@@ -20,28 +25,124 @@ type (
 	ackedAuthMsg        auth.Token
 	userInfoMsg         api.UserProfile
 	dismissWarnErrMsg   int
+	profileSwitchedMsg  struct{}
+	copiedMsg           struct{}
+	loggedOutMsg        struct{}
 )
 
+type partyStartedMsg struct {
+	session *partySession
+}
+
+type partyChangedMsg struct {
+	session *partySession
+}
+
+type statsLoadedMsg struct {
+	stats  history.Stats
+	period history.Period
+}
+
+type recentLoadedMsg struct {
+	page   api.RecentlyPlayed
+	before string
+	err    error
+}
+
+type topLoadedMsg struct {
+	timeRange api.TimeRange
+	list      topList
+	err       error
+}
+
+type playlistCreatedMsg struct {
+	playlist api.Playlist
+	err      error
+}
+
+type libraryLoadedMsg struct {
+	playlists []api.Playlist
+	err       error
+}
+
+type playlistLoadedMsg struct {
+	playlist    api.Playlist
+	items       []api.PlaylistItem
+	err         error
+	snapshotErr error
+}
+
+type playlistEditedMsg struct {
+	playlist api.Playlist
+	edit     playlists.Edit
+	undo     bool
+	err      error
+}
+
+type daemonAttachedMsg struct {
+	client *daemon.Client
+}
+
+type authStartedMsg struct {
+	authorization api.Authorization
+	attempt       int
+	manual        bool
+}
+
+type authFailedMsg struct {
+	err     error
+	attempt int
+}
+
 type newTokenMsg struct {
 	token     auth.Token
 	refreshed bool
 }
 
 type warnErrMsg struct {
-	err error
-	id  int
+	err    error
+	id     int
+	notice bool
 }
 
 func newWarnErrMsg(err error) warnErrMsg {
 	id := rand.Int()
 
-	return warnErrMsg{err, id}
+	return warnErrMsg{err, id, false}
+}
+
+func newNoticeMsg(notice string) warnErrMsg {
+	id := rand.Int()
+
+	return warnErrMsg{errors.New(notice), id, true}
 }
 
 func newNoWarnErrMsg() warnErrMsg {
-	return warnErrMsg{nil, -1}
+	return warnErrMsg{nil, -1, false}
 }
 
 func (w warnErrMsg) warn() bool {
 	return w.id != -1
 }
+
+type importResolvedMsg struct {
+	name    string
+	matches []playlists.Match
+	err     error
+}
+
+type snapshotsLoadedMsg struct {
+	playlistId string
+	snapshots  []snapshots.Snapshot
+	err        error
+}
+
+type mergeLoadedMsg struct {
+	items [][]api.PlaylistItem
+	err   error
+}
+
+type playlistRewrittenMsg struct {
+	playlist api.Playlist
+	err      error
+}
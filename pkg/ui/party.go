@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/party"
+	"github.com/franciscosbf/spotify-tui/internals/remote"
+)
+
+const partyRowWidth = 44
+
+type partySession struct {
+	requests *party.Party
+	server   *remote.Server
+	ctx      context.Context
+	cancel   context.CancelFunc
+	link     string
+}
+
+func (s *partySession) stop() {
+	s.cancel()
+	s.server.Close()
+}
+
+func (m model) stopParty() model {
+	if m.party != nil {
+		m.party.stop()
+		m.party = nil
+	}
+	m.partyLink = false
+
+	return m
+}
+
+func (m model) openParty() (model, tea.Cmd) {
+	m.view = moderation
+	if m.party == nil {
+		return m, startParty(m.conf, m.actions)
+	}
+
+	return m, nil
+}
+
+func (m model) partyStarted(session *partySession) (model, tea.Cmd) {
+	if m.party != nil || m.view != moderation {
+		session.stop()
+		return m, nil
+	}
+
+	m.party = session
+	m.partyRequests.reset(0, 0)
+
+	return m, waitPartyChange(session)
+}
+
+func (m model) partyChanged(session *partySession) (model, tea.Cmd) {
+	if m.party != session {
+		return m, nil
+	}
+
+	m.partyRequests.move(0, len(session.requests.Moderation()))
+
+	return m, waitPartyChange(session)
+}
+
+func (m model) moderate(approve bool) tea.Cmd {
+	entries := m.party.requests.Moderation()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	entry := entries[m.partyRequests.cursor]
+
+	var err error
+	if approve {
+		err = m.party.requests.Approve(entry.Id)
+	} else {
+		err = m.party.requests.Reject(entry.Id)
+	}
+
+	if err != nil {
+		return func() tea.Msg {
+			return newWarnErrMsg(err)
+		}
+	}
+
+	return nil
+}
+
+func (m model) updateParty(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.partyLink {
+		switch {
+		case key.Matches(msg, partyKm.quit):
+			return m.stopParty(), tea.Quit
+		case key.Matches(msg, partyKm.link), key.Matches(msg, partyKm.back):
+			m.partyLink = false
+		}
+
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, partyKm.quit):
+		return m.stopParty(), tea.Quit
+	case key.Matches(msg, partyKm.back):
+		m.view = player
+	case key.Matches(msg, partyKm.stop):
+		m = m.stopParty()
+		m.view = player
+	}
+
+	if m.party == nil {
+		return m, nil
+	}
+
+	size := len(m.party.requests.Moderation())
+
+	switch {
+	case key.Matches(msg, partyKm.up):
+		m.partyRequests.move(-1, size)
+	case key.Matches(msg, partyKm.down):
+		m.partyRequests.move(1, size)
+	case key.Matches(msg, partyKm.approve):
+		return m, m.moderate(true)
+	case key.Matches(msg, partyKm.reject):
+		return m, m.moderate(false)
+	case key.Matches(msg, partyKm.link):
+		m.partyLink = true
+	}
+
+	return m, nil
+}
+
+func truncate(content string, width int) string {
+	runes := []rune(content)
+	if len(runes) <= width {
+		return content
+	}
+
+	return string(runes[:width-1]) + "…"
+}
+
+func (m model) viewParty() string {
+	if m.party == nil {
+		return awaitStyle.Render("Starting party...")
+	}
+
+	entries := m.party.requests.Moderation()
+
+	pending := 0
+	rows := []string{}
+	for _, entry := range entries {
+		marker := " "
+		if entry.Status == party.StatusApproved {
+			marker = activeProfileStyle.Render("●")
+		} else {
+			pending++
+		}
+
+		row := truncate(fmt.Sprintf("%+d %s - %s (%s)",
+			entry.Score, entry.Title, entry.Artist, entry.Guest), partyRowWidth)
+		rows = append(rows, fmt.Sprintf("%s %s", marker, row))
+	}
+
+	title := titleStyle.Render(fmt.Sprintf("Party · %d pending", pending))
+	if err := m.party.requests.QueueErr(); err != nil {
+		title += " " + errorMsgStyle.Render(truncate(err.Error(), partyRowWidth-20))
+	}
+
+	if len(rows) == 0 {
+		return fmt.Sprintf("%s\n\n%s", title,
+			awaitStyle.Render("No requests yet, press tab to invite guests"))
+	}
+
+	return fmt.Sprintf("%s\n\n%s", title, m.partyRequests.render(rows))
+}
+
+func (m model) viewPartyLink() string {
+	return fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s",
+		titleStyle.Render("Guests can join the party at:"),
+		breakLines(m.party.link, m.width),
+		renderQr(m.party.link),
+		m.help.View(partyKm))
+}
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/api"
+)
+
+const recentRowWidth = 46
+
+var errNoContext = errors.New("the track wasn't played from a playlist, album or artist")
+
+type recentRow struct {
+	header string
+	item   int
+}
+
+func (m model) openRecent() (model, tea.Cmd) {
+	m.view = recent
+	m.recentItems = nil
+	m.recentMore = false
+	m.recentLoading = true
+	m.recentCursor = ""
+	m.recentRows.reset(0, 0)
+
+	return m, m.actions.loadRecent("")
+}
+
+func (m model) recentLoaded(msg recentLoadedMsg) (model, tea.Cmd) {
+	if m.view != recent || msg.before != m.recentCursor {
+		return m, nil
+	}
+
+	m.recentLoading = false
+	if msg.err != nil {
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	m.recentItems = append(m.recentItems, msg.page.Items...)
+	m.recentBefore = msg.page.Cursors.Before
+	m.recentMore = msg.page.HasMore() && len(msg.page.Items) > 0
+
+	if msg.before == "" {
+		m.recentRows.reset(0, len(m.recentGroups()))
+		m.recentRows = m.skipHeader(1)
+	}
+
+	return m, nil
+}
+
+func day(t time.Time, now time.Time) string {
+	t = t.Local()
+	year, month, date := now.Date()
+	today := time.Date(year, month, date, 0, 0, 0, 0, now.Location())
+
+	switch {
+	case !t.Before(today):
+		return "Today"
+	case !t.Before(today.AddDate(0, 0, -1)):
+		return "Yesterday"
+	case t.Year() == now.Year():
+		return t.Format("Monday, 02 Jan")
+	}
+
+	return t.Format("Monday, 02 Jan 2006")
+}
+
+func (m model) recentGroups() []recentRow {
+	now := time.Now()
+
+	rows := []recentRow{}
+	last := ""
+	for i, item := range m.recentItems {
+		if header := day(item.PlayedAt, now); header != last {
+			rows = append(rows, recentRow{header: header, item: -1})
+			last = header
+		}
+		rows = append(rows, recentRow{item: i})
+	}
+
+	return rows
+}
+
+func (m model) skipHeader(delta int) listing {
+	rows := m.recentGroups()
+	l := m.recentRows
+
+	if l.cursor < len(rows) && rows[l.cursor].item == -1 {
+		if l.cursor == 0 {
+			delta = 1
+		}
+		l.move(delta, len(rows))
+		if l.cursor == 1 {
+			l.offset = 0
+		}
+	}
+
+	return l
+}
+
+func (m model) selectedRecent() (api.PlayHistory, bool) {
+	rows := m.recentGroups()
+	if m.recentRows.cursor >= len(rows) || rows[m.recentRows.cursor].item == -1 {
+		return api.PlayHistory{}, false
+	}
+
+	return m.recentItems[rows[m.recentRows.cursor].item], true
+}
+
+func (m model) moveRecent(delta int) (model, tea.Cmd) {
+	size := len(m.recentGroups())
+
+	m.recentRows.move(delta, size)
+	m.recentRows = m.skipHeader(delta)
+
+	if m.recentRows.cursor == size-1 && m.recentMore && !m.recentLoading {
+		m.recentLoading = true
+		m.recentCursor = m.recentBefore
+		return m, m.actions.loadRecent(m.recentBefore)
+	}
+
+	return m, nil
+}
+
+func (m model) updateRecent(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, recentKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, recentKm.back):
+		m.view = player
+	case key.Matches(msg, recentKm.reload):
+		return m.openRecent()
+	case key.Matches(msg, recentKm.up):
+		return m.moveRecent(-1)
+	case key.Matches(msg, recentKm.down):
+		return m.moveRecent(1)
+	case key.Matches(msg, recentKm.replay):
+		if item, ok := m.selectedRecent(); ok {
+			return m, m.actions.startPlayback(api.PlayOptions{Uris: []string{item.Track.Uri}})
+		}
+	case key.Matches(msg, recentKm.add):
+		if item, ok := m.selectedRecent(); ok {
+			return m.pickPlaylist(item.Track.Uri)
+		}
+	case key.Matches(msg, recentKm.context):
+		item, ok := m.selectedRecent()
+		if !ok {
+			return m, nil
+		}
+
+		if item.Context.Uri == "" {
+			return m, func() tea.Msg { return newWarnErrMsg(errNoContext) }
+		}
+
+		options := api.PlayOptions{ContextUri: item.Context.Uri}
+		if item.Context.Type != "artist" {
+			options.OffsetUri = item.Track.Uri
+		}
+
+		return m, m.actions.startPlayback(options)
+	}
+
+	return m, nil
+}
+
+func (m model) viewRecent() string {
+	title := titleStyle.Render("Recently played")
+
+	if m.recentItems == nil {
+		if m.recentLoading {
+			return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Loading..."))
+		}
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Nothing played recently"))
+	}
+
+	groups := m.recentGroups()
+
+	rows := make([]string, 0, len(groups))
+	for _, row := range groups {
+		if row.item == -1 {
+			rows = append(rows, activeProfileStyle.Render(row.header))
+			continue
+		}
+
+		item := m.recentItems[row.item]
+
+		artist := ""
+		if len(item.Track.Artists) > 0 {
+			artist = " - " + item.Track.Artists[0].Name
+		}
+
+		rows = append(rows, truncate(fmt.Sprintf("%s  %s%s",
+			item.PlayedAt.Local().Format("15:04"), item.Track.Name, artist), recentRowWidth))
+	}
+
+	if m.recentLoading {
+		title += countdownStyle.Render(" · loading more")
+	}
+
+	return fmt.Sprintf("%s\n\n%s", title, m.recentRows.render(rows))
+}
@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+	"github.com/franciscosbf/spotify-tui/internals/snapshots"
+)
+
+const snapshotLayout = "02 Jan 15:04"
+
+type revisionsState struct {
+	playlistId string
+	snapshots  []snapshots.Snapshot
+	loaded     bool
+	rows       listing
+	base       int
+	from       snapshots.Snapshot
+	to         snapshots.Snapshot
+	changes    []snapshots.Change
+	changeRows listing
+	restored   map[int]bool
+	readOnly   bool
+}
+
+func (m model) snapshotStore() *snapshots.Store {
+	return snapshots.NewStore(m.conf.DataPath("snapshots"))
+}
+
+func (m model) openRevisions() (model, tea.Cmd) {
+	m.view = revisions
+	m.revisions = revisionsState{playlistId: m.library.current.Id, base: -1}
+
+	return m, loadSnapshots(m.snapshotStore(), m.library.current.Id)
+}
+
+func (m model) openFollowedRevisions(playlist api.Playlist) (model, tea.Cmd) {
+	m.library.current = playlist
+	m.library.items = nil
+	m.library.itemsLoaded = false
+
+	m, cmd := m.openRevisions()
+	m.revisions.readOnly = true
+
+	return m, cmd
+}
+
+func (m model) snapshotsLoaded(msg snapshotsLoadedMsg) (model, tea.Cmd) {
+	if msg.playlistId != m.revisions.playlistId {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	m.revisions.snapshots = msg.snapshots
+	m.revisions.loaded = true
+	m.revisions.rows.reset(0, len(msg.snapshots))
+
+	return m, nil
+}
+
+func (m model) snapshotAt(row int) (snapshots.Snapshot, bool) {
+	index := len(m.revisions.snapshots) - 1 - row
+	if index < 0 || index >= len(m.revisions.snapshots) {
+		return snapshots.Snapshot{}, false
+	}
+
+	return m.revisions.snapshots[index], true
+}
+
+func (m model) openDiff() (model, tea.Cmd) {
+	cursor := m.revisions.rows.cursor
+
+	to, ok := m.snapshotAt(cursor)
+	if !ok {
+		return m, nil
+	}
+
+	base := m.revisions.base
+	if base < 0 || base == cursor {
+		base = cursor + 1
+	}
+
+	from, ok := m.snapshotAt(base)
+	if !ok {
+		return m, func() tea.Msg {
+			return newNoticeMsg("Nothing older to compare with, pick a snapshot with space")
+		}
+	}
+
+	if from.At.After(to.At) {
+		from, to = to, from
+	}
+
+	m.revisions.from = from
+	m.revisions.to = to
+	m.revisions.changes = snapshots.Diff(from, to)
+	m.revisions.restored = map[int]bool{}
+	m.revisions.changeRows.reset(0, len(m.revisions.changes))
+	m.view = diff
+
+	return m, nil
+}
+
+func (m model) updateRevisions(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	count := len(m.revisions.snapshots)
+
+	switch {
+	case key.Matches(msg, revisionsKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, revisionsKm.back):
+		m.view = editor
+		if m.revisions.readOnly {
+			m.view = followed
+		}
+	case key.Matches(msg, revisionsKm.up):
+		m.revisions.rows.move(-1, count)
+	case key.Matches(msg, revisionsKm.down):
+		m.revisions.rows.move(1, count)
+	case key.Matches(msg, revisionsKm.base):
+		if m.revisions.base == m.revisions.rows.cursor {
+			m.revisions.base = -1
+		} else if m.revisions.rows.cursor < count {
+			m.revisions.base = m.revisions.rows.cursor
+		}
+	case key.Matches(msg, revisionsKm.enter):
+		return m.openDiff()
+	}
+
+	return m, nil
+}
+
+func (m model) updateDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	changes := m.revisions.changes
+	cursor := m.revisions.changeRows.cursor
+
+	switch {
+	case key.Matches(msg, diffKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, diffKm.back):
+		m.view = revisions
+	case key.Matches(msg, diffKm.up):
+		m.revisions.changeRows.move(-1, len(changes))
+	case key.Matches(msg, diffKm.down):
+		m.revisions.changeRows.move(1, len(changes))
+	case key.Matches(msg, diffKm.restore):
+		if cursor >= len(changes) || changes[cursor].Added || m.revisions.restored[cursor] || m.library.busy ||
+			m.revisions.readOnly {
+			return m, nil
+		}
+
+		change := changes[cursor]
+		m.revisions.restored = maps.Clone(m.revisions.restored)
+		m.revisions.restored[cursor] = true
+
+		edit := playlists.NewAdd(m.library.items, change.Track.Uri, min(change.Position, len(m.library.items)))
+
+		return m.applyEdit(m.library.current, m.library.current.SnapshotId, edit, false)
+	}
+
+	return m, nil
+}
+
+func (m model) updateFollowed(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	playlists := m.followedPlaylists()
+
+	switch {
+	case key.Matches(msg, followedKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, followedKm.back):
+		m.view = library
+	case key.Matches(msg, followedKm.up):
+		m.library.followed.move(-1, len(playlists))
+	case key.Matches(msg, followedKm.down):
+		m.library.followed.move(1, len(playlists))
+	case key.Matches(msg, followedKm.enter):
+		if m.library.followed.cursor < len(playlists) {
+			return m.openFollowedRevisions(playlists[m.library.followed.cursor])
+		}
+	}
+
+	return m, nil
+}
+
+func (m model) viewFollowed() string {
+	title := titleStyle.Render("Followed playlists")
+
+	playlists := m.followedPlaylists()
+	if len(playlists) == 0 {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("You don't follow any playlists"))
+	}
+
+	rows := []string{}
+	for _, playlist := range playlists {
+		rows = append(rows, fmt.Sprintf("%-*s %s", libraryRowWidth-12,
+			truncate(playlist.Name, libraryRowWidth-12),
+			countdownStyle.Render(fmt.Sprintf("%4d tracks", playlist.Tracks.Total))))
+	}
+
+	return fmt.Sprintf("%s\n\n%s", title, m.library.followed.render(rows))
+}
+
+func (m model) viewRevisions() string {
+	title := titleStyle.Render(truncate(fmt.Sprintf("History of %s", m.library.current.Name), libraryRowWidth))
+
+	if !m.revisions.loaded {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Loading..."))
+	}
+
+	if len(m.revisions.snapshots) == 0 {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("No snapshots recorded yet"))
+	}
+
+	summary := countdownStyle.Render("enter compares with the previous one or the pinned base")
+
+	rows := []string{}
+	for row := range m.revisions.snapshots {
+		snapshot, _ := m.snapshotAt(row)
+
+		changes := ""
+		if previous, ok := m.snapshotAt(row + 1); ok {
+			added, removed := 0, 0
+			for _, change := range snapshots.Diff(previous, snapshot) {
+				if change.Added {
+					added++
+				} else {
+					removed++
+				}
+			}
+			changes = fmt.Sprintf("+%d -%d", added, removed)
+		}
+
+		marker := " "
+		if row == m.revisions.base {
+			marker = "◆"
+		}
+
+		rows = append(rows, fmt.Sprintf("%s %s %5d tracks  %s",
+			marker, snapshot.At.Local().Format(snapshotLayout), len(snapshot.Tracks), changes))
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, summary, m.revisions.rows.render(rows))
+}
+
+func (m model) viewDiff() string {
+	title := titleStyle.Render(fmt.Sprintf("Changes %s → %s",
+		m.revisions.from.At.Local().Format(snapshotLayout), m.revisions.to.At.Local().Format(snapshotLayout)))
+
+	added := 0
+	for _, change := range m.revisions.changes {
+		if change.Added {
+			added++
+		}
+	}
+
+	summary := countdownStyle.Render(fmt.Sprintf("%d added · %d removed", added, len(m.revisions.changes)-added))
+	if m.library.busy {
+		summary = countdownStyle.Render("Saving...")
+	}
+
+	if len(m.revisions.changes) == 0 {
+		return fmt.Sprintf("%s\n%s\n%s", title, summary, awaitStyle.Render("Same tracks in both snapshots"))
+	}
+
+	rows := []string{}
+	for i, change := range m.revisions.changes {
+		sign := "-"
+		switch {
+		case change.Added:
+			sign = "+"
+		case m.revisions.restored[i]:
+			sign = "↺"
+		}
+
+		row := fmt.Sprintf("%s %s", sign, truncate(change.Track.String(), libraryRowWidth-2))
+		if by := change.Track.AddedBy; by != "" {
+			row = fmt.Sprintf("%s %s by %s", sign,
+				truncate(change.Track.String(), libraryRowWidth-18), truncate(by, 12))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, summary, m.revisions.changeRows.render(rows))
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/history"
+)
+
+type statsPage int
+
+const (
+	topTracksPage statsPage = iota
+	topArtistsPage
+	listeningTimePage
+	nStatsPages
+)
+
+var statsPageTitles = []string{"Top tracks", "Top artists", "Listening time"}
+
+const (
+	statsNameWidth = 26
+	statsBarWidth  = 24
+)
+
+func (m model) openStats() (model, tea.Cmd) {
+	m.view = stats
+	m.statsLoaded = false
+
+	return m, loadStats(m.conf.DataPath("history.db"), m.statsPeriod)
+}
+
+func (m model) statsRowCount() int {
+	switch m.statsPage {
+	case topTracksPage:
+		return len(m.stats.Tracks)
+	case topArtistsPage:
+		return len(m.stats.Artists)
+	}
+
+	return len(m.stats.Buckets)
+}
+
+func (m model) statsLoadedFor(msg statsLoadedMsg) model {
+	if msg.period != m.statsPeriod {
+		return m
+	}
+
+	m.stats = msg.stats
+	m.statsLoaded = true
+	m.statsRows.reset(0, m.statsRowCount())
+
+	return m
+}
+
+func (m model) updateStats(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, statsKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, statsKm.back):
+		m.view = player
+	case key.Matches(msg, statsKm.up):
+		m.statsRows.move(-1, m.statsRowCount())
+	case key.Matches(msg, statsKm.down):
+		m.statsRows.move(1, m.statsRowCount())
+	case key.Matches(msg, statsKm.page):
+		m.statsPage = (m.statsPage + 1) % nStatsPages
+		m.statsRows.reset(0, m.statsRowCount())
+	case key.Matches(msg, statsKm.period):
+		m.statsPeriod = (m.statsPeriod + 1) % 2
+		return m.openStats()
+	}
+
+	return m, nil
+}
+
+func formatHours(ms int) string {
+	return fmt.Sprintf("%.1fh", (time.Duration(ms) * time.Millisecond).Hours())
+}
+
+func countRows(counts []history.Count) []string {
+	rows := []string{}
+	for _, entry := range counts {
+		rows = append(rows, fmt.Sprintf("%-*s %4d plays %3.0f%% skip",
+			statsNameWidth, truncate(entry.Name, statsNameWidth), entry.Plays, entry.SkipRate()*100))
+	}
+
+	return rows
+}
+
+func (m model) bucketRows() []string {
+	longest := 1
+	for _, bucket := range m.stats.Buckets {
+		longest = max(longest, bucket.PlayedMs)
+	}
+
+	layout := "Mon 02 Jan"
+	if m.statsPeriod == history.Weekly {
+		layout = "Week 02 Jan"
+	}
+
+	rows := []string{}
+	for _, bucket := range m.stats.Buckets {
+		bar := strings.Repeat("█", bucket.PlayedMs*statsBarWidth/longest)
+		rows = append(rows, fmt.Sprintf("%-11s %-*s %s",
+			bucket.Start.Format(layout), statsBarWidth, bar, formatHours(bucket.PlayedMs)))
+	}
+
+	return rows
+}
+
+func (m model) viewStats() string {
+	period := "last 7 days"
+	if m.statsPeriod == history.Weekly {
+		period = "last 12 weeks"
+	}
+
+	title := titleStyle.Render(fmt.Sprintf("%s · %s", statsPageTitles[m.statsPage], period))
+
+	if !m.statsLoaded {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Loading..."))
+	}
+
+	total := m.stats.Total
+	if total.Plays == 0 {
+		return fmt.Sprintf("%s\n\n%s\n%s", title,
+			awaitStyle.Render("Nothing recorded yet"),
+			countdownStyle.Render("Plays are recorded while the daemon runs"))
+	}
+
+	summary := countdownStyle.Render(fmt.Sprintf("%d plays · %s · %.0f%% skipped",
+		total.Plays, formatHours(total.PlayedMs), total.SkipRate()*100))
+
+	var rows []string
+	switch m.statsPage {
+	case topTracksPage:
+		rows = countRows(m.stats.Tracks)
+	case topArtistsPage:
+		rows = countRows(m.stats.Artists)
+	case listeningTimePage:
+		rows = m.bucketRows()
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, summary, m.statsRows.render(rows))
+}
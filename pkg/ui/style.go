@@ -59,6 +59,33 @@ var (
 			Foreground(lipgloss.Color("#fb4934"))
 	errorMsgStyle = lipgloss.NewStyle().
 			Italic(true)
+	profileStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#8ec07c"))
+	profileNameStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#928374"))
+	titleStyle = lipgloss.NewStyle().
+			Bold(true)
+	rowStyle = lipgloss.NewStyle().
+			Width(48)
+	selectedRowStyle = lipgloss.NewStyle().
+				Width(48).
+				Foreground(lipgloss.Color("#fe8019"))
+	activeProfileStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#b8bb26"))
+	scopeStyle = lipgloss.NewStyle().
+			Italic(true).
+			Foreground(lipgloss.Color("#8ec07c"))
+	linkStyle = lipgloss.NewStyle().
+			Underline(true).
+			Foreground(lipgloss.Color("#83a598"))
+	countdownStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#928374"))
+	copiedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#b8bb26"))
+	qrStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#ffffff")).
+		Background(lipgloss.Color("#000000"))
 	displayStyle = lipgloss.NewStyle().
 			Margin(2, 2).
 			Padding(2, 2).
@@ -0,0 +1,401 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+)
+
+type tool struct {
+	label   string
+	rewrite func(items []api.PlaylistItem, descending bool) []api.PlaylistItem
+}
+
+func dedupeTool(label string, options playlists.DedupeOptions) tool {
+	return tool{label, func(items []api.PlaylistItem, _ bool) []api.PlaylistItem {
+		return playlists.Dedupe(items, options)
+	}}
+}
+
+func sortTool(label string, sortKey playlists.SortKey) tool {
+	return tool{label, func(items []api.PlaylistItem, descending bool) []api.PlaylistItem {
+		return playlists.Sort(items, sortKey, descending)
+	}}
+}
+
+var toolOptions = []tool{
+	dedupeTool("Remove duplicates", playlists.DedupeOptions{}),
+	dedupeTool("Remove duplicates, same ISRC too", playlists.DedupeOptions{Isrc: true}),
+	dedupeTool("Remove duplicates, same title too", playlists.DedupeOptions{Isrc: true, Names: true}),
+	sortTool("Sort by artist", playlists.SortArtist),
+	sortTool("Sort by album", playlists.SortAlbum),
+	sortTool("Sort by date added", playlists.SortAdded),
+	sortTool("Sort by duration", playlists.SortDuration),
+	sortTool("Sort by release date", playlists.SortRelease),
+}
+
+type filterForm struct {
+	name       textinput.Model
+	years      textinput.Model
+	artists    textinput.Model
+	noExplicit bool
+	focus      int
+}
+
+type toolsState struct {
+	rows       listing
+	descending bool
+	form       filterForm
+	marked     map[string]bool
+	source     api.Playlist
+	name       string
+	loading    bool
+	before     []api.PlaylistItem
+	after      []api.PlaylistItem
+	changes    []playlists.Change
+	changeRows listing
+	returnTo   view
+}
+
+func (m model) openTools() (model, tea.Cmd) {
+	if !m.library.itemsLoaded || m.library.busy {
+		return m, nil
+	}
+
+	m.view = tools
+	m.tools.rows.reset(0, len(toolOptions)+1)
+
+	return m, nil
+}
+
+func (m model) openPreview(source api.Playlist, name string, before, after []api.PlaylistItem, returnTo view) (model, tea.Cmd) {
+	m.view = preview
+	m.tools.source = source
+	m.tools.name = name
+	m.tools.loading = false
+	m.tools.before = before
+	m.tools.after = after
+	m.tools.changes = playlists.Compare(before, after)
+	m.tools.changeRows.reset(0, len(m.tools.changes))
+	m.tools.returnTo = returnTo
+
+	return m, nil
+}
+
+func (m model) openFilter() (model, tea.Cmd) {
+	name := newDetailsInput("Name", 100)
+	name.SetValue(m.library.current.Name + " (filtered)")
+
+	m.tools.form = filterForm{
+		name:    name,
+		years:   newDetailsInput("Release years, e.g. 1990-1999", 9),
+		artists: newDetailsInput("Artists to leave out, comma separated", 500),
+	}
+	m.view = filtering
+
+	return m, m.tools.form.name.Focus()
+}
+
+func (m model) updateTools(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	count := len(toolOptions) + 1
+	cursor := m.tools.rows.cursor
+
+	switch {
+	case key.Matches(msg, toolsKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, toolsKm.back):
+		m.view = editor
+	case key.Matches(msg, toolsKm.up):
+		m.tools.rows.move(-1, count)
+	case key.Matches(msg, toolsKm.down):
+		m.tools.rows.move(1, count)
+	case key.Matches(msg, toolsKm.order):
+		m.tools.descending = !m.tools.descending
+	case key.Matches(msg, toolsKm.enter):
+		if cursor == len(toolOptions) {
+			return m.openFilter()
+		}
+
+		items := m.library.items
+		after := toolOptions[cursor].rewrite(items, m.tools.descending)
+
+		return m.openPreview(m.library.current, "", items, after, tools)
+	}
+
+	return m, nil
+}
+
+func (m model) updateFilter(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form := &m.tools.form
+	inputs := []*textinput.Model{&form.name, &form.years, &form.artists}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, filterKm.back):
+			m.view = tools
+			return m, nil
+		case key.Matches(msg, filterKm.next):
+			delta := 1
+			if msg.String() == "shift+tab" {
+				delta = 3
+			}
+			form.focus = (form.focus + delta) % 4
+			for _, input := range inputs {
+				input.Blur()
+			}
+			if form.focus < len(inputs) {
+				return m, inputs[form.focus].Focus()
+			}
+			return m, nil
+		case key.Matches(msg, filterKm.toggle) && form.focus == 3:
+			form.noExplicit = !form.noExplicit
+			return m, nil
+		case key.Matches(msg, filterKm.enter):
+			name := strings.TrimSpace(form.name.Value())
+			if name == "" {
+				return m, nil
+			}
+
+			from, to, err := playlists.ParseYears(strings.TrimSpace(form.years.Value()))
+			if err != nil {
+				return m, func() tea.Msg { return newWarnErrMsg(err) }
+			}
+
+			filter := playlists.Filter{FromYear: from, ToYear: to, ExcludeExplicit: form.noExplicit}
+			for _, artist := range strings.Split(form.artists.Value(), ",") {
+				if artist = strings.TrimSpace(artist); artist != "" {
+					filter.ExcludeArtists = append(filter.ExcludeArtists, artist)
+				}
+			}
+
+			items := m.library.items
+
+			return m.openPreview(api.Playlist{}, name, items, playlists.FilterItems(items, filter), filtering)
+		}
+	}
+
+	if form.focus >= len(inputs) {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	*inputs[form.focus], cmd = inputs[form.focus].Update(msg)
+
+	return m, cmd
+}
+
+func (m model) toggleMark(playlist api.Playlist) model {
+	m.tools.marked = maps.Clone(m.tools.marked)
+	if m.tools.marked == nil {
+		m.tools.marked = map[string]bool{}
+	}
+
+	if m.tools.marked[playlist.Id] {
+		delete(m.tools.marked, playlist.Id)
+	} else {
+		m.tools.marked[playlist.Id] = true
+	}
+
+	return m
+}
+
+func (m model) openMerge() (model, tea.Cmd) {
+	marked := []api.Playlist{}
+	names := []string{}
+	for _, playlist := range m.editablePlaylists() {
+		if m.tools.marked[playlist.Id] {
+			marked = append(marked, playlist)
+			names = append(names, playlist.Name)
+		}
+	}
+
+	if len(marked) < 2 {
+		return m, func() tea.Msg { return newNoticeMsg("Mark at least two playlists with space to merge them") }
+	}
+
+	m, _ = m.openPreview(api.Playlist{}, truncate(strings.Join(names, " + "), 100), nil, nil, library)
+	m.tools.loading = true
+
+	return m, m.actions.loadMerge(marked)
+}
+
+func (m model) mergeLoaded(msg mergeLoadedMsg) (model, tea.Cmd) {
+	if m.view != preview || !m.tools.loading {
+		return m, nil
+	}
+
+	if msg.err != nil {
+		m.view = library
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	m.tools.marked = nil
+
+	before := playlists.Merge(msg.items...)
+
+	return m.openPreview(api.Playlist{}, m.tools.name, before, playlists.Dedupe(before, playlists.DedupeOptions{}), library)
+}
+
+func (m model) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	changes := m.tools.changes
+
+	switch {
+	case key.Matches(msg, previewKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, previewKm.back):
+		m.view = m.tools.returnTo
+		m.tools.loading = false
+	case key.Matches(msg, previewKm.up):
+		m.tools.changeRows.move(-1, len(changes))
+	case key.Matches(msg, previewKm.down):
+		m.tools.changeRows.move(1, len(changes))
+	case m.tools.loading || m.library.busy:
+	case key.Matches(msg, previewKm.enter):
+		if source := m.tools.source; source.Id != "" {
+			m.view = editor
+			if slices.EqualFunc(m.tools.before, m.tools.after, func(a, b api.PlaylistItem) bool {
+				return a.Track.Uri == b.Track.Uri
+			}) {
+				return m, nil
+			}
+
+			m.library.busy = true
+
+			return m, m.actions.rewritePlaylist(source, m.tools.before, m.tools.after)
+		}
+
+		if m.profile.Id == "" {
+			return m, func() tea.Msg { return newWarnErrMsg(errNoProfile) }
+		}
+
+		if len(m.tools.after) == 0 {
+			return m, func() tea.Msg { return newNoticeMsg("No tracks left to add") }
+		}
+
+		uris := make([]string, 0, len(m.tools.after))
+		for _, item := range m.tools.after {
+			uris = append(uris, item.Track.Uri)
+		}
+
+		m.view = library
+		m.library.busy = true
+
+		return m, m.actions.createPlaylist(m.profile.Id,
+			api.PlaylistDetails{Name: m.tools.name},
+			func() ([]string, error) { return uris, nil })
+	}
+
+	return m, nil
+}
+
+func (m model) playlistRewritten(msg playlistRewrittenMsg) (model, tea.Cmd) {
+	m.library.busy = false
+
+	if msg.err != nil {
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	cmds := []tea.Cmd{func() tea.Msg {
+		return newNoticeMsg(fmt.Sprintf("Updated %s", msg.playlist.Name))
+	}}
+
+	if m.view == editor && m.library.current.Id == msg.playlist.Id {
+		cmds = append(cmds, m.actions.loadPlaylist(msg.playlist.Id, m.snapshotStore()))
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m model) viewTools() string {
+	title := titleStyle.Render(truncate(fmt.Sprintf("Tidy %s", m.library.current.Name), libraryRowWidth))
+
+	order := "ascending"
+	if m.tools.descending {
+		order = "descending"
+	}
+	summary := countdownStyle.Render(fmt.Sprintf("Sorting in %s order, changes are previewed first", order))
+
+	rows := []string{}
+	for _, option := range toolOptions {
+		rows = append(rows, option.label)
+	}
+	rows = append(rows, "Filter into a new playlist")
+
+	return fmt.Sprintf("%s\n%s\n%s", title, summary, m.tools.rows.render(rows))
+}
+
+func (m model) viewFilter() string {
+	form := m.tools.form
+
+	box := "[ ]"
+	if form.noExplicit {
+		box = "[x]"
+	}
+
+	checkbox := rowStyle.Render("  " + box + " leave out explicit tracks")
+	if form.focus == 3 {
+		checkbox = selectedRowStyle.Render("› " + box + " leave out explicit tracks")
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n%s\n%s\n%s",
+		titleStyle.Render(truncate(fmt.Sprintf("Filter %s into", m.library.current.Name), libraryRowWidth)),
+		form.name.View(),
+		form.years.View(),
+		form.artists.View(),
+		checkbox)
+}
+
+func (m model) viewPreview() string {
+	target := m.tools.source.Name
+	if m.tools.source.Id == "" {
+		target = m.tools.name
+	}
+
+	title := titleStyle.Render(truncate(fmt.Sprintf("Preview of %s", target), libraryRowWidth))
+
+	if m.tools.loading {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Loading..."))
+	}
+
+	removed, moved := 0, 0
+	for _, change := range m.tools.changes {
+		switch {
+		case change.To < 0:
+			removed++
+		case change.Moved():
+			moved++
+		}
+	}
+
+	summary := fmt.Sprintf("%d removed · %d moved · enter applies", removed, moved)
+	if m.tools.source.Id == "" {
+		summary = fmt.Sprintf("%d tracks · %d left out · enter creates it", len(m.tools.after), removed)
+	}
+
+	if len(m.tools.changes) == 0 {
+		return fmt.Sprintf("%s\n%s\n%s", title, countdownStyle.Render(summary), awaitStyle.Render("No tracks"))
+	}
+
+	rows := []string{}
+	for _, change := range m.tools.changes {
+		name := playlists.FromTrack(change.Item.Track).String()
+
+		switch {
+		case change.To < 0:
+			rows = append(rows, fmt.Sprintf("- %s", truncate(name, libraryRowWidth-2)))
+		case change.Moved() && m.tools.source.Id != "":
+			rows = append(rows, fmt.Sprintf("↕ %s", truncate(name, libraryRowWidth-2)))
+		default:
+			rows = append(rows, fmt.Sprintf("  %s", truncate(name, libraryRowWidth-2)))
+		}
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, countdownStyle.Render(summary), m.tools.changeRows.render(rows))
+}
@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/auth"
+	"github.com/franciscosbf/spotify-tui/internals/ranks"
+)
+
+const (
+	topLimit    = 50
+	topRowWidth = 38
+)
+
+var topRangeLabels = map[api.TimeRange]string{
+	api.ShortTerm:  "last 4 weeks",
+	api.MediumTerm: "last 6 months",
+	api.LongTerm:   "last year",
+}
+
+var errNoProfile = errors.New("profile not loaded yet")
+
+type topList struct {
+	tracks        []api.Track
+	artists       []api.Artist
+	trackChanges  []ranks.Change
+	artistChanges []ranks.Change
+	trackSince    time.Time
+	artistSince   time.Time
+}
+
+func (m model) topTimeRange() api.TimeRange {
+	return api.TimeRanges[m.topRange]
+}
+
+func (m model) openTop() (model, tea.Cmd) {
+	m.view = top
+	m.topSaved = ""
+
+	if m.topLists == nil {
+		m.topLists = map[api.TimeRange]topList{}
+	}
+
+	if _, ok := m.topLists[m.topTimeRange()]; ok {
+		m.topRows.reset(0, m.topRowCount())
+		return m, nil
+	}
+
+	return m, m.actions.loadTop(ranks.NewStore(m.conf.DataPath("top.json")), m.topTimeRange())
+}
+
+func (m model) topLoaded(msg topLoadedMsg) (model, tea.Cmd) {
+	if msg.err != nil {
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	if m.topLists == nil {
+		return m, nil
+	}
+
+	m.topLists[msg.timeRange] = msg.list
+	if msg.timeRange == m.topTimeRange() {
+		m.topRows.reset(0, m.topRowCount())
+	}
+
+	return m, nil
+}
+
+func (m model) playlistCreated(msg playlistCreatedMsg) (model, tea.Cmd) {
+	m.topSaving = false
+
+	if msg.err != nil {
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	m.topSaved = msg.playlist.Name
+
+	return m, nil
+}
+
+func (m model) topRowCount() int {
+	list := m.topLists[m.topTimeRange()]
+
+	if m.topArtists {
+		return len(list.artists)
+	}
+
+	return len(list.tracks)
+}
+
+func (m model) saveTop() (model, tea.Cmd) {
+	if m.topSaving || m.topRowCount() == 0 {
+		return m, nil
+	}
+
+	if m, ok := m.requireScopes(top, []string{auth.ScopePlaylistModifyPrivate}); !ok {
+		return m, nil
+	}
+
+	if m.profile.Id == "" {
+		return m, func() tea.Msg { return newWarnErrMsg(errNoProfile) }
+	}
+
+	list := m.topLists[m.topTimeRange()]
+	label := topRangeLabels[m.topTimeRange()]
+
+	kind := "tracks"
+	if m.topArtists {
+		kind = "artists"
+	}
+
+	details := api.PlaylistDetails{
+		Name: fmt.Sprintf("Top %s · %s", kind, label),
+		Description: fmt.Sprintf("Your top %s of the %s, saved on %s",
+			kind, label, time.Now().Format("02 Jan 2006")),
+	}
+
+	m.topSaving = true
+	m.topSaved = ""
+
+	if m.topArtists {
+		return m, m.actions.createArtistsPlaylist(m.profile.Id, details, list.artists)
+	}
+
+	return m, m.actions.createTracksPlaylist(m.profile.Id, details, list.tracks)
+}
+
+func (m model) updateTop(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, topKm.quit):
+		return m, tea.Quit
+	case key.Matches(msg, topKm.back):
+		m.view = player
+	case key.Matches(msg, topKm.up):
+		m.topRows.move(-1, m.topRowCount())
+	case key.Matches(msg, topKm.down):
+		m.topRows.move(1, m.topRowCount())
+	case key.Matches(msg, topKm.page):
+		m.topArtists = !m.topArtists
+		m.topSaved = ""
+		m.topRows.reset(0, m.topRowCount())
+	case key.Matches(msg, topKm.period):
+		m.topRange = (m.topRange + 1) % len(api.TimeRanges)
+		return m.openTop()
+	case key.Matches(msg, topKm.playlist):
+		return m.saveTop()
+	case key.Matches(msg, topKm.add):
+		tracks := m.topLists[m.topTimeRange()].tracks
+		if !m.topArtists && m.topRows.cursor < len(tracks) {
+			return m.pickPlaylist(tracks[m.topRows.cursor].Uri)
+		}
+	}
+
+	return m, nil
+}
+
+func formatChange(changes []ranks.Change, i int) string {
+	if i >= len(changes) {
+		return ""
+	}
+
+	switch change := changes[i]; {
+	case change.New:
+		return "new"
+	case change.Delta > 0:
+		return fmt.Sprintf("▲%d", change.Delta)
+	case change.Delta < 0:
+		return fmt.Sprintf("▼%d", -change.Delta)
+	}
+
+	return "="
+}
+
+func formatSince(since time.Time) string {
+	if since.IsZero() {
+		return "first visit, changes show up next time"
+	}
+
+	return fmt.Sprintf("changes since %s", since.Local().Format("02 Jan 15:04"))
+}
+
+func (m model) viewTop() string {
+	kind := "Top tracks"
+	if m.topArtists {
+		kind = "Top artists"
+	}
+
+	title := titleStyle.Render(fmt.Sprintf("%s · %s", kind, topRangeLabels[m.topTimeRange()]))
+
+	list, ok := m.topLists[m.topTimeRange()]
+	if !ok {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Loading..."))
+	}
+
+	if m.topRowCount() == 0 {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Not enough listening yet"))
+	}
+
+	rows := []string{}
+	var summary string
+
+	if m.topArtists {
+		for i, artist := range list.artists {
+			rows = append(rows, fmt.Sprintf("%2d %-4s %s",
+				i+1, formatChange(list.artistChanges, i), truncate(artist.Name, topRowWidth)))
+		}
+		summary = formatSince(list.artistSince)
+	} else {
+		for i, track := range list.tracks {
+			name := track.Name
+			if len(track.Artists) > 0 {
+				name += " - " + track.Artists[0].Name
+			}
+			rows = append(rows, fmt.Sprintf("%2d %-4s %s",
+				i+1, formatChange(list.trackChanges, i), truncate(name, topRowWidth)))
+		}
+		summary = formatSince(list.trackSince)
+	}
+
+	switch {
+	case m.topSaving:
+		summary = countdownStyle.Render("Saving playlist...")
+	case m.topSaved != "":
+		summary = copiedStyle.Render(fmt.Sprintf("Saved as %s", m.topSaved))
+	default:
+		summary = countdownStyle.Render(summary)
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, summary, m.topRows.render(rows))
+}
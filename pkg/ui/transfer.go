@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/internals/api"
+	"github.com/franciscosbf/spotify-tui/internals/playlists"
+)
+
+var statusMarks = map[playlists.Status]string{
+	playlists.Matched:   "✓",
+	playlists.Ambiguous: "?",
+	playlists.Unmatched: "✗",
+}
+
+func expandPath(path string) string {
+	path = strings.TrimSpace(path)
+
+	if rest, found := strings.CutPrefix(path, "~/"); found {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+
+	return path
+}
+
+func exportPath(playlist api.Playlist) string {
+	name := strings.NewReplacer("/", "-", "\\", "-").Replace(playlist.Name)
+
+	return filepath.Join("~", name+"."+playlists.FormatM3u)
+}
+
+func (m model) openTransfer(exporting bool, playlist api.Playlist) (model, tea.Cmd) {
+	m.library.exporting = exporting
+	m.library.transferred = playlist
+	m.library.path = newDetailsInput("~/playlist.m3u8", 1024)
+	m.library.path.Width = 48
+	if exporting {
+		m.library.path.SetValue(exportPath(playlist))
+	}
+
+	m.view = transfer
+
+	return m, m.library.path.Focus()
+}
+
+func (m model) updateTransfer(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, promptKm.back):
+			m.view = library
+			return m, nil
+		case key.Matches(msg, promptKm.enter):
+			path := expandPath(m.library.path.Value())
+			if path == "" {
+				return m, nil
+			}
+
+			if _, err := playlists.FormatFromPath(path); err != nil {
+				return m, func() tea.Msg { return newWarnErrMsg(err) }
+			}
+
+			if m.library.exporting {
+				m.view = library
+				return m, m.actions.exportPlaylist(m.library.transferred, path)
+			}
+
+			m.view = review
+			m.library.matches = nil
+			m.library.resolving = true
+
+			return m, m.actions.importPlaylist(path)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.library.path, cmd = m.library.path.Update(msg)
+
+	return m, cmd
+}
+
+func (m model) importResolved(msg importResolvedMsg) (model, tea.Cmd) {
+	if m.view != review || !m.library.resolving {
+		return m, nil
+	}
+
+	m.library.resolving = false
+
+	if msg.err != nil {
+		m.view = library
+		return m, func() tea.Msg { return newWarnErrMsg(msg.err) }
+	}
+
+	m.library.importName = msg.name
+	m.library.matches = msg.matches
+	m.library.included = make([]bool, len(msg.matches))
+	for i, match := range msg.matches {
+		m.library.included[i] = match.Status == playlists.Matched
+	}
+	m.library.reviewRows.reset(0, len(msg.matches))
+
+	return m, nil
+}
+
+func (m model) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	matches := m.library.matches
+	cursor := m.library.reviewRows.cursor
+
+	switch {
+	case key.Matches(msg, reviewKm.back):
+		m.view = library
+		m.library.resolving = false
+	case m.library.resolving || len(matches) == 0:
+	case key.Matches(msg, reviewKm.up):
+		m.library.reviewRows.move(-1, len(matches))
+	case key.Matches(msg, reviewKm.down):
+		m.library.reviewRows.move(1, len(matches))
+	case key.Matches(msg, reviewKm.include):
+		if matches[cursor].Status != playlists.Unmatched {
+			m.library.included = slices.Clone(m.library.included)
+			m.library.included[cursor] = !m.library.included[cursor]
+		}
+	case key.Matches(msg, reviewKm.candidate):
+		match := matches[cursor]
+		if len(match.Candidates) < 2 {
+			return m, nil
+		}
+
+		next := 0
+		for i, candidate := range match.Candidates {
+			if candidate.Uri == match.Track.Uri {
+				next = (i + 1) % len(match.Candidates)
+			}
+		}
+
+		m.library.matches = slices.Clone(matches)
+		m.library.matches[cursor].Track = match.Candidates[next]
+		m.library.included = slices.Clone(m.library.included)
+		m.library.included[cursor] = true
+	case key.Matches(msg, reviewKm.enter):
+		if m.profile.Id == "" {
+			return m, func() tea.Msg { return newWarnErrMsg(errNoProfile) }
+		}
+
+		uris := []string{}
+		for i, match := range matches {
+			if m.library.included[i] {
+				uris = append(uris, match.Track.Uri)
+			}
+		}
+
+		m.view = library
+		m.library.busy = true
+
+		return m, m.actions.createPlaylist(m.profile.Id,
+			api.PlaylistDetails{Name: m.library.importName},
+			func() ([]string, error) { return uris, nil })
+	}
+
+	return m, nil
+}
+
+func (m model) viewTransfer() string {
+	title := "Import a playlist from"
+	if m.library.exporting {
+		title = fmt.Sprintf("Export %s to", truncate(m.library.transferred.Name, 30))
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s",
+		titleStyle.Render(title),
+		m.library.path.View(),
+		countdownStyle.Render("The extension picks the format: m3u8, csv, json or xspf"))
+}
+
+func (m model) viewReview() string {
+	title := titleStyle.Render(truncate(fmt.Sprintf("Import %s", m.library.importName), libraryRowWidth))
+
+	if m.library.resolving {
+		return fmt.Sprintf("%s\n\n%s", title, awaitStyle.Render("Matching tracks..."))
+	}
+
+	counts := map[playlists.Status]int{}
+	included := 0
+	for i, match := range m.library.matches {
+		counts[match.Status]++
+		if m.library.included[i] {
+			included++
+		}
+	}
+
+	summary := countdownStyle.Render(fmt.Sprintf("%d matched · %d to review · %d not found · %d included",
+		counts[playlists.Matched], counts[playlists.Ambiguous], counts[playlists.Unmatched], included))
+
+	rows := []string{}
+	for i, match := range m.library.matches {
+		mark := statusMarks[match.Status]
+		if match.Status != playlists.Unmatched && !m.library.included[i] {
+			mark = "-"
+		}
+
+		row := match.Entry.String()
+		if match.Status == playlists.Ambiguous {
+			row = fmt.Sprintf("%s → %s", row, playlists.FromTrack(match.Track))
+		}
+
+		rows = append(rows, fmt.Sprintf("%s %s", mark, truncate(row, libraryRowWidth-2)))
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s", title, summary, m.library.reviewRows.render(rows))
+}
@@ -2,14 +2,15 @@ package ui
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscosbf/spotify-tui/pkg/config"
 )
 
 type Tui struct {
 	m model
 }
 
-func New(confLocation string) Tui {
-	m := newModel(confLocation)
+func New(conf *config.Config) Tui {
+	m := newModel(conf)
 
 	return Tui{m}
 }